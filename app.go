@@ -7,12 +7,25 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"time"
+
+	"nous-app/internal/fetcher"
+	"nous-app/internal/nodeclient"
+	"nous-app/internal/search"
+	"nous-app/internal/secrets"
 )
 
 type App struct {
-	ctx      context.Context
-	p2pCmd   *exec.Cmd
-	Location string
+	ctx       context.Context
+	reqCtx    context.Context     // parent of all node requests, cancelled on close
+	reqCancel context.CancelFunc  // cancels reqCtx
+	requests  map[string]*request // in-flight binding calls by request ID
+	p2pCmd    *exec.Cmd
+	fetcher   *fetcher.Fetcher // Go-side source fetcher, shared so rate limits hold
+	scheduler *fetchScheduler  // background source polling, nil when stopped
+	search    *search.Index    // full-text index of local articles
+	secrets   *secrets.Store   // encrypted API keys of sources
+	Location  string
 }
 
 var IDENTITY_ID = "nous-node"
@@ -38,12 +51,13 @@ func NewApp() *App {
 			instanceID = id
 		}
 	}
-	return &App{}
+	return &App{fetcher: newSourceFetcher(), search: newSearchIndex(), secrets: newSecretStore()}
 }
 
 // Startup initializes the Wails app
 func (a *App) Startup(ctx context.Context) {
 	a.ctx = ctx
+	a.reqCtx, a.reqCancel = context.WithCancel(ctx)
 	log.Println("Nous App started")
 
 	// HTTP port per instance
@@ -73,24 +87,68 @@ func (a *App) Startup(ctx context.Context) {
 		identityId = envID
 	}
 
+	// Supervisor crash budget (restarts allowed within P2PCrashWindow)
+	if budgetStr := os.Getenv("P2P_CRASH_BUDGET"); budgetStr != "" {
+		if b, err := strconv.Atoi(budgetStr); err == nil && b >= 0 {
+			P2PCrashBudget = b
+		}
+	}
+
+	// How long to wait for the node's READY handshake
+	if timeoutStr := os.Getenv("P2P_STARTUP_TIMEOUT"); timeoutStr != "" {
+		if secs, err := strconv.Atoi(timeoutStr); err == nil && secs > 0 {
+			P2PStartupTimeout = time.Duration(secs) * time.Second
+		}
+	}
+
+	// Concurrent source fetches
+	if workersStr := os.Getenv("FETCH_WORKERS"); workersStr != "" {
+		if w, err := strconv.Atoi(workersStr); err == nil && w > 0 {
+			FetchWorkers = w
+			a.fetcher.Workers = w
+		}
+	}
+
+	// Background source polling
+	if v := os.Getenv("FETCH_SCHEDULER"); v == "off" || v == "false" || v == "0" {
+		FetchSchedulerEnabled = false
+	}
+	if minsStr := os.Getenv("FETCH_REFRESH_INTERVAL"); minsStr != "" {
+		if mins, err := strconv.Atoi(minsStr); err == nil && mins > 0 {
+			DefaultRefreshInterval = time.Duration(mins) * time.Minute
+		}
+	}
+
 	log.Printf("[Startup] Using config → id:%s http:%d libp2p:%d db:%s keystore:%s blockstore:%s",
 		identityId, httpPortBase+instanceID, libp2pPortBase+instanceID, dbPath, keystorePath, blockstorePath)
 
-	// Start P2P node asynchronously
-	go func() {
-		msg, err := a.StartP2PNode()
-		if err != nil {
-			log.Println("[P2P] Failed to start node:", err)
-		} else {
-			log.Println("[P2P] Node started successfully:", msg)
-		}
-	}()
+	// Migrate sources.json and move plaintext API keys out of it, once
+	sourcesMu.Lock()
+	if err := a.prepareSources(); err != nil {
+		log.Println("[Sources] Failed to prepare sources:", err)
+	}
+	sourcesMu.Unlock()
+
+	// Start P2P node and wait for its READY handshake, so the frontend
+	// (loaded after Startup returns) never races a half-started node
+	msg, err := a.StartP2PNode()
+	if err != nil {
+		log.Println("[P2P] Failed to start node:", err)
+	} else {
+		log.Println("[P2P] Node started successfully:", msg)
+	}
+
+	// Poll sources in the background; rounds pause while the node is down
+	a.startFetchScheduler()
 }
 
 // Fired before the application is closed
 func (a *App) BeforeClose(ctx context.Context) (prevent bool) {
-	a.StopP2PNode() // stop P2P node cleanly
-	return false    // false = allow close
+	a.stopFetchScheduler() // stop background source polling
+	a.cancelRequests()     // abort in-flight node requests
+	a.StopP2PNode()        // stop P2P node cleanly
+	a.flushSearchIndex()   // save pending search index changes
+	return false           // false = allow close
 }
 
 // SetLocation stores user location locally
@@ -111,3 +169,8 @@ func (a *App) GetLocation() string {
 func GetNodeBaseUrl() string {
 	return fmt.Sprintf("%s:%d", BASE_API_URL, instanceHTTPPort())
 }
+
+// nodeClient returns a typed client for this instance's P2P node HTTP API
+func (a *App) nodeClient() *nodeclient.Client {
+	return nodeclient.New(GetNodeBaseUrl())
+}
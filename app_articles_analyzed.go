@@ -2,38 +2,51 @@ package main
 
 import (
 	"fmt"
-	"log"
+
+	"nous-app/internal/models"
 )
 
 // FetchAnalyzedArticles retrieves AI-analyzed articles
 func (a *App) FetchAnalyzedArticles() string {
-	url := fmt.Sprintf("%s/articles/analyzed", GetNodeBaseUrl())
-	body, err := get(url)
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	articles, err := a.nodeClient().AnalyzedArticles(req.ctx)
 	if err != nil {
-		log.Printf("Error fetching analyzed articles: %v", err)
-		return fmt.Sprintf("Error fetching analyzed articles: %v", err)
+		return req.failWith("Error fetching analyzed articles", err)
 	}
-	return body
+	return req.ok(articles)
 }
 
-// SaveAnalyzedArticle stores a new analyzed article via HTTP
+// SaveAnalyzedArticle stores a new analyzed article via HTTP. The article is
+// forwarded as given, so fields the Go types don't know are kept.
 func (a *App) SaveAnalyzedArticle(article map[string]interface{}) string {
-	url := fmt.Sprintf("%s/articles/analyzed/save", GetNodeBaseUrl())
-	body, err := post(url, article)
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	// Decoded only to check it
+	var analyzed ArticleAnalyzed
+	if err := decodeMap(article, &analyzed); err != nil {
+		return req.fail(models.ErrorBadRequest, fmt.Sprintf("Error decoding analyzed article: %v", err))
+	}
+
+	res, err := a.nodeClient().SaveAnalyzedArticle(req.ctx, article)
 	if err != nil {
-		log.Printf("Error saving analyzed article: %v", err)
-		return fmt.Sprintf("Error saving analyzed article: %v", err)
+		return req.failWith("Error saving analyzed article", err)
 	}
-	return body
+	analyzedCache.reset()
+	return req.ok(res)
 }
 
 // DeleteAnalyzedArticle removes an analyzed article by ID
 func (a *App) DeleteAnalyzedArticle(id string) string {
-	url := fmt.Sprintf("%s/articles/analyzed/delete/%s", GetNodeBaseUrl(), id)
-	body, err := get(url)
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	res, err := a.nodeClient().DeleteAnalyzedArticle(req.ctx, id)
 	if err != nil {
-		log.Printf("Error deleting analyzed article: %v", err)
-		return fmt.Sprintf("Error deleting analyzed article: %v", err)
+		return req.failWith("Error deleting analyzed article", err)
 	}
-	return body
+	analyzedCache.reset()
+	return req.ok(res)
 }
@@ -2,38 +2,49 @@ package main
 
 import (
 	"fmt"
-	"log"
+
+	"nous-app/internal/models"
 )
 
 // FetchFederatedArticles retrieves federated articles
 func (a *App) FetchFederatedArticles() string {
-	url := fmt.Sprintf("%s/articles/federated", GetNodeBaseUrl())
-	body, err := get(url)
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	pointers, err := a.nodeClient().FederatedArticles(req.ctx)
 	if err != nil {
-		log.Printf("Error fetching federated articles: %v", err)
-		return fmt.Sprintf("Error fetching federated articles: %v", err)
+		return req.failWith("Error fetching federated articles", err)
 	}
-	return body
+	return req.ok(pointers)
 }
 
 // SaveFederatedArticle stores a new federated article via HTTP
 func (a *App) SaveFederatedArticle(article map[string]interface{}) string {
-	url := fmt.Sprintf("%s/articles/federated/save", GetNodeBaseUrl())
-	body, err := post(url, article)
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	var pointer FederatedArticlePointer
+	if err := decodeMap(article, &pointer); err != nil {
+		return req.fail(models.ErrorBadRequest, fmt.Sprintf("Error decoding federated article: %v", err))
+	}
+
+	res, err := a.nodeClient().SaveFederatedArticle(req.ctx, pointer)
 	if err != nil {
-		log.Printf("Error saving federated article: %v", err)
-		return fmt.Sprintf("Error saving federated article: %v", err)
+		return req.failWith("Error saving federated article", err)
 	}
-	return body
+	federatedCache.reset()
+	return req.ok(res)
 }
 
 // DeleteFederatedArticle removes a federated article by ID
 func (a *App) DeleteFederatedArticle(id string) string {
-	url := fmt.Sprintf("%s/articles/federated/delete/%s", GetNodeBaseUrl(), id)
-	body, err := get(url)
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	res, err := a.nodeClient().DeleteFederatedArticle(req.ctx, id)
 	if err != nil {
-		log.Printf("Error deleting federated article: %v", err)
-		return fmt.Sprintf("Error deleting federated article: %v", err)
+		return req.failWith("Error deleting federated article", err)
 	}
-	return body
+	federatedCache.reset()
+	return req.ok(res)
 }
@@ -1,21 +1,12 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
-)
 
-func failResponse(msg string) string {
-	log.Println(msg)
-	res := APIResponse{
-		Success: false,
-		Error:   msg,
-		Data:    nil,
-	}
-	resJSON, _ := json.Marshal(res)
-	return string(resJSON)
-}
+	"nous-app/internal/models"
+	"nous-app/internal/nodeclient"
+)
 
 // Helper to convert identifiers to []string without validation
 func identifiersToStrings(identifiers interface{}) []string {
@@ -39,7 +30,10 @@ func identifiersToStrings(identifiers interface{}) []string {
 	return result
 }
 
-func (a *App) TranslateArticle(identifiers interface{}, targetLanguage string, keys []string, overwrite bool) string {
+func (a *App) TranslateArticle(identifiers interface{}, targetLanguage string, keys []string, overwrite bool, requestID string) string {
+	req := a.beginRequest(requestID, LongRequestTimeout)
+	defer req.end()
+
 	// Force keys default if nil/empty
 	if len(keys) == 0 {
 		keys = []string{"title"}
@@ -53,92 +47,96 @@ func (a *App) TranslateArticle(identifiers interface{}, targetLanguage string, k
 		Overwrite:      overwrite,
 	}
 
-	url := fmt.Sprintf("%s/articles/local/translate", GetNodeBaseUrl())
-	body, err := post(url, reqBody)
-	if err != nil {
-		log.Printf("Error translating articles: %v", err)
-		return failResponse(fmt.Sprintf("Error translating articles: %v", err))
+	if len(reqBody.Identifiers) == 0 || targetLanguage == "" {
+		return req.fail(models.ErrorBadRequest, "Error translating articles: identifiers and target language are required")
 	}
 
-	var res APIResponse
-	if err := json.Unmarshal([]byte(body), &res); err != nil {
-		log.Printf("Error parsing translation response: %v", err)
-		return failResponse(fmt.Sprintf("Error parsing translation response: %v", err))
+	articles, err := a.nodeClient().TranslateArticles(req.ctx, reqBody)
+	if err != nil {
+		return req.failWith("Error translating articles", err)
 	}
-
-	resJSON, _ := json.Marshal(res)
-	return string(resJSON)
+	return req.ok(articles)
 }
 
-// FetchLocalArticle fetches by ID/URL/CID and returns immediately
+// FetchLocalArticle fetches by ID/URL/CID and returns immediately.
+// The data is an ArticleStatus: "pending" until the node has the full content.
 func (a *App) FetchLocalArticle(idOrCIDOrURL string) string {
-	// Call your internal fetch, e.g., database or cache
-	url := fmt.Sprintf("%s/articles/local/full?id=%s", GetNodeBaseUrl(), idOrCIDOrURL)
-	body, err := get(url)
-	if err != nil {
-		log.Printf("Error fetching local article: %v", err)
-		status := ArticleStatus{
-			ID:       idOrCIDOrURL,
-			Status:   "error",
-			ErrorMsg: err.Error(),
-		}
-		res, _ := json.Marshal(status)
-		return string(res)
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	article, err := a.nodeClient().LocalArticle(req.ctx, idOrCIDOrURL)
+	if err != nil && !nodeclient.IsNotFound(err) {
+		return req.failWith("Error fetching local article", err)
 	}
 
-	// Check if processing is complete
-	var article map[string]interface{}
-	if err := json.Unmarshal([]byte(body), &article); err != nil || article["content"] == nil {
-		// Article not fully processed
-		status := ArticleStatus{
+	// Not stored yet, or stored without content: still being processed. A
+	// stored article without content has its page extracted in the background
+	// (unless it was already analyzed, as saving it back would drop the analysis).
+	if article == nil || article.Content == nil {
+		if article != nil && !article.Analyzed {
+			a.extractInBackground(article.Article)
+		}
+		return req.ok(ArticleStatus{
 			ID:     idOrCIDOrURL,
 			Status: "pending",
-		}
-		res, _ := json.Marshal(status)
-		return string(res)
+		})
 	}
 
 	// Fully processed
-	status := ArticleStatus{
-		ID:     idOrCIDOrURL,
-		Status: "complete",
-		Body:   body,
-	}
-	res, _ := json.Marshal(status)
-	return string(res)
+	return req.ok(ArticleStatus{
+		ID:      idOrCIDOrURL,
+		Status:  "complete",
+		Article: article,
+	})
 }
 
 // FetchLocalArticles retrieves only local articles from the HTTP service
 func (a *App) FetchLocalArticles() string {
-	url := fmt.Sprintf("%s/articles/local", GetNodeBaseUrl())
-	body, err := get(url)
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	articles, err := a.nodeClient().LocalArticles(req.ctx)
 	if err != nil {
-		log.Printf("Error fetching local articles: %v", err)
-		return fmt.Sprintf("Error fetching local articles: %v", err)
+		return req.failWith("Error fetching local articles", err)
 	}
-	return body
+	return req.ok(articles)
 }
 
-// SaveLocalArticle stores a new local article via HTTP, optionally overwriting existing articles
+// SaveLocalArticle stores a new local article via HTTP, optionally overwriting existing articles.
+// The article is forwarded as given, so fields the Go types don't know are kept.
 func (a *App) SaveLocalArticle(article map[string]interface{}, overwrite bool) string {
-	// Add overwrite flag as a query param
-	url := fmt.Sprintf("%s/articles/local/save?overwrite=%t", GetNodeBaseUrl(), overwrite)
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
 
-	body, err := post(url, article)
+	// Decoded only to check it and to index it
+	var local Article
+	if err := decodeMap(article, &local); err != nil {
+		return req.fail(models.ErrorBadRequest, fmt.Sprintf("Error decoding local article: %v", err))
+	}
+
+	res, err := a.nodeClient().SaveLocalArticle(req.ctx, article, overwrite)
 	if err != nil {
-		log.Printf("Error saving local article: %v", err)
-		return fmt.Sprintf("Error saving local article: %v", err)
+		return req.failWith("Error saving local article", err)
+	}
+	localCache.reset()
+	if res.Success {
+		a.indexArticle(local)
 	}
-	return body
+	return req.ok(res)
 }
 
 // DeleteLocalArticle removes a local article by ID
 func (a *App) DeleteLocalArticle(id string) string {
-	url := fmt.Sprintf("%s/articles/local/delete/%s", GetNodeBaseUrl(), id)
-	body, err := get(url)
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	res, err := a.nodeClient().DeleteLocalArticle(req.ctx, id)
 	if err != nil {
-		log.Printf("Error deleting local article: %v", err)
-		return fmt.Sprintf("Error deleting local article: %v", err)
+		return req.failWith("Error deleting local article", err)
+	}
+	localCache.reset()
+	if res.Success {
+		a.unindexArticle(id)
 	}
-	return body
+	return req.ok(res)
 }
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"nous-app/internal/models"
+	"nous-app/internal/query"
+)
+
+// EventArticlesPage carries one page of a StreamArticles call.
+const EventArticlesPage = "articles:page"
+
+// ArticlesPage is the data of the paged listing bindings. Total is the size
+// of the whole store when the page was cut, a hint for progress and
+// scrollbars; it may change between pages.
+type ArticlesPage struct {
+	Items      interface{} `json:"items"`
+	Total      int         `json:"total"`
+	NextCursor string      `json:"nextCursor,omitempty"` // Empty on the last page
+
+	count int // len(Items)
+}
+
+// ArticlesPageEvent is the payload of the "articles:page" event.
+type ArticlesPageEvent struct {
+	RequestID string `json:"requestId"` // The StreamArticles call the page belongs to
+	Store     string `json:"store"`
+	Page      int    `json:"page"` // 1-based
+	ArticlesPage
+	Done bool `json:"done"` // Last page of the stream
+}
+
+// StreamSummary is the data StreamArticles returns once every page is sent.
+type StreamSummary struct {
+	Pages int `json:"pages"`
+	Items int `json:"items"`
+}
+
+// FetchLocalArticlesPage returns one page of the local articles, newest
+// first by publishedAt unless page.Sort ("publishedAt" or "fetchedAt") and
+// page.Ascending say otherwise. Pass the returned nextCursor to get the next
+// page; a slow read can be cancelled with CancelRequest(requestID).
+func (a *App) FetchLocalArticlesPage(page query.PageRequest, requestID string) string {
+	req := a.beginRequest(requestID, DefaultRequestTimeout)
+	defer req.end()
+
+	res, err := a.articlesPage(req.ctx, query.StoreLocal, page)
+	if err != nil {
+		return pageFailure(req, "Error fetching local articles", err)
+	}
+	return req.ok(res)
+}
+
+// FetchAnalyzedArticlesPage returns one page of the analyzed articles; like
+// FetchLocalArticlesPage, and page.Sort may also be "analysisTimestamp".
+func (a *App) FetchAnalyzedArticlesPage(page query.PageRequest, requestID string) string {
+	req := a.beginRequest(requestID, DefaultRequestTimeout)
+	defer req.end()
+
+	res, err := a.articlesPage(req.ctx, query.StoreAnalyzed, page)
+	if err != nil {
+		return pageFailure(req, "Error fetching analyzed articles", err)
+	}
+	return req.ok(res)
+}
+
+// FetchFederatedArticlesPage returns one page of the federated article
+// pointers. Pointers carry a single timestamp, so "publishedAt" and
+// "fetchedAt" both sort by it.
+func (a *App) FetchFederatedArticlesPage(page query.PageRequest, requestID string) string {
+	req := a.beginRequest(requestID, DefaultRequestTimeout)
+	defer req.end()
+
+	res, err := a.articlesPage(req.ctx, query.StoreFederated, page)
+	if err != nil {
+		return pageFailure(req, "Error fetching federated articles", err)
+	}
+	return req.ok(res)
+}
+
+// StreamArticles sends a whole store ("local", "analyzed" or "federated") to
+// the frontend page by page as "articles:page" events, starting at
+// page.Cursor, so the first articles show while the rest are still on their
+// way. It returns a StreamSummary after the last page; CancelRequest with the
+// same requestID stops the stream between pages.
+func (a *App) StreamArticles(store string, page query.PageRequest, requestID string) string {
+	req := a.beginRequest(requestID, LongRequestTimeout)
+	defer req.end()
+
+	var sum StreamSummary
+	for {
+		if err := req.ctx.Err(); err != nil {
+			return req.failWith("Error streaming articles", err)
+		}
+		res, err := a.articlesPage(req.ctx, store, page)
+		if err != nil {
+			return pageFailure(req, "Error streaming articles", err)
+		}
+		sum.Pages++
+		sum.Items += res.count
+		a.emitEvent(EventArticlesPage, ArticlesPageEvent{
+			RequestID:    req.ID,
+			Store:        store,
+			Page:         sum.Pages,
+			ArticlesPage: res,
+			Done:         res.NextCursor == "",
+		})
+		if res.NextCursor == "" {
+			return req.ok(sum)
+		}
+		page.Cursor = res.NextCursor
+	}
+}
+
+// errUnknownStore and errBadSort are request errors of articlesPage.
+var (
+	errUnknownStore = errors.New("unknown store")
+	errBadSort      = errors.New("unsupported sort")
+)
+
+// storeSorts lists the sort keys each store supports.
+var storeSorts = map[string][]string{
+	query.StoreLocal:     {query.SortPublishedAt, query.SortFetchedAt},
+	query.StoreAnalyzed:  {query.SortPublishedAt, query.SortFetchedAt, query.SortAnalysisTimestamp},
+	query.StoreFederated: {query.SortPublishedAt, query.SortFetchedAt},
+}
+
+// articlesPage cuts one page of a store.
+func (a *App) articlesPage(ctx context.Context, store string, page query.PageRequest) (ArticlesPage, error) {
+	if page.Sort == "" {
+		page.Sort = query.SortPublishedAt
+	}
+	sorts, ok := storeSorts[store]
+	if !ok {
+		return ArticlesPage{}, fmt.Errorf("%w %q", errUnknownStore, store)
+	}
+	if !slices.Contains(sorts, page.Sort) {
+		return ArticlesPage{}, fmt.Errorf("%w %q for %s articles", errBadSort, page.Sort, store)
+	}
+
+	switch store {
+	case query.StoreLocal:
+		articles, err := a.cachedLocalArticles(ctx)
+		if err != nil {
+			return ArticlesPage{}, err
+		}
+		l, err := query.Paginate(len(articles), func(i int) (time.Time, string) {
+			return articleSortTime(articles[i], page.Sort), models.ArticleKey(articles[i].ID, articles[i].URL)
+		}, page)
+		if err != nil {
+			return ArticlesPage{}, err
+		}
+		items := make([]models.Article, 0, len(l.Indices))
+		for _, i := range l.Indices {
+			items = append(items, articles[i])
+		}
+		return ArticlesPage{Items: items, Total: l.Total, NextCursor: l.NextCursor, count: len(items)}, nil
+
+	case query.StoreAnalyzed:
+		articles, err := a.cachedAnalyzedArticles(ctx)
+		if err != nil {
+			return ArticlesPage{}, err
+		}
+		l, err := query.Paginate(len(articles), func(i int) (time.Time, string) {
+			if page.Sort == query.SortAnalysisTimestamp {
+				return query.ParseTime(articles[i].AnalysisTimestamp), models.ArticleKey(articles[i].ID, articles[i].URL)
+			}
+			return articleSortTime(articles[i].Article, page.Sort), models.ArticleKey(articles[i].ID, articles[i].URL)
+		}, page)
+		if err != nil {
+			return ArticlesPage{}, err
+		}
+		items := make([]models.ArticleAnalyzed, 0, len(l.Indices))
+		for _, i := range l.Indices {
+			items = append(items, articles[i])
+		}
+		return ArticlesPage{Items: items, Total: l.Total, NextCursor: l.NextCursor, count: len(items)}, nil
+
+	default:
+		pointers, err := a.cachedFederatedArticles(ctx)
+		if err != nil {
+			return ArticlesPage{}, err
+		}
+		l, err := query.Paginate(len(pointers), func(i int) (time.Time, string) {
+			return query.ParseTime(&pointers[i].Timestamp), pointers[i].CID
+		}, page)
+		if err != nil {
+			return ArticlesPage{}, err
+		}
+		items := make([]models.FederatedArticlePointer, 0, len(l.Indices))
+		for _, i := range l.Indices {
+			items = append(items, pointers[i])
+		}
+		return ArticlesPage{Items: items, Total: l.Total, NextCursor: l.NextCursor, count: len(items)}, nil
+	}
+}
+
+// articleSortTime returns the time an article sorts by under a listing sort.
+func articleSortTime(a models.Article, sort string) time.Time {
+	if sort == query.SortFetchedAt {
+		return query.ParseTime(a.FetchedAt)
+	}
+	return query.ParseTime(a.PublishedAt)
+}
+
+// pageFailure reports an articlesPage error: bad cursors, sorts and stores
+// are the caller's mistake, anything else comes from the node.
+func pageFailure(req *request, op string, err error) string {
+	if errors.Is(err, query.ErrBadCursor) || errors.Is(err, errBadSort) || errors.Is(err, errUnknownStore) {
+		return req.fail(models.ErrorBadRequest, fmt.Sprintf("%s: %v", op, err))
+	}
+	return req.failWith(op, err)
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"nous-app/internal/cluster"
+	"nous-app/internal/models"
+)
+
+// DefaultClusterPeriod is how far back GetStoryClusters looks when since is
+// empty.
+const DefaultClusterPeriod = 24 * time.Hour
+
+// GetStoryClusters groups the locally stored articles published after since
+// into stories and returns them as StoryClusters, most widely covered first.
+// since is an RFC3339 time or a duration back from now ("48h"); empty means
+// DefaultClusterPeriod. edition limits the articles to one edition ("us",
+// "uk", ...); empty or "all" keeps every edition. Coverage is computed from
+// each source's Bias, taken from the article's source metadata or, failing
+// that, from the source of the same name in sources.json.
+func (a *App) GetStoryClusters(since string, edition string) string {
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	from, err := parseSince(since, DefaultClusterPeriod)
+	if err != nil {
+		return req.fail(models.ErrorBadRequest, fmt.Sprintf("Error clustering stories: %v", err))
+	}
+
+	clusters, err := a.storyClusters(req.ctx, from, time.Time{}, edition)
+	if err != nil {
+		return req.failWith("Error fetching local articles", err)
+	}
+	return req.ok(clusters)
+}
+
+// BlindspotQuery selects the stories GetBlindspotReport considers.
+type BlindspotQuery struct {
+	Since      string  `json:"since,omitempty"`      // RFC3339 time or duration back from now; default DefaultClusterPeriod
+	Until      string  `json:"until,omitempty"`      // RFC3339 time; default now
+	Edition    string  `json:"edition,omitempty"`    // Empty or "all" for every edition
+	Threshold  float64 `json:"threshold,omitempty"`  // Share (0-100) below which a side misses a story; default 10
+	MinSources int     `json:"minSources,omitempty"` // Sources of known bias a story needs; default 3
+	Limit      int     `json:"limit,omitempty"`      // Maximum blindspots returned; 0 for all
+}
+
+// BlindspotReport is the data of GetBlindspotReport.
+type BlindspotReport struct {
+	Since      string              `json:"since"`      // RFC3339 start of the period
+	Until      string              `json:"until"`      // RFC3339 end of the period
+	Stories    int                 `json:"stories"`    // Clusters considered
+	Blindspots []cluster.Blindspot `json:"blindspots"` // Most widely covered first
+}
+
+// GetBlindspotReport clusters the local articles of the query's period and
+// edition and lists the stories that the left or the right barely covers:
+// those where that side's share of the sources of known bias is below the
+// threshold while the other side's is not. Source bias comes from the
+// article's SourceMeta.Bias or the Bias of the source in sources.json.
+func (a *App) GetBlindspotReport(query BlindspotQuery) string {
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	from, err := parseSince(query.Since, DefaultClusterPeriod)
+	if err != nil {
+		return req.fail(models.ErrorBadRequest, fmt.Sprintf("Error building blindspot report: %v", err))
+	}
+	until := time.Now()
+	if query.Until != "" {
+		if until, err = time.Parse(time.RFC3339, query.Until); err != nil {
+			return req.fail(models.ErrorBadRequest, fmt.Sprintf("Error building blindspot report: invalid until %q", query.Until))
+		}
+	}
+	if query.Threshold < 0 || query.Threshold > 100 {
+		return req.fail(models.ErrorBadRequest, "Threshold must be between 0 and 100")
+	}
+
+	clusters, err := a.storyClusters(req.ctx, from, until, query.Edition)
+	if err != nil {
+		return req.failWith("Error fetching local articles", err)
+	}
+
+	blindspots := cluster.Blindspots(clusters, cluster.BlindspotOptions{
+		Threshold:  query.Threshold,
+		MinSources: query.MinSources,
+	})
+	if query.Limit > 0 && len(blindspots) > query.Limit {
+		blindspots = blindspots[:query.Limit]
+	}
+	return req.ok(BlindspotReport{
+		Since:      from.UTC().Format(time.RFC3339),
+		Until:      until.UTC().Format(time.RFC3339),
+		Stories:    len(clusters),
+		Blindspots: blindspots,
+	})
+}
+
+// storyClusters clusters the local articles of an edition published between
+// from and until (a zero until means no upper bound).
+func (a *App) storyClusters(ctx context.Context, from, until time.Time, edition string) ([]StoryCluster, error) {
+	articles, err := a.nodeClient().LocalArticles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := articles[:0]
+	for _, article := range articles {
+		if !matchesEdition(article, edition) {
+			continue
+		}
+		t := cluster.ArticleTime(article)
+		if t.IsZero() || t.Before(from) || !until.IsZero() && t.After(until) {
+			continue
+		}
+		selected = append(selected, article)
+	}
+	return cluster.Build(selected, cluster.Options{Bias: a.sourceBiases()}), nil
+}
+
+// parseSince reads an RFC3339 time or a duration back from now.
+func parseSince(since string, fallback time.Duration) (time.Time, error) {
+	since = strings.TrimSpace(since)
+	if since == "" {
+		return time.Now().Add(-fallback), nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(since)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid since %q: want an RFC3339 time or a duration like \"48h\"", since)
+	}
+	return time.Now().Add(-d), nil
+}
+
+func matchesEdition(article Article, edition string) bool {
+	if edition == "" || strings.EqualFold(edition, "all") {
+		return true
+	}
+	return article.Edition != nil && strings.EqualFold(string(*article.Edition), edition)
+}
+
+// sourceBiases returns a lookup of the Bias of each configured source by
+// name, for articles that do not carry their source's bias.
+func (a *App) sourceBiases() func(string) string {
+	sources, _ := a.loadSources()
+	biases := make(map[string]string, len(sources))
+	for _, s := range sources {
+		if s.Bias != "" {
+			biases[strings.ToLower(s.Name)] = s.Bias
+		}
+	}
+	return func(name string) string {
+		return biases[strings.ToLower(name)]
+	}
+}
@@ -1,49 +1,35 @@
 package main
 
-import (
-	"encoding/json"
-	"fmt"
-	"log"
-)
+import "nous-app/internal/models"
 
 // FetchDebugLogs calls GET /debug/logs
 func (a *App) FetchDebugLogs() string {
-	url := fmt.Sprintf("%s/debug/logs", GetNodeBaseUrl())
-	body, err := get(url)
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
 
+	entries, err := a.nodeClient().DebugLogs(req.ctx)
 	if err != nil {
-		log.Printf("Error fetching debug logs: %v", err)
-
-		resp := APIResponse{
-			Success: false,
-			Error:   err.Error(),
-			Data:    []interface{}{}, // always valid JSON array
-		}
-		jsonBytes, _ := json.Marshal(resp)
-		return string(jsonBytes)
-	}
-
-	// Wrap the body inside APIResponse
-	var parsed interface{}
-	if err := json.Unmarshal([]byte(body), &parsed); err != nil {
-		parsed = []interface{}{}
+		return req.failWith("Error fetching debug logs", err)
 	}
 
-	resp := APIResponse{
-		Success: true,
-		Data:    parsed,
+	if entries == nil {
+		entries = []DebugLogEntry{} // always valid JSON array
 	}
-	jsonBytes, _ := json.Marshal(resp)
-	return string(jsonBytes)
+	return req.ok(entries)
 }
 
 // AddDebugLog calls POST /debug/log with a full DebugLogEntry
 func (a *App) AddDebugLog(entry DebugLogEntry) string {
-	url := fmt.Sprintf("%s/debug/log", GetNodeBaseUrl())
-	body, err := post(url, entry)
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	if entry.Message == "" {
+		return req.fail(models.ErrorBadRequest, "Error adding debug log: missing log message")
+	}
+
+	stored, err := a.nodeClient().AddDebugLog(req.ctx, entry)
 	if err != nil {
-		log.Printf("Error adding debug log: %v", err)
-		return fmt.Sprintf("Error adding debug log: %v", err)
+		return req.failWith("Error adding debug log", err)
 	}
-	return body
+	return req.ok(stored)
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"nous-app/internal/dedup"
+	"nous-app/internal/fetcher"
+	"nous-app/internal/models"
+)
+
+// canonicalResolver is shared by every DeduplicateArticles call so a page's
+// canonical URL is fetched once a day at most. Its cache is bounded (see
+// dedup.Resolver).
+var canonicalResolver = func() *dedup.Resolver {
+	r := dedup.NewResolver()
+	r.Headers = map[string]string{"User-Agent": fetcher.DefaultUserAgent}
+	return r
+}()
+
+// DeduplicateArticles canonicalises the articles' URLs (dropping tracking
+// parameters, AMP variants and redirect wrappers), gives each a stable ID
+// derived from its canonical URL and merges duplicates across sources. Each
+// merged article lists every contributing source in Sources. With
+// resolveCanonical set, each page is also fetched to follow redirects and its
+// rel=canonical link, which catches syndicated copies but costs one request
+// per article.
+func (a *App) DeduplicateArticles(articles []Article, resolveCanonical bool, requestID string) string {
+	timeout := DefaultRequestTimeout
+	if resolveCanonical {
+		timeout = LongRequestTimeout
+	}
+	req := a.beginRequest(requestID, timeout)
+	defer req.end()
+
+	if resolveCanonical {
+		canonicalResolver.ResolveAll(req.ctx, articles)
+		if err := req.ctx.Err(); err != nil {
+			return req.failWith("Error resolving canonical URLs", err)
+		}
+	}
+	return req.ok(dedup.Merge(articles))
+}
+
+// nearDuplicates holds the fingerprints of recently seen articles, fed by
+// FetchSourceArticles and FindNearDuplicates, so syndicated copies are
+// recognised across fetches.
+var nearDuplicates = dedup.NewIndex()
+
+// NearDuplicateResult is the data of FindNearDuplicates.
+type NearDuplicateResult struct {
+	Articles []Article     `json:"articles"` // Input articles, flagged or merged
+	Groups   []dedup.Group `json:"groups"`   // Similarity groups involving the input
+	Indexed  int           `json:"indexed"`  // Fingerprints in the recent index
+}
+
+// FindNearDuplicates fingerprints each article's Title and Content with
+// SimHash and groups articles whose similarity (0-1, the share of matching
+// fingerprint bits) is at least threshold, matching against each other and
+// against recently seen articles. A threshold of 0 uses the default (0.9).
+// With merge set, each group is folded into a single article listing every
+// source, as DeduplicateArticles does for exact URL matches; otherwise copies
+// are only flagged with DuplicateOf. Articles with little text (a headline
+// without content or summary) are not fingerprinted.
+func (a *App) FindNearDuplicates(articles []Article, threshold float64, merge bool, requestID string) string {
+	req := a.beginRequest(requestID, DefaultRequestTimeout)
+	defer req.end()
+
+	if threshold < 0 || threshold > 1 {
+		return req.fail(models.ErrorBadRequest, "Threshold must be between 0 and 1")
+	}
+
+	if merge {
+		articles = dedup.Merge(articles)
+	} else {
+		for i := range articles {
+			dedup.Normalize(&articles[i])
+		}
+	}
+	groups := nearDuplicates.Group(articles, threshold)
+	if merge {
+		articles = dedup.MergeGroups(articles, groups)
+	} else {
+		dedup.Flag(articles, groups)
+	}
+	if groups == nil {
+		groups = []dedup.Group{}
+	}
+	return req.ok(NearDuplicateResult{
+		Articles: articles,
+		Groups:   groups,
+		Indexed:  nearDuplicates.Len(),
+	})
+}
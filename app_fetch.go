@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"nous-app/internal/dedup"
+	"nous-app/internal/fetcher"
+	"nous-app/internal/hn"
+	"nous-app/internal/models"
+	"nous-app/internal/parser"
+	"nous-app/internal/reddit"
+)
+
+// FetchWorkers is the number of sources fetched concurrently by the Go
+// fetcher (env FETCH_WORKERS).
+var FetchWorkers = fetcher.DefaultWorkers
+
+// HNStoryLimit is how many stories an "hn" source reads per fetch, and
+// RedditMaxPages how many listing pages a "reddit" source follows.
+var (
+	HNStoryLimit   = hn.DefaultLimit
+	RedditMaxPages = 4
+)
+
+// newSourceFetcher creates the app's fetcher with its HTTP cache (ETag,
+// Last-Modified, max-age and Retry-After per source) persisted next to
+// sources.json.
+func newSourceFetcher() *fetcher.Fetcher {
+	f := fetcher.New(FetchWorkers)
+
+	cache, err := fetcher.NewCache(fmt.Sprintf("%s/sources.cache.json", DATA_PATH))
+	if err != nil {
+		log.Println("[Fetch] Starting with an empty fetch cache:", err)
+	}
+	f.Cache = cache
+	return f
+}
+
+// FetchSourceArticles fetches a single source from Go and parses it into
+// Articles. "hn" sources fan out over the Firebase item endpoint and "reddit"
+// sources follow the listing's "after" cursor; every other parser goes through
+// the regular fetcher, so a source whose payload has not changed returns no
+// articles. Results go through dedup.Merge, so URLs are canonical and IDs
+// stable across sources, and near copies of recently fetched articles are
+// flagged with DuplicateOf.
+func (a *App) FetchSourceArticles(source Source, requestID string) string {
+	req := a.beginRequest(requestID, LongRequestTimeout)
+	defer req.end()
+
+	source = a.withSecret(source)
+
+	var articles []Article
+	var err error
+	switch source.Parser {
+	case "hn":
+		articles, err = hn.FetchSource(req.ctx, a.fetcher, source, HNStoryLimit)
+	case "reddit":
+		articles, err = reddit.FetchSource(req.ctx, a.fetcher, source, RedditMaxPages)
+	default:
+		res := a.fetcher.FetchOne(req.ctx, source)
+		switch {
+		case res.Meta.Error != "":
+			err = fmt.Errorf("%s", res.Meta.Error)
+		case res.Meta.NotModified:
+			articles = []Article{}
+		default:
+			articles, err = parser.Parse(res.Body, source)
+		}
+	}
+	if err != nil {
+		if req.ctx.Err() != nil {
+			return req.failWith("Error fetching source", req.ctx.Err())
+		}
+		if errors.Is(err, parser.ErrUnsupported) {
+			return req.fail(models.ErrorBadRequest, err.Error())
+		}
+		return req.fail(models.ErrorUpstream, fmt.Sprintf("Error fetching %s: %v", source.Name, err))
+	}
+	return req.ok(mergeFetched(articles))
+}
+
+// mergeFetched merges duplicate articles of one fetch and flags near copies
+// of recently fetched articles with DuplicateOf.
+func mergeFetched(articles []Article) []Article {
+	articles = dedup.Merge(articles)
+	dedup.Flag(articles, nearDuplicates.Group(articles, dedup.DefaultNearThreshold))
+	return articles
+}
+
+// ----------------------
+// Fetch and store
+// ----------------------
+
+// sourceArticles is the outcome of fetching and parsing one source.
+type sourceArticles struct {
+	Source   Source
+	Articles []Article // Parsed and merged; nil if the source failed or has not changed
+	Meta     FetchMeta // Error is set if fetching or parsing failed
+}
+
+// fetchArticles fetches sources from Go and parses their payloads, streaming
+// one result per enabled source as it completes. "hn" and "reddit" sources go
+// through their adapters, the others through the shared fetcher, so they are
+// conditional; all of them are rate limited by the shared fetcher. Keys must already be filled in (withSecrets).
+func (a *App) fetchArticles(ctx context.Context, sources []Source) <-chan sourceArticles {
+	out := make(chan sourceArticles)
+
+	var regular, adapted []Source
+	for _, src := range sources {
+		switch {
+		case src.Enabled != nil && !*src.Enabled:
+		case src.Parser == "hn" || src.Parser == "reddit":
+			adapted = append(adapted, src)
+		default:
+			regular = append(regular, src)
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for r := range a.fetcher.Fetch(ctx, regular) {
+			out <- parseFetched(r)
+		}
+	}()
+	for _, src := range adapted {
+		wg.Add(1)
+		go func(src Source) {
+			defer wg.Done()
+			out <- a.fetchAdapted(ctx, src)
+		}(src)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// parseFetched parses the payload of a fetcher result into articles.
+func parseFetched(r fetcher.Result) sourceArticles {
+	res := sourceArticles{Source: r.Source, Meta: r.Meta}
+	if r.Meta.Error != "" || r.Body == nil {
+		return res
+	}
+	articles, err := parser.Parse(r.Body, r.Source)
+	if err != nil {
+		res.Meta.Error = fmt.Sprintf("failed to parse payload: %v", err)
+		log.Printf("[Fetch] %s: %s\n", r.Source.Name, res.Meta.Error)
+		return res
+	}
+	res.Articles = mergeFetched(articles)
+	return res
+}
+
+// fetchAdapted fetches an "hn" or "reddit" source through its adapter, which
+// makes its requests through the shared fetcher's rate limiter.
+func (a *App) fetchAdapted(ctx context.Context, src Source) sourceArticles {
+	start := time.Now()
+	var articles []Article
+	var err error
+	if src.Parser == "hn" {
+		articles, err = hn.FetchSource(ctx, a.fetcher, src, HNStoryLimit)
+	} else {
+		articles, err = reddit.FetchSource(ctx, a.fetcher, src, RedditMaxPages)
+	}
+
+	res := sourceArticles{Source: src, Meta: FetchMeta{
+		DurationMs: time.Since(start).Milliseconds(),
+		FetchedAt:  start.UTC().Format(time.RFC3339),
+	}}
+	if err != nil {
+		res.Meta.Error = err.Error()
+		log.Printf("[Fetch] %s: %v\n", src.Name, err)
+		return res
+	}
+	res.Articles = mergeFetched(articles)
+	return res
+}
+
+// storeFetched stores the articles of a fetched source and reports whether
+// the source is done. A failure is recorded in r.Meta; if a payload arrived
+// but its items were not stored, the source's cache validators are dropped
+// so the next fetch downloads them again instead of getting a 304.
+func (a *App) storeFetched(ctx context.Context, r *sourceArticles) bool {
+	if r.Meta.Error == "" {
+		if err := a.storeArticles(ctx, r.Articles); err != nil {
+			r.Meta.Error = fmt.Sprintf("failed to store articles: %v", err)
+			log.Printf("[Fetch] %s: %s\n", r.Source.Name, r.Meta.Error)
+		}
+	}
+	if r.Meta.Error == "" {
+		return true
+	}
+	if r.Meta.StatusCode >= 200 && r.Meta.StatusCode < 300 {
+		a.fetcher.Cache.Forget(r.Source.Name)
+	}
+	return false
+}
+
+// storeArticles adds fetched articles to the node's local store, skipping
+// those it already has, and indexes them for search.
+func (a *App) storeArticles(ctx context.Context, articles []Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	added, err := a.nodeClient().AddLocalArticles(ctx, articles)
+	if err != nil {
+		return err
+	}
+	localCache.reset()
+	a.indexArticles(articles)
+	if added > 0 {
+		log.Printf("[Fetch] Stored %d new article(s)\n", added)
+	}
+	return nil
+}
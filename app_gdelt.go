@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"nous-app/internal/fetcher"
+	"nous-app/internal/gdelt"
+	"nous-app/internal/models"
+)
+
+// GDELTMaxPages caps how many pages SearchGDELT fetches per call. Pages are
+// spaced gdelt.DefaultMinInterval apart, so this also bounds the call time.
+const GDELTMaxPages = 10
+
+// BuildGDELTEndpoint returns the DOC 2.0 API URL for a query, for use as the
+// Endpoint of a "gdelt" Source.
+func (a *App) BuildGDELTEndpoint(query gdelt.Query) string {
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	if err := query.Validate(); err != nil {
+		return req.fail(models.ErrorBadRequest, err.Error())
+	}
+	return req.ok(query.URL(""))
+}
+
+// SearchGDELT runs a GDELT query, paging back through up to maxPages pages
+// (capped at GDELTMaxPages), and returns the results as Articles.
+func (a *App) SearchGDELT(query gdelt.Query, maxPages int, requestID string) string {
+	req := a.beginRequest(requestID, LongRequestTimeout)
+	defer req.end()
+
+	if err := query.Validate(); err != nil {
+		return req.fail(models.ErrorBadRequest, err.Error())
+	}
+	if maxPages > GDELTMaxPages {
+		maxPages = GDELTMaxPages
+	}
+
+	client := gdelt.New()
+	client.UserAgent = fetcher.DefaultUserAgent
+
+	items, err := client.Search(req.ctx, query, maxPages)
+	if err != nil {
+		if req.ctx.Err() != nil {
+			return req.failWith("Error searching GDELT", req.ctx.Err())
+		}
+		return req.fail(models.ErrorUpstream, fmt.Sprintf("Error searching GDELT: %v", err))
+	}
+
+	src := Source{Name: "GDELT", Parser: "gdelt", Normalizer: "gdelt"}
+	return req.ok(gdelt.ToArticles(items, src))
+}
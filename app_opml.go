@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"nous-app/internal/models"
+	"nous-app/internal/opml"
+	"nous-app/internal/persist"
+	"nous-app/internal/sourcefile"
+)
+
+// OPMLImport is the data of ImportOPML.
+type OPMLImport struct {
+	Added   []string        `json:"added"`   // Names of the sources added
+	Skipped []OPMLSkipped   `json:"skipped"` // Feeds left out, with why
+	Total   int             `json:"total"`   // Feeds in the file
+	Sources []models.Source `json:"sources"` // All sources after the import
+}
+
+// OPMLSkipped is a feed ImportOPML did not add.
+type OPMLSkipped struct {
+	Name     string `json:"name"`
+	Endpoint string `json:"endpoint"`
+	Reason   string `json:"reason"`
+}
+
+// OPMLExport is the data of ExportOPML.
+type OPMLExport struct {
+	Path     string `json:"path"`
+	Exported int    `json:"exported"` // Sources written; those without an endpoint are left out
+}
+
+// ImportOPML adds the feeds of the OPML file at path to sources.json. A feed
+// whose endpoint (ignoring scheme, "www." and a trailing slash) or name
+// matches an existing source, or an earlier feed of the file, is skipped, as
+// is a feed that is not a valid source (see sourcefile.Validate), so the
+// import never leaves sources.json in a state SaveSources rejects.
+// Feeds exported by Nous keep all their settings; feeds from other readers
+// become enabled RSS sources with their folder as Category. Credential
+// headers go to the secret store, as with SaveSources.
+func (a *App) ImportOPML(path string) string {
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	if strings.TrimSpace(path) == "" {
+		return req.fail(models.ErrorBadRequest, "No OPML file given")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return req.fail(models.ErrorBadRequest, fmt.Sprintf("Error opening OPML file: %v", err))
+	}
+	defer f.Close()
+
+	feeds, err := opml.Parse(f)
+	if err != nil {
+		return req.fail(models.ErrorBadRequest, fmt.Sprintf("Error reading OPML file: %v", err))
+	}
+
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+
+	sources, err := a.loadSources()
+	if err != nil {
+		return req.fail(models.ErrorInternal, fmt.Sprintf("Error loading sources: %v", err))
+	}
+
+	byEndpoint := map[string]string{}
+	byName := map[string]bool{}
+	for _, s := range sources {
+		byEndpoint[opml.EndpointKey(s.Endpoint)] = s.Name
+		byName[strings.ToLower(s.Name)] = true
+	}
+
+	res := OPMLImport{Added: []string{}, Skipped: []OPMLSkipped{}, Total: len(feeds)}
+	for _, src := range feeds {
+		key := opml.EndpointKey(src.Endpoint)
+		reason := ""
+		switch {
+		case byEndpoint[key] != "":
+			reason = fmt.Sprintf("same feed as %q", byEndpoint[key])
+		case byName[strings.ToLower(src.Name)]:
+			reason = "a source with this name exists"
+		default:
+			reason = validationReason(sourcefile.Validate([]Source{src}))
+		}
+		if reason != "" {
+			res.Skipped = append(res.Skipped, OPMLSkipped{Name: src.Name, Endpoint: src.Endpoint, Reason: reason})
+			continue
+		}
+
+		if src.Enabled == nil {
+			enabled := true
+			src.Enabled = &enabled
+		}
+		if err := a.sealHeaders(&src, Source{}); err != nil {
+			return req.failWith("Error saving credential headers", err)
+		}
+		sources = append(sources, src)
+		byEndpoint[key] = src.Name
+		byName[strings.ToLower(src.Name)] = true
+		res.Added = append(res.Added, src.Name)
+	}
+
+	if len(res.Added) > 0 {
+		if err := a.saveSources(sources); err != nil {
+			return req.fail(models.ErrorInternal, fmt.Sprintf("Error saving sources: %v", err))
+		}
+	}
+	if sources == nil {
+		sources = []Source{}
+	}
+	res.Sources = maskSources(sources)
+	return req.ok(res)
+}
+
+// validationReason describes the issues of a single source, or is "" if
+// there are none.
+func validationReason(issues []sourcefile.Issue) string {
+	reasons := make([]string, len(issues))
+	for i, issue := range issues {
+		reasons[i] = issue.Field + ": " + issue.Message
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// ExportOPML writes the sources in sources.json to path as OPML 2.0, grouped
+// into folders by Category. Nous-specific settings are kept in "nous:"
+// attributes that other readers ignore; API keys and credential headers are
+// not exported.
+func (a *App) ExportOPML(path string) string {
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	if strings.TrimSpace(path) == "" {
+		return req.fail(models.ErrorBadRequest, "No OPML file given")
+	}
+
+	sourcesMu.Lock()
+	sources, err := a.loadSources()
+	sourcesMu.Unlock()
+	if err != nil {
+		return req.fail(models.ErrorInternal, fmt.Sprintf("Error loading sources: %v", err))
+	}
+
+	for i := range sources {
+		sources[i].Headers = publicHeaders(sources[i].Headers)
+	}
+
+	var buf bytes.Buffer
+	if err := opml.Write(&buf, "Nous sources", sources); err != nil {
+		return req.fail(models.ErrorInternal, fmt.Sprintf("Error encoding OPML: %v", err))
+	}
+	if err := persist.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return req.fail(models.ErrorInternal, fmt.Sprintf("Error writing OPML file: %v", err))
+	}
+
+	exported := 0
+	for _, s := range sources {
+		if s.Endpoint != "" {
+			exported++
+		}
+	}
+	return req.ok(OPMLExport{Path: path, Exported: exported})
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportOPMLSkipsInvalidFeeds(t *testing.T) {
+	a := newTestApp(t)
+	doc := `<opml version="2.0" xmlns:nous="urn:nous:opml"><body>
+		<outline text="Good" xmlUrl="https://good.example/rss"/>
+		<outline text="Feed scheme" xmlUrl="feed://feeds.example/rss"/>
+		<outline text="Relative" xmlUrl="/rss.xml"/>
+		<outline text="Bias" xmlUrl="https://bias.example/rss" nous:bias="Lean Left"/>
+		<outline text="Scraper" xmlUrl="https://scrape.example/" nous:parser="html"/>
+	</body></opml>`
+	path := filepath.Join(t.TempDir(), "feeds.opml")
+	if err := os.WriteFile(path, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var res struct {
+		Success bool       `json:"success"`
+		Data    OPMLImport `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(a.ImportOPML(path)), &res); err != nil || !res.Success {
+		t.Fatalf("ImportOPML: %+v, %v", res, err)
+	}
+	if strings.Join(res.Data.Added, ",") != "Good" {
+		t.Errorf("Added = %v, want only Good", res.Data.Added)
+	}
+	reasons := map[string]string{}
+	for _, s := range res.Data.Skipped {
+		reasons[s.Name] = s.Reason
+	}
+	for name, field := range map[string]string{"Feed scheme": "endpoint", "Relative": "endpoint", "Bias": "bias", "Scraper": "scrape"} {
+		if !strings.HasPrefix(reasons[name], field+": ") {
+			t.Errorf("%s skipped with %q, want a %s issue", name, reasons[name], field)
+		}
+	}
+
+	// The saved list still passes SaveSources
+	sources, err := a.loadSources()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := a.SaveSources(sources); !strings.Contains(out, `"success":true`) {
+		t.Errorf("SaveSources after the import: %s", out)
+	}
+}
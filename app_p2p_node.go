@@ -8,7 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
-	"strings"
+	"sync"
 )
 
 // =========================
@@ -35,6 +35,28 @@ var DefaultHeap = HeapMedium
 // This prevents starting multiple instances of the node accidentally.
 var p2pProcessRunning bool
 
+// p2pMu guards p2pProcessRunning, a.p2pCmd and the supervisor state.
+var p2pMu sync.Mutex
+
+// p2pProcess bundles a launched node process with the signals that the
+// supervisor and the READY handshake wait on.
+type p2pProcess struct {
+	cmd        *exec.Cmd
+	outputDone sync.WaitGroup   // Released once stdout and stderr are drained
+	ready      chan P2PNodeInfo // Receives the READY handshake (buffered)
+	readyOnce  sync.Once
+	exited     chan struct{} // Closed by the supervisor once the process exits
+}
+
+// newP2PProcess wraps a started command.
+func newP2PProcess(cmd *exec.Cmd) *p2pProcess {
+	return &p2pProcess{
+		cmd:    cmd,
+		ready:  make(chan P2PNodeInfo, 1),
+		exited: make(chan struct{}),
+	}
+}
+
 // =========================
 // P2P Node Functions
 // =========================
@@ -44,23 +66,56 @@ var p2pProcessRunning bool
 // It performs the following steps:
 //  1. Checks if the node is already running and returns early if so.
 //  2. Cleans any leftover OrbitDB lock files to avoid startup issues.
-//  3. Determines the HTTP and LibP2P ports for this instance.
-//  4. Selects the correct Node.js binary based on the OS.
-//  5. Verifies that the binary and compiled server script exist.
-//  6. Prepares the command to launch Node.js with the configured memory heap.
-//  7. Sets environment variables for the node (ports, identity, keystore, DB path).
-//  8. Captures stdout and stderr streams for logging.
-//  9. Starts the Node.js process and monitors stdout for "READY" messages.
+//  3. Launches the bundled Node.js process (see launchP2PNode).
+//  4. Hands the process over to the supervisor, which restarts it on crashes.
+//  5. Blocks until the node reports READY or answers GET /status, killing it
+//     and returning a *P2PStartupTimeoutError if P2PStartupTimeout elapses.
 //
 // Returns a string describing the result of the start attempt.
 func (a *App) StartP2PNode() (string, error) {
+	p2pMu.Lock()
+
 	if p2pProcessRunning {
+		p2pMu.Unlock()
 		return "", fmt.Errorf("P2P node already running")
 	}
 
 	// Clean any leftover OrbitDB lock files before starting
 	CleanOrbitDBLocks()
 
+	// A manual start resets the crash budget and any pending stop
+	resetP2PSupervisor()
+
+	proc, err := a.launchP2PNode()
+	p2pMu.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	go a.superviseP2PNode(proc)
+
+	info, err := a.waitForP2PReady(proc, P2PStartupTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("P2P node ready on port %d with %d MB heap (peer %s, version %s)",
+		info.HTTPPort, DefaultHeap, info.PeerID, info.Version), nil
+}
+
+// launchP2PNode spawns the bundled Node.js process and wires up its output.
+//
+// It performs the following steps:
+//  1. Determines the HTTP and LibP2P ports for this instance.
+//  2. Selects the correct Node.js binary based on the OS.
+//  3. Verifies that the binary and compiled server script exist.
+//  4. Prepares the command to launch Node.js with the configured memory heap.
+//  5. Sets environment variables for the node (ports, identity, keystore, DB path).
+//  6. Captures stdout and stderr streams for logging.
+//  7. Starts the Node.js process and monitors stdout for "READY" messages.
+//
+// Callers must hold p2pMu.
+func (a *App) launchP2PNode() (*p2pProcess, error) {
 	// Determine ports for this instance
 	httpPort := httpPortBase + instanceID
 	libp2pPort := libp2pPortBase + instanceID
@@ -75,29 +130,29 @@ func (a *App) StartP2PNode() (string, error) {
 	case "windows":
 		nodeBinary = "./frontend/dist/bin/node-win.exe"
 	default:
-		return "", fmt.Errorf("unsupported OS")
+		return nil, fmt.Errorf("unsupported OS")
 	}
 
 	// Verify the node binary exists
 	if _, err := os.Stat(nodeBinary); os.IsNotExist(err) {
-		return "", fmt.Errorf("bundled Node binary not found at %s", nodeBinary)
+		return nil, fmt.Errorf("bundled Node binary not found at %s", nodeBinary)
 	}
 
 	// Path to compiled server setup script
 	jsNodePath := "./backend/dist/setup.js"
 	if _, err := os.Stat(jsNodePath); os.IsNotExist(err) {
-		return "", fmt.Errorf("compiled server node not found at %s. Run build first", jsNodePath)
+		return nil, fmt.Errorf("compiled server node not found at %s. Run build first", jsNodePath)
 	}
 
 	// Prepare Node.js command
-	a.p2pCmd = exec.Command(
+	cmd := exec.Command(
 		nodeBinary,
 		fmt.Sprintf("--max-old-space-size=%d", DefaultHeap),
 		jsNodePath,
 	)
 
 	// Set environment variables
-	a.p2pCmd.Env = append(os.Environ(),
+	cmd.Env = append(os.Environ(),
 		fmt.Sprintf("HTTP_PORT=%d", httpPort),
 		fmt.Sprintf("LIBP2P_ADDR=/ip4/127.0.0.1/tcp/%d", libp2pPort),
 		fmt.Sprintf("IDENTITY_ID=%s", IDENTITY_ID),
@@ -106,55 +161,70 @@ func (a *App) StartP2PNode() (string, error) {
 	)
 
 	// Capture stdout and stderr
-	stdout, err := a.p2pCmd.StdoutPipe()
+	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return "", fmt.Errorf("failed to get stdout: %v", err)
+		return nil, fmt.Errorf("failed to get stdout: %v", err)
 	}
-	stderr, err := a.p2pCmd.StderrPipe()
+	stderr, err := cmd.StderrPipe()
 	if err != nil {
-		return "", fmt.Errorf("failed to get stderr: %v", err)
+		return nil, fmt.Errorf("failed to get stderr: %v", err)
 	}
 
 	// Start the Node.js process
-	if err := a.p2pCmd.Start(); err != nil {
-		return "", fmt.Errorf("failed to start P2P node: %v", err)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start P2P node: %v", err)
 	}
 
 	// Mark as running
+	a.p2pCmd = cmd
 	p2pProcessRunning = true
 
+	proc := newP2PProcess(cmd)
+	proc.outputDone.Add(2)
+
 	// Log stdout lines and detect "READY" message
 	go func() {
+		defer proc.outputDone.Done()
 		scanner := bufio.NewScanner(stdout)
 		for scanner.Scan() {
 			line := scanner.Text()
-			if strings.Contains(line, "READY") {
+			if info, ok := parseP2PReadyLine(line); ok {
 				log.Println("[P2P] Node reported READY")
+				a.onP2PReady(proc, info)
 			}
 			log.Println("[P2P stdout]", line)
 		}
 	}()
 
 	// Forward stderr to main stderr
-	go io.Copy(os.Stderr, stderr)
+	go func() {
+		defer proc.outputDone.Done()
+		io.Copy(os.Stderr, stderr)
+	}()
 
-	return fmt.Sprintf("P2P node started with %d MB heap", DefaultHeap), nil
+	return proc, nil
 }
 
 // StopP2PNode stops the local P2P node process cleanly.
 //
 // It performs the following steps:
-//  1. Sends an interrupt signal to the running node process.
-//  2. Cleans any leftover OrbitDB lock files.
-//  3. Kills any lingering Node.js processes to ensure a clean shutdown.
-//  4. Logs success and returns true.
+//  1. Tells the supervisor the exit is intentional and cancels pending restarts.
+//  2. Sends an interrupt signal to the running node process.
+//  3. Cleans any leftover OrbitDB lock files.
+//  4. Kills any lingering Node.js processes to ensure a clean shutdown.
+//  5. Logs success and returns true.
 //
 // Returns true if the stop procedure was initiated.
 func (a *App) StopP2PNode() bool {
+	p2pMu.Lock()
+	stopP2PSupervisor()
 	if a.p2pCmd != nil && a.p2pCmd.Process != nil {
 		a.p2pCmd.Process.Signal(os.Interrupt)
 		a.p2pCmd = nil
 	}
+	p2pProcessRunning = false
+	p2pNodeInfo = nil
+	p2pMu.Unlock()
 
 	CleanOrbitDBLocks()
 	KillLingeringNode()
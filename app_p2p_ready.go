@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// =========================
+// P2P READY Handshake
+// =========================
+
+// P2PStartupTimeout is how long StartP2PNode waits for the node to become
+// usable before killing it. Override with P2P_STARTUP_TIMEOUT (seconds).
+var P2PStartupTimeout = 60 * time.Second
+
+// p2pReadyProbeInterval is how often GET /status is probed while waiting for READY.
+const p2pReadyProbeInterval = 500 * time.Millisecond
+
+// P2PNodeInfo is what the node advertises once it is usable.
+//
+// The node prints it on stdout as a single structured line:
+//
+//	READY {"httpPort":9001,"peerId":"12D3KooW...","version":"0.1.1"}
+type P2PNodeInfo struct {
+	HTTPPort int    `json:"httpPort"` // Port the node's HTTP API listens on
+	PeerID   string `json:"peerId"`   // libp2p peer ID (empty when detected via /status)
+	Version  string `json:"version"`  // Node backend version (empty when detected via /status)
+}
+
+// P2PStartupTimeoutError is returned by StartP2PNode when the node does not
+// become ready within the startup deadline. The half-started process is killed.
+type P2PStartupTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *P2PStartupTimeoutError) Error() string {
+	return fmt.Sprintf("P2P node not ready after %s", e.Timeout)
+}
+
+// p2pNodeInfo holds the info advertised by the currently running node.
+// Guarded by p2pMu.
+var p2pNodeInfo *P2PNodeInfo
+
+// GetP2PNodeInfo returns the info advertised by the running node; the data
+// is null if the node has not reported READY yet.
+func (a *App) GetP2PNodeInfo() string {
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	return req.ok(a.nodeInfo())
+}
+
+// nodeInfo returns the info advertised by the running node, or nil if the
+// node has not reported READY yet.
+func (a *App) nodeInfo() *P2PNodeInfo {
+	p2pMu.Lock()
+	defer p2pMu.Unlock()
+	return p2pNodeInfo
+}
+
+// parseP2PReadyLine detects the READY line on the node's stdout. A bare
+// "READY" is accepted too, in which case only the expected port is known.
+func parseP2PReadyLine(line string) (P2PNodeInfo, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "READY") {
+		return P2PNodeInfo{}, false
+	}
+
+	info := P2PNodeInfo{HTTPPort: instanceHTTPPort()}
+	if payload := strings.TrimSpace(strings.TrimPrefix(line, "READY")); payload != "" {
+		if err := json.Unmarshal([]byte(payload), &info); err != nil {
+			log.Printf("[P2P] Malformed READY payload %q: %v", payload, err)
+		}
+	}
+	return info, true
+}
+
+// onP2PReady records the advertised node info, unblocks the handshake and
+// notifies the frontend.
+func (a *App) onP2PReady(proc *p2pProcess, info P2PNodeInfo) {
+	p2pMu.Lock()
+	if a.p2pCmd == proc.cmd {
+		p2pNodeInfo = &info
+	}
+	p2pMu.Unlock()
+
+	proc.readyOnce.Do(func() {
+		proc.ready <- info
+	})
+	a.emitEvent(EventP2PReady, info)
+
+	// Catch the search index up with articles stored while it was not running.
+	go func() {
+		ctx, cancel := context.WithTimeout(a.requestContext(), LongRequestTimeout)
+		defer cancel()
+		if err := a.syncSearchIndex(ctx); err != nil {
+			log.Println("[Search] Failed to sync index:", err)
+		}
+	}()
+}
+
+// waitForP2PReady blocks until the node reports READY on stdout or answers
+// GET /status, whichever comes first. On timeout the process is killed and a
+// *P2PStartupTimeoutError is returned.
+func (a *App) waitForP2PReady(proc *p2pProcess, timeout time.Duration) (P2PNodeInfo, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	probe := time.NewTicker(p2pReadyProbeInterval)
+	defer probe.Stop()
+
+	for {
+		select {
+		case info := <-proc.ready:
+			return info, nil
+
+		case <-probe.C:
+			if info, ok := a.probeP2PStatus(); ok {
+				a.onP2PReady(proc, info)
+				return info, nil
+			}
+
+		case <-proc.exited:
+			return P2PNodeInfo{}, fmt.Errorf("P2P node exited before reporting READY")
+
+		case <-deadline.C:
+			p2pMu.Lock()
+			// Detach first so the supervisor treats the exit as intentional
+			if a.p2pCmd == proc.cmd {
+				a.p2pCmd = nil
+				p2pProcessRunning = false
+			}
+			p2pMu.Unlock()
+
+			if proc.cmd.Process != nil {
+				proc.cmd.Process.Kill()
+			}
+			log.Printf("[P2P] Node not ready after %s, killed", timeout)
+			return P2PNodeInfo{}, &P2PStartupTimeoutError{Timeout: timeout}
+		}
+	}
+}
+
+// probeP2PStatus checks whether the node's HTTP API is answering.
+func (a *App) probeP2PStatus() (P2PNodeInfo, bool) {
+	ctx, cancel := context.WithTimeout(a.requestContext(), p2pReadyProbeInterval)
+	defer cancel()
+
+	if _, err := a.nodeClient().Status(ctx); err != nil {
+		return P2PNodeInfo{}, false
+	}
+	return P2PNodeInfo{HTTPPort: instanceHTTPPort()}, true
+}
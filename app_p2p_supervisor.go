@@ -0,0 +1,216 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// =========================
+// P2P Supervisor Configuration
+// =========================
+
+// Restart policy for the bundled P2P node. When the process exits without
+// StopP2PNode being called, the supervisor restarts it after a delay that
+// doubles with every crash (P2PRestartBackoffInitial, 2x, 4x, ...) up to
+// P2PRestartBackoffMax. Once more than P2PCrashBudget crashes happen within
+// P2PCrashWindow the supervisor gives up and leaves the node stopped.
+var (
+	P2PRestartBackoffInitial = 1 * time.Second
+	P2PRestartBackoffMax     = 30 * time.Second
+	P2PCrashBudget           = 5
+	P2PCrashWindow           = 10 * time.Minute
+)
+
+// Wails events emitted by the supervisor so the frontend can track the node.
+const (
+	EventP2PCrashed    = "p2p:crashed"
+	EventP2PRestarting = "p2p:restarting"
+	EventP2PReady      = "p2p:ready"
+)
+
+// P2PCrashedEvent is the payload of the "p2p:crashed" event.
+type P2PCrashedEvent struct {
+	Error       string `json:"error"`       // Exit reason or relaunch error
+	ExitCode    int    `json:"exitCode"`    // Process exit code, -1 if unknown
+	Crashes     int    `json:"crashes"`     // Crashes within the current crash window
+	Budget      int    `json:"budget"`      // Allowed crashes within the window
+	WillRestart bool   `json:"willRestart"` // False once the crash budget is exhausted
+}
+
+// P2PRestartingEvent is the payload of the "p2p:restarting" event.
+type P2PRestartingEvent struct {
+	Attempt int   `json:"attempt"` // Restart attempt within the current crash window
+	DelayMs int64 `json:"delayMs"` // Backoff before the node is relaunched
+}
+
+// =========================
+// P2P Supervisor State
+// =========================
+
+// p2pStopping is set by StopP2PNode so the supervisor treats the next exit as intentional.
+var p2pStopping bool
+
+// p2pCrashTimes holds the crash timestamps within the current crash window.
+var p2pCrashTimes []time.Time
+
+// p2pStopCh is closed by StopP2PNode to cancel a pending restart.
+var p2pStopCh chan struct{}
+
+// resetP2PSupervisor clears the crash history before a manual start.
+// Callers must hold p2pMu.
+func resetP2PSupervisor() {
+	p2pStopping = false
+	p2pCrashTimes = nil
+	p2pStopCh = make(chan struct{})
+}
+
+// stopP2PSupervisor marks the node as intentionally stopped and wakes any
+// pending restart. Callers must hold p2pMu.
+func stopP2PSupervisor() {
+	if p2pStopping {
+		return
+	}
+	p2pStopping = true
+	if p2pStopCh != nil {
+		close(p2pStopCh)
+	}
+}
+
+// =========================
+// P2P Supervisor Functions
+// =========================
+
+// superviseP2PNode waits on the node process and restarts it when it exits
+// unexpectedly. It returns once the node is stopped on purpose, replaced by a
+// manual start, or the crash budget is exhausted.
+func (a *App) superviseP2PNode(proc *p2pProcess) {
+	for {
+		// cmd.Wait closes the pipes, so drain stdout/stderr first
+		proc.outputDone.Wait()
+		cmd := proc.cmd
+		err := cmd.Wait()
+		close(proc.exited)
+
+		p2pMu.Lock()
+		intentional := p2pStopping || a.p2pCmd != cmd
+		if !intentional {
+			a.p2pCmd = nil
+			p2pProcessRunning = false
+			p2pNodeInfo = nil
+		}
+		p2pMu.Unlock()
+
+		if intentional {
+			log.Println("[P2P] Node process exited after stop request")
+			return
+		}
+
+		exitCode := -1
+		if cmd.ProcessState != nil {
+			exitCode = cmd.ProcessState.ExitCode()
+		}
+		reason := "exited with status 0"
+		if err != nil {
+			reason = err.Error()
+		}
+
+		var ok bool
+		proc, ok = a.restartP2PNode(reason, exitCode)
+		if !ok {
+			return
+		}
+	}
+}
+
+// restartP2PNode records a crash and relaunches the node after the backoff
+// delay, retrying while the crash budget allows. The relaunched node gets the
+// same READY handshake as StartP2PNode. It returns false when the supervisor
+// should stop watching.
+func (a *App) restartP2PNode(reason string, exitCode int) (*p2pProcess, bool) {
+	for {
+		p2pMu.Lock()
+		if p2pStopping {
+			p2pMu.Unlock()
+			return nil, false
+		}
+		now := time.Now()
+		p2pCrashTimes = append(recentP2PCrashes(now), now)
+		crashes := len(p2pCrashTimes)
+		stopCh := p2pStopCh
+		p2pMu.Unlock()
+
+		willRestart := crashes <= P2PCrashBudget
+		log.Printf("[P2P] Node crashed: %s (%d/%d crashes in %s)", reason, crashes, P2PCrashBudget, P2PCrashWindow)
+		a.emitEvent(EventP2PCrashed, P2PCrashedEvent{
+			Error:       reason,
+			ExitCode:    exitCode,
+			Crashes:     crashes,
+			Budget:      P2PCrashBudget,
+			WillRestart: willRestart,
+		})
+		if !willRestart {
+			log.Println("[P2P] Crash budget exhausted, giving up on restarts")
+			return nil, false
+		}
+
+		delay := p2pRestartDelay(crashes)
+		log.Printf("[P2P] Restarting node in %s (attempt %d)", delay, crashes)
+		a.emitEvent(EventP2PRestarting, P2PRestartingEvent{
+			Attempt: crashes,
+			DelayMs: delay.Milliseconds(),
+		})
+
+		select {
+		case <-time.After(delay):
+		case <-stopCh:
+			return nil, false
+		}
+
+		p2pMu.Lock()
+		// Stopped, or started manually, while we were backing off
+		if p2pStopping || p2pProcessRunning {
+			p2pMu.Unlock()
+			return nil, false
+		}
+		CleanOrbitDBLocks()
+		proc, err := a.launchP2PNode()
+		p2pMu.Unlock()
+
+		if err == nil {
+			// Same handshake as a manual start, so p2p:ready fires even if the
+			// READY line is missed. The supervisor keeps waiting on the process
+			// meanwhile, which is what closes proc.exited.
+			go func() {
+				if _, err := a.waitForP2PReady(proc, P2PStartupTimeout); err != nil {
+					log.Println("[P2P] Restarted node not ready:", err)
+				}
+			}()
+			return proc, true
+		}
+		reason, exitCode = err.Error(), -1
+	}
+}
+
+// recentP2PCrashes drops crash timestamps that fall outside P2PCrashWindow.
+// Callers must hold p2pMu.
+func recentP2PCrashes(now time.Time) []time.Time {
+	recent := p2pCrashTimes[:0]
+	for _, t := range p2pCrashTimes {
+		if now.Sub(t) <= P2PCrashWindow {
+			recent = append(recent, t)
+		}
+	}
+	return recent
+}
+
+// p2pRestartDelay returns the capped exponential backoff for the given attempt (1-based).
+func p2pRestartDelay(attempt int) time.Duration {
+	delay := P2PRestartBackoffInitial
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= P2PRestartBackoffMax {
+			return P2PRestartBackoffMax
+		}
+	}
+	return delay
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"nous-app/internal/models"
+	"nous-app/internal/query"
+)
+
+// StoreCacheTTL is how long QueryArticles and the paged listings reuse the
+// articles they loaded from the node, so paging through a large library does
+// not reload it for every page. Saving or deleting an article through the
+// app drops the cache of its store early.
+var StoreCacheTTL = 30 * time.Second
+
+// storeCache holds the last listing of one of the node's article stores.
+type storeCache struct {
+	mu    sync.Mutex
+	items interface{}
+	at    time.Time
+}
+
+var localCache, analyzedCache, federatedCache storeCache
+
+// get returns the cached listing, or calls load when it is missing or stale.
+func (c *storeCache) get(load func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.items != nil && time.Since(c.at) < StoreCacheTTL {
+		return c.items, nil
+	}
+	items, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.items, c.at = items, time.Now()
+	return items, nil
+}
+
+// reset makes the next get reload the store.
+func (c *storeCache) reset() {
+	c.mu.Lock()
+	c.items = nil
+	c.mu.Unlock()
+}
+
+func (a *App) cachedLocalArticles(ctx context.Context) ([]models.Article, error) {
+	items, err := localCache.get(func() (interface{}, error) { return a.nodeClient().LocalArticles(ctx) })
+	if err != nil {
+		return nil, err
+	}
+	return items.([]models.Article), nil
+}
+
+func (a *App) cachedAnalyzedArticles(ctx context.Context) ([]models.ArticleAnalyzed, error) {
+	items, err := analyzedCache.get(func() (interface{}, error) { return a.nodeClient().AnalyzedArticles(ctx) })
+	if err != nil {
+		return nil, err
+	}
+	return items.([]models.ArticleAnalyzed), nil
+}
+
+func (a *App) cachedFederatedArticles(ctx context.Context) ([]models.FederatedArticlePointer, error) {
+	items, err := federatedCache.get(func() (interface{}, error) { return a.nodeClient().FederatedArticles(ctx) })
+	if err != nil {
+		return nil, err
+	}
+	return items.([]models.FederatedArticlePointer), nil
+}
+
+// QueryArticles filters the local, analyzed and federated articles with the
+// same options as the frontend filter panel, sorts them ("newest", "oldest",
+// "title", "confidence" or "coverage"; empty for newest) and returns the page
+// after cursor as a query.Page. The page holds up to limit articles, the total
+// matching, facet counts for every filter dimension and the cursor of the
+// next page; pass an empty cursor for the first page. Cursors stay valid as
+// articles are added or removed: the next page starts right after the last
+// article seen.
+func (a *App) QueryArticles(filter query.Filter, sort string, cursor string, limit int, requestID string) string {
+	req := a.beginRequest(requestID, DefaultRequestTimeout)
+	defer req.end()
+
+	items, err := a.queryItems(req.ctx)
+	if err != nil {
+		return req.failWith("Error fetching articles", err)
+	}
+
+	page, err := query.Run(items, filter, sort, cursor, limit, a.sourceBiases())
+	if err != nil {
+		if errors.Is(err, query.ErrBadCursor) {
+			return req.fail(models.ErrorBadRequest, "Invalid cursor; start again from the first page")
+		}
+		return req.fail(models.ErrorBadRequest, fmt.Sprintf("Error querying articles: %v", err))
+	}
+	return req.ok(page)
+}
+
+// queryItems returns the articles of all three stores.
+func (a *App) queryItems(ctx context.Context) ([]query.Item, error) {
+	local, err := a.cachedLocalArticles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	analyzed, err := a.cachedAnalyzedArticles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	federated, err := a.cachedFederatedArticles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return query.Items(local, analyzed, federated), nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"nous-app/internal/fetcher"
+	"nous-app/internal/models"
+	"nous-app/internal/readability"
+)
+
+// ExtractTimeout bounds a background content extraction, and
+// ExtractRetryInterval is how long FetchLocalArticle waits before trying an
+// article again after its extraction failed.
+const (
+	ExtractTimeout       = 30 * time.Second
+	ExtractRetryInterval = 10 * time.Minute
+)
+
+// ArticleExtraction is an article with its extracted body.
+type ArticleExtraction struct {
+	Article Article `json:"article"` // Content, Summary, Author, Image and PublishedAt filled where missing
+	Text    string  `json:"text"`    // Plain text, paragraphs separated by blank lines
+	HTML    string  `json:"html"`    // Sanitized article HTML
+	Length  int     `json:"length"`  // Characters of text
+}
+
+var (
+	extractMu       sync.Mutex
+	extractAttempts = map[string]time.Time{} // Article ID → last background attempt
+)
+
+// ExtractArticle downloads article.URL and extracts its main content. Missing
+// fields of the article are filled from the page and its OpenGraph / JSON-LD
+// metadata; nothing is stored.
+func (a *App) ExtractArticle(article Article, requestID string) string {
+	req := a.beginRequest(requestID, DefaultRequestTimeout)
+	defer req.end()
+
+	if article.URL == "" {
+		return req.fail(models.ErrorBadRequest, "Article has no URL")
+	}
+
+	res, err := extractArticle(req.ctx, &article)
+	if err != nil {
+		if req.ctx.Err() != nil {
+			return req.failWith("Error extracting article", req.ctx.Err())
+		}
+		if errors.Is(err, readability.ErrNoContent) {
+			return req.fail(models.ErrorNotFound, err.Error())
+		}
+		return req.fail(models.ErrorUpstream, fmt.Sprintf("Error extracting article: %v", err))
+	}
+	return req.ok(ArticleExtraction{
+		Article: article,
+		Text:    res.Text,
+		HTML:    res.HTML,
+		Length:  res.Length,
+	})
+}
+
+// extractArticle fetches article.URL and enriches article in place.
+func extractArticle(ctx context.Context, article *Article) (*readability.Result, error) {
+	headers := map[string]string{"User-Agent": fetcher.DefaultUserAgent}
+	res, err := readability.Fetch(ctx, nil, article.URL, headers)
+	if err != nil {
+		return nil, err
+	}
+	readability.Enrich(article, res)
+	return res, nil
+}
+
+// extractInBackground extracts a stored article that has no content yet and
+// adds what was extracted to it on the node, so FetchLocalArticle stops
+// reporting it as pending. Each article is attempted at most once per
+// ExtractRetryInterval.
+func (a *App) extractInBackground(article Article) {
+	if article.URL == "" {
+		return
+	}
+	key := article.ID
+	if key == "" {
+		key = article.URL
+	}
+
+	extractMu.Lock()
+	if last, ok := extractAttempts[key]; ok && time.Since(last) < ExtractRetryInterval {
+		extractMu.Unlock()
+		return
+	}
+	extractAttempts[key] = time.Now()
+	extractMu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(a.requestContext(), ExtractTimeout)
+		defer cancel()
+
+		if _, err := extractArticle(ctx, &article); err != nil {
+			log.Printf("[Extract] %s: %v\n", article.URL, err)
+			return
+		}
+		if err := a.storeExtracted(ctx, article); err != nil {
+			log.Printf("[Extract] Failed to save %s: %v\n", article.URL, err)
+			return
+		}
+
+		extractMu.Lock()
+		delete(extractAttempts, key)
+		extractMu.Unlock()
+		log.Printf("[Extract] Stored content for %s\n", article.URL)
+	}()
+}
+
+// storeExtracted adds the fields extraction filled in article to the stored
+// article. The node replaces whole records, so they are merged into the
+// record as stored now: saving article itself would drop the fields the Go
+// types don't model and anything changed during the download. Fields the
+// stored article already has are kept, and an article analyzed meanwhile is
+// left alone.
+func (a *App) storeExtracted(ctx context.Context, article Article) error {
+	stored, err := a.nodeClient().LocalArticleJSON(ctx, article.URL)
+	if err != nil {
+		return err
+	}
+	if analyzed, _ := stored["analyzed"].(bool); analyzed {
+		return nil
+	}
+
+	changed := false
+	for key, value := range extractedFields(article) {
+		current, _ := stored[key].(string)
+		if current = strings.TrimSpace(current); current == "" || (key == "title" && current == "Untitled") {
+			stored[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if _, err := a.nodeClient().SaveLocalArticle(ctx, stored, true); err != nil {
+		return err
+	}
+	localCache.reset()
+	var local Article
+	if err := decodeMap(stored, &local); err == nil {
+		a.indexArticle(local)
+	}
+	return nil
+}
+
+// extractedFields returns the fields readability.Enrich fills, by JSON name.
+func extractedFields(article Article) map[string]string {
+	fields := map[string]string{}
+	for key, v := range map[string]*string{
+		"title":       &article.Title,
+		"content":     article.Content,
+		"summary":     article.Summary,
+		"author":      article.Author,
+		"image":       article.Image,
+		"publishedAt": article.PublishedAt,
+		"language":    article.Language,
+	} {
+		if v != nil && *v != "" {
+			fields[key] = *v
+		}
+	}
+	return fields
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"nous-app/internal/models"
+)
+
+// =========================
+// Request Deadlines
+// =========================
+
+// Per-operation deadlines for calls to the P2P node. Status checks are polled
+// by the UI and must fail fast; fetching and translating can legitimately take
+// minutes while the node downloads feeds or runs models.
+const (
+	StatusRequestTimeout  = 3 * time.Second
+	DefaultRequestTimeout = 15 * time.Second
+	LongRequestTimeout    = 5 * time.Minute
+)
+
+// =========================
+// In-flight Requests
+// =========================
+
+// request is one in-flight binding call. Its context derives from the app's
+// request context, so it ends on timeout, on CancelRequest, or when the app closes.
+type request struct {
+	ID     string
+	ctx    context.Context
+	cancel context.CancelFunc
+	app    *App
+}
+
+// requestsMu guards App.requests.
+var requestsMu sync.Mutex
+
+// beginRequest registers a binding call with the given deadline. id may come
+// from the frontend so it can cancel the call later; a random one is used when
+// empty. Callers must defer req.end().
+func (a *App) beginRequest(id string, timeout time.Duration) *request {
+	if id == "" {
+		id = newRequestID()
+	}
+	ctx, cancel := context.WithTimeout(a.requestContext(), timeout)
+	req := &request{ID: id, ctx: ctx, cancel: cancel, app: a}
+
+	requestsMu.Lock()
+	if a.requests == nil {
+		a.requests = make(map[string]*request)
+	}
+	a.requests[id] = req
+	requestsMu.Unlock()
+
+	return req
+}
+
+// end releases the request's context and forgets it.
+func (r *request) end() {
+	r.cancel()
+
+	requestsMu.Lock()
+	if r.app.requests[r.ID] == r {
+		delete(r.app.requests, r.ID)
+	}
+	requestsMu.Unlock()
+}
+
+// CancelRequest aborts an in-flight long operation (e.g. TranslateArticle or
+// FetchArticlesBySources) started with the given request ID. The cancelled
+// call returns an envelope with the "canceled" error code.
+func (a *App) CancelRequest(requestID string) string {
+	requestsMu.Lock()
+	req, ok := a.requests[requestID]
+	requestsMu.Unlock()
+
+	res := &request{ID: newRequestID()}
+	if !ok {
+		return res.fail(models.ErrorNotFound, "No in-flight request with ID "+requestID)
+	}
+
+	req.cancel()
+	return res.ok(map[string]interface{}{"canceled": true, "requestId": requestID})
+}
+
+// requestContext returns the context node requests are bound to. It is
+// cancelled in BeforeClose so nothing outlives the window.
+func (a *App) requestContext() context.Context {
+	if a.reqCtx == nil {
+		return context.Background()
+	}
+	return a.reqCtx
+}
+
+// cancelRequests aborts every in-flight node request.
+func (a *App) cancelRequests() {
+	if a.reqCancel != nil {
+		a.reqCancel()
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+
+	"nous-app/internal/models"
+	"nous-app/internal/nodeclient"
+	"nous-app/internal/secrets"
+)
+
+// newRequestID returns a short random identifier for a binding call
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// ok wraps a successful binding result in the APIResponse envelope
+func (r *request) ok(data interface{}) string {
+	return toJSON(APIResponse{
+		Success:   true,
+		Data:      data,
+		RequestID: r.ID,
+	})
+}
+
+// fail wraps a failure in the APIResponse envelope and logs it
+func (r *request) fail(code ErrorCode, msg string) string {
+	res := APIResponse{
+		Success:   false,
+		Code:      code,
+		Error:     msg,
+		RequestID: r.ID,
+	}
+	log.Printf("[%s] %s: %s", res.RequestID, code, msg)
+	return toJSON(res)
+}
+
+// invalid wraps a validation failure in the APIResponse envelope; unlike
+// other failures it carries data, the list of issues for the UI to show.
+func (r *request) invalid(msg string, issues interface{}) string {
+	res := APIResponse{
+		Success:   false,
+		Code:      models.ErrorValidation,
+		Error:     msg,
+		Data:      issues,
+		RequestID: r.ID,
+	}
+	log.Printf("[%s] %s: %s", res.RequestID, res.Code, msg)
+	return toJSON(res)
+}
+
+// failWith wraps err in the APIResponse envelope, deriving the error code
+// from the failure. op describes the operation, e.g. "Error fetching local articles".
+func (r *request) failWith(op string, err error) string {
+	return r.fail(errorCodeFor(err), op+": "+err.Error())
+}
+
+// errorCodeFor maps an error from the node client (or elsewhere) to an ErrorCode
+func errorCodeFor(err error) ErrorCode {
+	if errors.Is(err, context.Canceled) {
+		return models.ErrorCanceled
+	}
+	if errors.Is(err, secrets.ErrLocked) {
+		return models.ErrorSecretsLocked
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return models.ErrorUpstreamTimeout
+	}
+	if nodeclient.IsUnreachable(err) {
+		return models.ErrorNodeUnreachable
+	}
+
+	var nodeErr *nodeclient.Error
+	if !errors.As(err, &nodeErr) || nodeErr.StatusCode == 0 {
+		return models.ErrorInternal
+	}
+
+	switch nodeErr.StatusCode {
+	case http.StatusNotFound:
+		return models.ErrorNotFound
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return models.ErrorBadRequest
+	case http.StatusTooManyRequests:
+		return models.ErrorRateLimited
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return models.ErrorUpstreamTimeout
+	default:
+		return models.ErrorUpstream
+	}
+}
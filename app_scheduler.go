@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// =========================
+// Fetch Scheduler Configuration
+// =========================
+
+// The scheduler wakes every FetchSchedulerTick and fetches each enabled source
+// whose refresh interval has elapsed since it was last fetched. Sources
+// without RefreshInterval use DefaultRefreshInterval, and a source is never
+// polled more often than its RateLimitPerMin allows.
+var (
+	FetchSchedulerEnabled  = true
+	FetchSchedulerTick     = 30 * time.Second
+	DefaultRefreshInterval = 15 * time.Minute
+)
+
+// Wails events emitted by the scheduler.
+const (
+	EventFetchBatch   = "fetch:batch"   // one source fetched (FetchBatchEvent)
+	EventFetchCycle   = "fetch:cycle"   // a round of due sources finished (FetchCycleEvent)
+	EventFetchPaused  = "fetch:paused"  // the node went down, polling stopped
+	EventFetchResumed = "fetch:resumed" // the node is back, polling resumed
+)
+
+// FetchBatchEvent is the payload of the "fetch:batch" event.
+type FetchBatchEvent struct {
+	Source   string    `json:"source"`   // Source name
+	Meta     FetchMeta `json:"meta"`     // Status code, bytes, duration, error
+	Articles int       `json:"articles"` // Articles parsed and handed to the local store
+}
+
+// FetchCycleEvent is the payload of the "fetch:cycle" event.
+type FetchCycleEvent struct {
+	Fetched int `json:"fetched"` // Sources fetched successfully
+	Failed  int `json:"failed"`  // Sources that failed
+}
+
+// =========================
+// Fetch Scheduler
+// =========================
+
+// fetchScheduler polls sources in the background while the P2P node is up.
+type fetchScheduler struct {
+	app    *App
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	paused      bool
+	lastAttempt map[string]time.Time // last fetch attempt per source name, including failures
+}
+
+// schedulerMu guards App.scheduler.
+var schedulerMu sync.Mutex
+
+// startFetchScheduler starts polling sources in the background. It is a no-op
+// if the scheduler is disabled or already running.
+func (a *App) startFetchScheduler() {
+	if !FetchSchedulerEnabled {
+		log.Println("[Fetch] Background scheduler disabled")
+		return
+	}
+
+	schedulerMu.Lock()
+	defer schedulerMu.Unlock()
+	if a.scheduler != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(a.requestContext())
+	s := &fetchScheduler{
+		app:         a,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+		lastAttempt: make(map[string]time.Time),
+	}
+	a.scheduler = s
+
+	go s.run(ctx)
+	log.Printf("[Fetch] Background scheduler started (tick %s)\n", FetchSchedulerTick)
+}
+
+// stopFetchScheduler stops the scheduler and waits for an in-progress round
+// to wind down.
+func (a *App) stopFetchScheduler() {
+	schedulerMu.Lock()
+	s := a.scheduler
+	a.scheduler = nil
+	schedulerMu.Unlock()
+
+	if s == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+func (s *fetchScheduler) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(FetchSchedulerTick)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick runs one round: pause or resume with the node, then fetch due sources.
+func (s *fetchScheduler) tick(ctx context.Context) {
+	if s.app.nodeInfo() == nil {
+		if !s.paused {
+			s.paused = true
+			log.Println("[Fetch] P2P node is down, pausing background fetches")
+			s.app.emitEvent(EventFetchPaused)
+		}
+		return
+	}
+	if s.paused {
+		s.paused = false
+		log.Println("[Fetch] P2P node is up, resuming background fetches")
+		s.app.emitEvent(EventFetchResumed)
+	}
+
+	sources, err := s.app.loadSources()
+	if err != nil {
+		log.Println("[Fetch] Failed to load sources:", err)
+		return
+	}
+
+	now := time.Now()
+	var due []Source
+	for _, src := range sources {
+		if s.isDue(src, now) {
+			due = append(due, src)
+			s.lastAttempt[src.Name] = now
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	log.Printf("[Fetch] %d source(s) due\n", len(due))
+
+	// A source counts as fetched only once its articles are stored
+	fetched := make(map[string]string) // source name -> LastFetched
+	failed := 0
+	for r := range s.app.fetchArticles(ctx, s.app.withSecrets(due)) {
+		stored := s.app.storeFetched(ctx, &r)
+		event := FetchBatchEvent{Source: r.Source.Name, Meta: r.Meta}
+		if stored {
+			fetched[r.Source.Name] = r.Meta.FetchedAt
+			event.Articles = len(r.Articles)
+		} else {
+			failed++
+		}
+		s.app.emitEvent(EventFetchBatch, event)
+	}
+
+	if len(fetched) > 0 {
+		if err := s.app.markSourcesFetched(fetched); err != nil {
+			log.Println("[Fetch] Failed to save LastFetched:", err)
+		}
+	}
+	s.app.emitEvent(EventFetchCycle, FetchCycleEvent{Fetched: len(fetched), Failed: failed})
+}
+
+// isDue reports whether src is enabled and its refresh interval has elapsed
+// since its last successful fetch or last attempt.
+func (s *fetchScheduler) isDue(src Source, now time.Time) bool {
+	if src.Enabled == nil || !*src.Enabled || src.Endpoint == "" {
+		return false
+	}
+
+	last := s.lastAttempt[src.Name]
+	if src.LastFetched != nil {
+		if t, err := time.Parse(time.RFC3339, *src.LastFetched); err == nil && t.After(last) {
+			last = t
+		}
+	}
+	return now.Sub(last) >= refreshInterval(src)
+}
+
+// refreshInterval returns how often src is polled: its own RefreshInterval or
+// DefaultRefreshInterval, but never faster than its rate limit allows.
+func refreshInterval(src Source) time.Duration {
+	interval := DefaultRefreshInterval
+	if src.RefreshInterval != nil && *src.RefreshInterval > 0 {
+		interval = time.Duration(*src.RefreshInterval) * time.Minute
+	}
+	if src.RateLimitPerMin != nil && *src.RateLimitPerMin > 0 {
+		if minInterval := time.Minute / time.Duration(*src.RateLimitPerMin); interval < minInterval {
+			interval = minInterval
+		}
+	}
+	return interval
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"nous-app/internal/fetcher"
+	"nous-app/internal/models"
+	"nous-app/internal/parser"
+)
+
+// ScrapePreviewLimit is how many items PreviewScrape returns when no limit is
+// given; MaxScrapePreviewLimit caps the limit.
+const (
+	ScrapePreviewLimit    = 5
+	MaxScrapePreviewLimit = 50
+)
+
+// ScrapePreview is the dry-run result of a source's scrape rules.
+type ScrapePreview struct {
+	Matched   int       `json:"matched"`   // Elements matched by the item selector
+	Extracted int       `json:"extracted"` // Items that yielded a title or link
+	Items     []Article `json:"items"`     // The first extracted items
+	Meta      FetchMeta `json:"meta"`      // How the page fetch went
+}
+
+// PreviewScrape fetches an "html" source's page and applies its scrape rules
+// without storing anything, returning the first limit extracted items so the
+// rules can be checked in the settings UI. The page is always fetched fresh,
+// bypassing the fetch cache.
+func (a *App) PreviewScrape(source Source, limit int, requestID string) string {
+	req := a.beginRequest(requestID, DefaultRequestTimeout)
+	defer req.end()
+
+	if source.Scrape == nil {
+		return req.fail(models.ErrorBadRequest, "Source has no scrape rules")
+	}
+	if limit <= 0 {
+		limit = ScrapePreviewLimit
+	}
+	if limit > MaxScrapePreviewLimit {
+		limit = MaxScrapePreviewLimit
+	}
+
+	res := fetcher.New(1).FetchOne(req.ctx, a.withSecret(source))
+	if res.Meta.Error != "" {
+		if req.ctx.Err() != nil {
+			return req.failWith("Error fetching page", req.ctx.Err())
+		}
+		return req.fail(models.ErrorUpstream, "Error fetching page: "+res.Meta.Error)
+	}
+
+	scraped, err := parser.Scrape(res.Body, source)
+	if err != nil {
+		return req.fail(models.ErrorBadRequest, err.Error())
+	}
+
+	items := scraped.Articles
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return req.ok(ScrapePreview{
+		Matched:   scraped.Matched,
+		Extracted: len(scraped.Articles),
+		Items:     items,
+		Meta:      res.Meta,
+	})
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"nous-app/internal/models"
+	"nous-app/internal/search"
+)
+
+// SearchFlushDelay is how long after the last change the search index is
+// written to disk, so a burst of saves costs one write.
+var SearchFlushDelay = 5 * time.Second
+
+var (
+	searchMu         sync.Mutex
+	searchSynced     bool        // The index has been synced with the node since startup
+	searchFlushTimer *time.Timer // Pending delayed flush, nil if none
+)
+
+// newSearchIndex opens the full-text index persisted next to sources.json.
+// A missing or unreadable index starts empty and is rebuilt from the node.
+func newSearchIndex() *search.Index {
+	x, err := search.Open(fmt.Sprintf("%s/search.index", DATA_PATH))
+	if err != nil {
+		log.Println("[Search] Starting with an empty index:", err)
+	}
+	return x
+}
+
+// SearchArticles searches the locally stored articles. query.Text accepts
+// words, "exact phrases", prefixes (infla*), exclusions (-word), field terms
+// (title:, summary:, content:, tag:, author:) and inline filters (source:,
+// bias:, edition:, lang:, after:, before:); the other query fields are
+// filters as well. Hits are ranked by BM25 and paged with Limit and Offset.
+// The data is search.Results, which carries article IDs, titles and URLs;
+// full articles are loaded with FetchLocalArticle.
+func (a *App) SearchArticles(query search.Query, requestID string) string {
+	req := a.beginRequest(requestID, DefaultRequestTimeout)
+	defer req.end()
+
+	searchMu.Lock()
+	synced := searchSynced
+	searchMu.Unlock()
+	if !synced {
+		if err := a.syncSearchIndex(req.ctx); err != nil {
+			log.Println("[Search] Searching without syncing the index:", err)
+		}
+	}
+
+	res, err := a.search.Search(query)
+	if err != nil {
+		return req.fail(models.ErrorBadRequest, fmt.Sprintf("Error searching articles: %v", err))
+	}
+	return req.ok(res)
+}
+
+// syncSearchIndex brings the index in line with the node's local articles,
+// indexing new and changed ones and dropping deleted ones.
+func (a *App) syncSearchIndex(ctx context.Context) error {
+	articles, err := a.nodeClient().LocalArticles(ctx)
+	if err != nil {
+		return err
+	}
+
+	updated, removed := a.search.Sync(articles, a.sourceBiases())
+	searchMu.Lock()
+	searchSynced = true
+	searchMu.Unlock()
+
+	log.Printf("[Search] Index synced: %d article(s), %d updated, %d removed\n", a.search.Len(), updated, removed)
+	if updated > 0 || removed > 0 {
+		a.flushSearchIndexLater()
+	}
+	return nil
+}
+
+// indexArticle adds or updates a saved article in the search index.
+func (a *App) indexArticle(article Article) {
+	a.indexArticles([]Article{article})
+}
+
+// indexArticles adds or updates a batch of saved articles in the search
+// index, reading the source biases once for the whole batch.
+func (a *App) indexArticles(articles []Article) {
+	if len(articles) == 0 {
+		return
+	}
+	biasOf := a.sourceBiases()
+	changed := false
+	for _, article := range articles {
+		if a.search.Put(article, biasOf) {
+			changed = true
+		}
+	}
+	if changed {
+		a.flushSearchIndexLater()
+	}
+}
+
+// unindexArticle removes a deleted article from the search index.
+func (a *App) unindexArticle(idOrURL string) {
+	if a.search.Delete(idOrURL) {
+		a.flushSearchIndexLater()
+	}
+}
+
+// flushSearchIndexLater writes the index SearchFlushDelay after the last
+// change.
+func (a *App) flushSearchIndexLater() {
+	searchMu.Lock()
+	defer searchMu.Unlock()
+
+	if searchFlushTimer != nil {
+		searchFlushTimer.Stop()
+	}
+	searchFlushTimer = time.AfterFunc(SearchFlushDelay, a.flushSearchIndex)
+}
+
+// flushSearchIndex writes pending index changes to disk.
+func (a *App) flushSearchIndex() {
+	if err := a.search.Flush(); err != nil {
+		log.Println("[Search] Failed to save index:", err)
+	}
+}
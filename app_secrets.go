@@ -0,0 +1,459 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nous-app/internal/models"
+	"nous-app/internal/persist"
+	"nous-app/internal/secrets"
+)
+
+// SecretMask stands in for a stored API key in the sources handed to the
+// frontend, so keys never leave the Go side. Sent back unchanged to
+// SaveSources or a fetch binding, it means "the key already stored".
+const SecretMask = "••••••••"
+
+// MinPassphraseLength is the shortest passphrase SetSecretsPassphrase accepts.
+const MinPassphraseLength = 8
+
+// SecretsStatus is the data of SecretsStatus, UnlockSecrets and
+// SetSecretsPassphrase.
+type SecretsStatus struct {
+	Mode   string `json:"mode"`   // "file" (key file next to the store) or "passphrase"
+	Locked bool   `json:"locked"` // Waiting for the passphrase; keys can't be read or saved
+	Keys   int    `json:"keys"`   // Number of stored keys, 0 while locked
+}
+
+// newSecretStore opens DATA_PATH/secrets.json. A store that can't be read
+// stays locked, so it is never overwritten with an empty one.
+func newSecretStore() *secrets.Store {
+	s, err := secrets.Open(fmt.Sprintf("%s/secrets.json", DATA_PATH), fmt.Sprintf("%s/secrets.key", DATA_PATH))
+	if err != nil {
+		log.Println("[Secrets] Secret store unavailable:", err)
+	}
+	return s
+}
+
+// SecretsStatus reports how API keys are protected and whether the secret
+// store is unlocked.
+func (a *App) SecretsStatus() string {
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	return req.ok(a.secretsStatus())
+}
+
+// UnlockSecrets unlocks a passphrase-protected secret store for the rest of
+// the session. API keys still stored in sources.json are moved into it.
+func (a *App) UnlockSecrets(passphrase string) string {
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	if err := a.secrets.Unlock(passphrase); err != nil {
+		if errors.Is(err, secrets.ErrWrongPassphrase) {
+			return req.fail(models.ErrorBadRequest, "Wrong passphrase")
+		}
+		return req.failWith("Error unlocking secrets", err)
+	}
+
+	// Move keys left in sources.json now that it can
+	sourcesMu.Lock()
+	err := a.prepareSources()
+	sourcesMu.Unlock()
+	if err != nil {
+		log.Println("[Secrets] Failed to prepare sources after unlocking:", err)
+	}
+	return req.ok(a.secretsStatus())
+}
+
+// SetSecretsPassphrase protects the secret store with passphrase, or with a
+// key file in the data directory when passphrase is empty. current must be
+// the passphrase in use, if any.
+func (a *App) SetSecretsPassphrase(current, passphrase string) string {
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	if passphrase != "" && len([]rune(passphrase)) < MinPassphraseLength {
+		return req.fail(models.ErrorBadRequest, fmt.Sprintf("Passphrase must have at least %d characters", MinPassphraseLength))
+	}
+	if err := a.secrets.Unlock(current); err != nil {
+		if errors.Is(err, secrets.ErrWrongPassphrase) {
+			return req.fail(models.ErrorBadRequest, "Wrong passphrase")
+		}
+		return req.failWith("Error unlocking secrets", err)
+	}
+	if err := a.secrets.SetPassphrase(passphrase); err != nil {
+		return req.failWith("Error changing passphrase", err)
+	}
+	return req.ok(a.secretsStatus())
+}
+
+func (a *App) secretsStatus() SecretsStatus {
+	return SecretsStatus{Mode: a.secrets.Mode(), Locked: a.secrets.Locked(), Keys: a.secrets.Len()}
+}
+
+// sourceKey matches sources by name, case-insensitively.
+func sourceKey(s Source) string {
+	return strings.ToLower(strings.TrimSpace(s.Name))
+}
+
+// sealSources moves the API keys of sources into the secret store and
+// leaves references in their place. A key equal to SecretMask keeps the
+// stored one, found by reference or, without one, by name in previous; an
+// empty key removes it; a source sent without a key keeps its reference.
+// Credential headers such as Authorization are sealed the same way, the
+// reference taking the place of the header value (see sealHeaders).
+func (a *App) sealSources(sources, previous []Source) error {
+	prev := make(map[string]Source, len(previous))
+	for _, p := range previous {
+		prev[sourceKey(p)] = p
+	}
+
+	for i := range sources {
+		src := &sources[i]
+		if err := a.sealHeaders(src, prev[sourceKey(*src)]); err != nil {
+			return err
+		}
+		if src.APIKey == nil {
+			continue
+		}
+		key := strings.TrimSpace(*src.APIKey)
+		src.APIKey = nil
+
+		if key == SecretMask {
+			if src.APIKeyRef != nil {
+				continue
+			}
+			p := prev[sourceKey(*src)]
+			if src.APIKeyRef = p.APIKeyRef; src.APIKeyRef != nil || p.APIKey == nil {
+				continue
+			}
+			// Previous key was never moved out of sources.json
+			key = strings.TrimSpace(*p.APIKey)
+		}
+		if key == "" {
+			src.APIKeyRef = nil
+			continue
+		}
+
+		ref, err := a.secrets.Put(key)
+		if err != nil {
+			return fmt.Errorf("failed to store the API key of %s: %w", src.Name, err)
+		}
+		src.APIKeyRef = &ref
+	}
+	return nil
+}
+
+// sealHeaders replaces the values of src's credential headers with secret
+// references. A value equal to SecretMask keeps the value of the same header
+// in prev; an empty one, or a masked one prev has no value for, removes the
+// header.
+func (a *App) sealHeaders(src *Source, prev Source) error {
+	if len(src.Headers) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(src.Headers))
+	for name, value := range src.Headers {
+		if !isSecretHeader(name) || isSecretRef(value) {
+			headers[name] = value
+			continue
+		}
+		if value == SecretMask {
+			value = headerValue(prev.Headers, name)
+			if value == "" || isSecretRef(value) {
+				if value != "" {
+					headers[name] = value
+				}
+				continue
+			}
+			// Previous value was never moved out of sources.json
+		}
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+
+		ref, err := a.secrets.Put(value)
+		if err != nil {
+			return fmt.Errorf("failed to store the %s header of %s: %w", name, src.Name, err)
+		}
+		headers[name] = ref
+	}
+	src.Headers = headers
+	return nil
+}
+
+// secretHeaderWords mark request headers that carry credentials, such as
+// Authorization, Cookie or X-Api-Key.
+var secretHeaderWords = []string{"auth", "token", "key", "secret", "password", "cookie", "session"}
+
+// isSecretHeader reports whether the value of header name is a credential,
+// which is kept in the secret store like an API key.
+func isSecretHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range secretHeaderWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// publicHeaders returns headers without the credential headers.
+func publicHeaders(headers map[string]string) map[string]string {
+	var public map[string]string
+	for name, value := range headers {
+		if isSecretHeader(name) {
+			continue
+		}
+		if public == nil {
+			public = make(map[string]string, len(headers))
+		}
+		public[name] = value
+	}
+	return public
+}
+
+// isSecretRef reports whether value is a secret store reference.
+func isSecretRef(value string) bool {
+	return strings.HasPrefix(value, secrets.RefPrefix)
+}
+
+// headerValue looks name up in headers, case-insensitively.
+func headerValue(headers map[string]string, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// forgetUnusedSecrets deletes the stored keys no source refers to anymore.
+func (a *App) forgetUnusedSecrets(sources []Source) {
+	keep := map[string]bool{}
+	for _, s := range sources {
+		if s.APIKeyRef != nil {
+			keep[*s.APIKeyRef] = true
+		}
+		for name, value := range s.Headers {
+			if isSecretHeader(name) && isSecretRef(value) {
+				keep[value] = true
+			}
+		}
+	}
+	if err := a.secrets.Retain(keep); err != nil && !errors.Is(err, secrets.ErrLocked) {
+		log.Println("[Secrets] Failed to delete unused keys:", err)
+	}
+}
+
+// hasPlaintextKeys reports whether any source carries its API key or a
+// credential header value itself.
+func hasPlaintextKeys(sources []Source) bool {
+	for _, s := range sources {
+		if s.APIKey != nil {
+			return true
+		}
+		for name, value := range s.Headers {
+			if isSecretHeader(name) && !isSecretRef(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// moveKeysToSecrets seals the API keys and credential headers sources.json
+// still holds, saves it and deletes the backups and migration copies that
+// contain them.
+func (a *App) moveKeysToSecrets(file *persist.File, sources []Source) error {
+	if err := a.sealSources(sources, nil); err != nil {
+		return err
+	}
+	if err := a.saveSources(sources); err != nil {
+		return err
+	}
+
+	if err := file.RemoveBackups(); err != nil {
+		return fmt.Errorf("failed to delete sources backups: %w", err)
+	}
+	copies, _ := filepath.Glob(fmt.Sprintf("%s/sources.v*.json", DATA_PATH))
+	for _, path := range copies {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", filepath.Base(path), err)
+		}
+	}
+	log.Println("[Secrets] Moved API keys from sources.json into the secret store and deleted the backups holding them")
+	return nil
+}
+
+// maskSources replaces the API keys and credential header values of sources
+// with SecretMask, for returning them to the frontend.
+func maskSources(sources []Source) []Source {
+	mask := SecretMask
+	for i := range sources {
+		if sources[i].APIKey != nil || sources[i].APIKeyRef != nil {
+			sources[i].APIKey = &mask
+		}
+		if len(sources[i].Headers) == 0 {
+			continue
+		}
+		headers := make(map[string]string, len(sources[i].Headers))
+		for name, value := range sources[i].Headers {
+			if isSecretHeader(name) {
+				value = SecretMask
+			}
+			headers[name] = value
+		}
+		sources[i].Headers = headers
+	}
+	return sources
+}
+
+// withSecrets returns a copy of sources with their API keys and credential
+// headers read from the secret store. It is called right before fetching, so
+// keys are held in memory only for the fetch. A plaintext key sent by the
+// caller, such as an unsaved key being tried out, is used as is. Stored keys
+// and headers, asked for with SecretMask or a reference, come from the saved
+// source of the same name only, and only when the source's endpoint is on
+// the saved endpoint's host, so a stored key is never sent elsewhere.
+// Sources whose key can't be read are fetched without one.
+func (a *App) withSecrets(sources []Source) []Source {
+	var saved map[string]Source
+	out := make([]Source, len(sources))
+	for i, src := range sources {
+		if needsStoredSecrets(src) && saved == nil {
+			saved = a.savedSourcesByName()
+		}
+		p, found := saved[sourceKey(src)]
+		if found && !sameHost(src.Endpoint, p.Endpoint) {
+			log.Printf("[Secrets] Not sending the stored credentials of %s to %s, which is not its saved endpoint's host\n", src.Name, hostOf(src.Endpoint))
+			found = false
+		}
+		if !found {
+			p = Source{}
+		}
+
+		src.APIKey = a.apiKey(src, p)
+		src.Headers = a.headers(src, p)
+		out[i] = src
+	}
+	return out
+}
+
+// needsStoredSecrets reports whether src asks for a stored key or header.
+func needsStoredSecrets(src Source) bool {
+	if src.APIKeyRef != nil || (src.APIKey != nil && *src.APIKey == SecretMask) {
+		return true
+	}
+	for name, value := range src.Headers {
+		if isSecretHeader(name) && (value == SecretMask || isSecretRef(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKey returns the API key src is fetched with, or nil. saved is the saved
+// source src may take its stored key from, empty if none.
+func (a *App) apiKey(src, saved Source) *string {
+	if src.APIKey != nil && *src.APIKey != SecretMask {
+		return src.APIKey
+	}
+	if src.APIKey == nil && src.APIKeyRef == nil {
+		return nil
+	}
+	if src.APIKeyRef != nil && (saved.APIKeyRef == nil || *src.APIKeyRef != *saved.APIKeyRef) {
+		log.Printf("[Secrets] Ignoring an API key reference that is not the saved key of %s\n", src.Name)
+		return nil
+	}
+
+	if saved.APIKeyRef == nil {
+		// Not moved into the store yet, or no key saved
+		return saved.APIKey
+	}
+	if key, ok := a.readSecret(src, "API key", *saved.APIKeyRef); ok {
+		return &key
+	}
+	return nil
+}
+
+// headers returns a copy of src.Headers with the credential values read from
+// the secret store. Masked values and references are taken from the same
+// header of saved; headers whose value can't be read are left out.
+func (a *App) headers(src, saved Source) map[string]string {
+	if len(src.Headers) == 0 {
+		return src.Headers
+	}
+	headers := make(map[string]string, len(src.Headers))
+	for name, value := range src.Headers {
+		if isSecretHeader(name) && (value == SecretMask || isSecretRef(value)) {
+			stored := headerValue(saved.Headers, name)
+			if isSecretRef(value) && value != stored {
+				log.Printf("[Secrets] Ignoring a %s header reference that is not the saved one of %s\n", name, src.Name)
+				continue
+			}
+			if value = stored; isSecretRef(value) {
+				value, _ = a.readSecret(src, name+" header", value)
+			}
+			if value == "" {
+				continue
+			}
+		}
+		headers[name] = value
+	}
+	return headers
+}
+
+// sameHost reports whether two endpoints are on the same host.
+func sameHost(a, b string) bool {
+	host := hostOf(a)
+	return host != "" && strings.EqualFold(host, hostOf(b))
+}
+
+// hostOf returns the host name of endpoint, or "".
+func hostOf(endpoint string) string {
+	u, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// readSecret reads a secret of src from the store, logging why it can't.
+func (a *App) readSecret(src Source, what, ref string) (string, bool) {
+	value, ok, err := a.secrets.Get(ref)
+	switch {
+	case err != nil:
+		log.Printf("[Secrets] No %s for %s: %v\n", what, src.Name, err)
+	case !ok:
+		log.Printf("[Secrets] %s of %s is missing from the secret store\n", what, src.Name)
+	}
+	return value, err == nil && ok
+}
+
+// withSecret is withSecrets for a single source.
+func (a *App) withSecret(source Source) Source {
+	return a.withSecrets([]Source{source})[0]
+}
+
+// savedSourcesByName returns the sources of sources.json by sourceKey.
+func (a *App) savedSourcesByName() map[string]Source {
+	sourcesMu.Lock()
+	sources, err := a.loadSources()
+	sourcesMu.Unlock()
+	if err != nil {
+		log.Println("[Secrets] Failed to load sources:", err)
+	}
+	byName := make(map[string]Source, len(sources))
+	for _, s := range sources {
+		byName[sourceKey(s)] = s
+	}
+	return byName
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestApp returns an App whose data directory is a fresh temp dir.
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	saved := DATA_PATH
+	DATA_PATH = t.TempDir()
+	t.Cleanup(func() { DATA_PATH = saved })
+	return &App{secrets: newSecretStore()}
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestSealSources(t *testing.T) {
+	a := newTestApp(t)
+	oldRef, err := a.secrets.Put("old-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	headerRef, err := a.secrets.Put("Bearer old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := []Source{
+		{Name: "Ref", APIKeyRef: &oldRef, Headers: map[string]string{"Authorization": headerRef}},
+		{Name: "Plain", APIKey: strPtr("plain-key"), Headers: map[string]string{"X-Token": "plain-token"}},
+	}
+
+	tests := []struct {
+		name       string
+		src        Source
+		wantKey    string // Secret the APIKeyRef points to, "" for no reference
+		wantHeader string // Secret of the credential header, "" for no header
+		header     string
+	}{
+		{"new key", Source{Name: "New", APIKey: strPtr(" new-key ")}, "new-key", "", ""},
+		{"masked key keeps reference", Source{Name: "ref", APIKey: strPtr(SecretMask)}, "old-key", "", ""},
+		{"masked key moves plaintext", Source{Name: "Plain", APIKey: strPtr(SecretMask)}, "plain-key", "", ""},
+		{"masked key without previous", Source{Name: "Other", APIKey: strPtr(SecretMask)}, "", "", ""},
+		{"empty key removes it", Source{Name: "Ref", APIKey: strPtr(""), APIKeyRef: &oldRef}, "", "", ""},
+		{"no key keeps reference", Source{Name: "Ref", APIKeyRef: &oldRef}, "old-key", "", ""},
+		{"new header", Source{Name: "New", Headers: map[string]string{"Authorization": "Bearer new"}}, "", "Bearer new", "Authorization"},
+		{"masked header keeps reference", Source{Name: "Ref", Headers: map[string]string{"authorization": SecretMask}}, "", "Bearer old", "authorization"},
+		{"masked header moves plaintext", Source{Name: "Plain", Headers: map[string]string{"X-Token": SecretMask}}, "", "plain-token", "X-Token"},
+		{"masked header without previous", Source{Name: "New", Headers: map[string]string{"Cookie": SecretMask}}, "", "", "Cookie"},
+		{"empty header removes it", Source{Name: "Ref", Headers: map[string]string{"Authorization": ""}}, "", "", "Authorization"},
+	}
+	for _, tt := range tests {
+		sources := []Source{tt.src}
+		if err := a.sealSources(sources, previous); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got := sources[0]
+		if got.APIKey != nil {
+			t.Errorf("%s: APIKey %q left in the source", tt.name, *got.APIKey)
+		}
+		switch {
+		case tt.wantKey == "" && got.APIKeyRef != nil:
+			t.Errorf("%s: unexpected reference %s", tt.name, *got.APIKeyRef)
+		case tt.wantKey != "":
+			if got.APIKeyRef == nil {
+				t.Errorf("%s: no reference", tt.name)
+			} else if v, _, _ := a.secrets.Get(*got.APIKeyRef); v != tt.wantKey {
+				t.Errorf("%s: reference points to %q, want %q", tt.name, v, tt.wantKey)
+			}
+		}
+		if tt.header == "" {
+			continue
+		}
+		value, ok := got.Headers[tt.header]
+		switch {
+		case tt.wantHeader == "" && ok:
+			t.Errorf("%s: header kept as %q", tt.name, value)
+		case tt.wantHeader != "":
+			if !isSecretRef(value) {
+				t.Errorf("%s: header value %q is not a reference", tt.name, value)
+			} else if v, _, _ := a.secrets.Get(value); v != tt.wantHeader {
+				t.Errorf("%s: header points to %q, want %q", tt.name, v, tt.wantHeader)
+			}
+		}
+	}
+
+	// Other headers pass through untouched
+	sources := []Source{{Name: "New", Headers: map[string]string{"Accept-Language": "en"}}}
+	if err := a.sealSources(sources, nil); err != nil || sources[0].Headers["Accept-Language"] != "en" {
+		t.Errorf("plain header: %v, %v", sources[0].Headers, err)
+	}
+}
+
+func TestMaskSources(t *testing.T) {
+	ref := "secret:0011223344556677"
+	sources := maskSources([]Source{{
+		Name:      "A",
+		APIKeyRef: &ref,
+		Headers:   map[string]string{"Authorization": ref, "Accept": "application/json"},
+	}})
+	got := sources[0]
+	if got.APIKey == nil || *got.APIKey != SecretMask {
+		t.Errorf("APIKey = %v, want the mask", got.APIKey)
+	}
+	if got.Headers["Authorization"] != SecretMask || got.Headers["Accept"] != "application/json" {
+		t.Errorf("Headers = %v", got.Headers)
+	}
+}
+
+func TestMoveKeysToSecrets(t *testing.T) {
+	a := newTestApp(t)
+	legacy := `[{"name":"A","url":"https://a.test/feed","apiKey":"k1","headers":{"Authorization":"Bearer t1"}}]`
+	if err := os.WriteFile(filepath.Join(DATA_PATH, "sources.json"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Older versions and backups of sources.json hold the keys too
+	for _, name := range []string{"sources.json.1", "sources.json.2", "sources.v0.json"} {
+		os.WriteFile(filepath.Join(DATA_PATH, name), []byte(legacy), 0644)
+	}
+
+	sources, err := a.loadSources()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasPlaintextKeys(sources) {
+		t.Fatal("hasPlaintextKeys missed the legacy keys")
+	}
+	if err := a.prepareSources(); err != nil {
+		t.Fatal(err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(DATA_PATH, "sources*"))
+	if len(matches) != 1 || filepath.Base(matches[0]) != "sources.json" {
+		t.Errorf("left behind: %v", matches)
+	}
+	data, err := os.ReadFile(filepath.Join(DATA_PATH, "sources.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "k1") || strings.Contains(string(data), "Bearer t1") {
+		t.Errorf("sources.json still holds a key: %s", data)
+	}
+
+	sources, err = a.loadSources()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasPlaintextKeys(sources) {
+		t.Error("hasPlaintextKeys after moving the keys")
+	}
+	got := a.withSecrets(sources)[0]
+	if got.APIKey == nil || *got.APIKey != "k1" || got.Headers["Authorization"] != "Bearer t1" {
+		t.Errorf("withSecrets after the move: key %v, headers %v", got.APIKey, got.Headers)
+	}
+}
+
+func TestWithSecretsHost(t *testing.T) {
+	a := newTestApp(t)
+	saved := []Source{{
+		Name:     "NewsAPI",
+		Endpoint: "https://newsapi.org/v2/top-headlines",
+		APIKey:   strPtr("stored-key"),
+		Headers:  map[string]string{"Authorization": "Bearer stored"},
+		Parser:   "json",
+	}}
+	if res := a.SaveSources(saved); !strings.Contains(res, `"success":true`) {
+		t.Fatal(res)
+	}
+	stored, err := a.loadSources()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := *stored[0].APIKeyRef
+	otherRef, _ := a.secrets.Put("other-key")
+
+	masked := map[string]string{"Authorization": SecretMask}
+	tests := []struct {
+		name       string
+		src        Source
+		wantKey    string
+		wantHeader string
+	}{
+		{"saved endpoint", Source{Name: "NewsAPI", Endpoint: "https://newsapi.org/v2/everything", APIKey: strPtr(SecretMask), Headers: masked}, "stored-key", "Bearer stored"},
+		{"saved reference", Source{Name: "newsapi", Endpoint: "https://NEWSAPI.org/v2", APIKeyRef: &ref}, "stored-key", ""},
+		{"other host", Source{Name: "NewsAPI", Endpoint: "https://evil.test/collect", APIKey: strPtr(SecretMask), Headers: masked}, "", ""},
+		{"other host by reference", Source{Name: "NewsAPI", Endpoint: "https://evil.test/collect", APIKeyRef: &ref}, "", ""},
+		{"unsaved reference", Source{Name: "NewsAPI", Endpoint: "https://newsapi.org/v2", APIKeyRef: &otherRef}, "", ""},
+		{"reference of another source", Source{Name: "Unknown", Endpoint: "https://newsapi.org/v2", APIKeyRef: &ref}, "", ""},
+		{"plaintext key", Source{Name: "NewsAPI", Endpoint: "https://evil.test/", APIKey: strPtr("typed-key")}, "typed-key", ""},
+	}
+	for _, tt := range tests {
+		got := a.withSecret(tt.src)
+		key := ""
+		if got.APIKey != nil {
+			key = *got.APIKey
+		}
+		if key != tt.wantKey || got.Headers["Authorization"] != tt.wantHeader {
+			t.Errorf("%s: key %q, Authorization %q; want %q, %q", tt.name, key, got.Headers["Authorization"], tt.wantKey, tt.wantHeader)
+		}
+	}
+}
@@ -1,96 +1,274 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"log"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
+
+	"nous-app/internal/models"
+	"nous-app/internal/persist"
+	"nous-app/internal/sourcefile"
 )
 
-// SaveSources persists sources locally (e.g., JSON file)
-func (a *App) SaveSources(sources []Source) error {
-	if err := os.MkdirAll(DATA_PATH, os.ModePerm); err != nil {
-		return fmt.Errorf("failed to create data directory: %w", err)
+// sourcesMu serializes writes to sources.json (SaveSources, imports and
+// prepareSources), so a read-modify-write never interleaves with another.
+var sourcesMu sync.Mutex
+
+// SaveSources validates and persists sources to sources.json. Invalid
+// sources fail with the "validation_failed" code and the issues as data
+// (see sourcefile.Issue); nothing is saved then. LastUpdated is set on the
+// sources that are new or changed. API keys go to the secret store, with
+// sources.json keeping a reference (see sealSources); while the store is
+// locked, saving a new key fails with "secrets_locked".
+func (a *App) SaveSources(sources []Source) string {
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	if issues := sourcefile.Validate(sources); len(issues) > 0 {
+		return req.invalid(fmt.Sprintf("%d invalid source setting(s): %v", len(issues), issues[0]), issues)
 	}
 
-	data, err := json.Marshal(sources)
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+
+	previous, err := a.loadSources()
 	if err != nil {
-		return err
+		return req.fail(models.ErrorInternal, fmt.Sprintf("Error loading sources: %v", err))
+	}
+	if err := a.sealSources(sources, previous); err != nil {
+		return req.failWith("Error saving API keys", err)
 	}
+	sourcefile.Stamp(sources, previous, time.Now().Format(time.RFC3339))
 
-	return os.WriteFile(fmt.Sprintf("%s/sources.json", DATA_PATH), data, 0644)
+	if err := a.saveSources(sources); err != nil {
+		return req.fail(models.ErrorInternal, fmt.Sprintf("Error saving sources: %v", err))
+	}
+	a.forgetUnusedSecrets(sources)
+	return req.ok(maskSources(sources))
 }
 
-// LoadSources loads sources from local file
-func (a *App) LoadSources() ([]Source, error) {
-	if _, err := os.Stat(DATA_PATH); os.IsNotExist(err) {
-		return nil, nil
+// LoadSources loads sources from local file. Stored API keys come back as
+// SecretMask.
+func (a *App) LoadSources() string {
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	sources, err := a.loadSources()
+	if err != nil {
+		return req.fail(models.ErrorInternal, fmt.Sprintf("Error loading sources: %v", err))
 	}
+	if sources == nil {
+		sources = []Source{}
+	}
+	return req.ok(maskSources(sources))
+}
 
-	data, err := os.ReadFile(fmt.Sprintf("%s/sources.json", DATA_PATH))
+// ValidateSources checks sources without saving them. The data is the list
+// of issues found, empty when all sources are valid.
+func (a *App) ValidateSources(sources []Source) string {
+	req := a.beginRequest("", DefaultRequestTimeout)
+	defer req.end()
+
+	issues := sourcefile.Validate(sources)
+	if issues == nil {
+		issues = []sourcefile.Issue{}
+	}
+	return req.ok(issues)
+}
+
+// sourcesFile is sources.json, written atomically with rotating backups.
+func sourcesFile() *persist.File {
+	return persist.NewFile(fmt.Sprintf("%s/sources.json", DATA_PATH))
+}
+
+// saveSources writes sources to DATA_PATH/sources.json. LastFetched is
+// left out; it is kept in the fetch state file (see markSourcesFetched).
+func (a *App) saveSources(sources []Source) error {
+	stored := make([]Source, len(sources))
+	for i, s := range sources {
+		s.LastFetched = nil
+		stored[i] = s
+	}
+	data, err := sourcefile.Encode(stored)
 	if err != nil {
+		return err
+	}
+	return sourcesFile().Save(data)
+}
+
+// checkSources is the persist check for sources.json.
+func checkSources(data []byte) error {
+	_, _, err := sourcefile.Decode(data)
+	if errors.Is(err, sourcefile.ErrNewerVersion) {
+		// Intact, just not ours to read; don't roll it back
+		return nil
+	}
+	return err
+}
+
+// loadSources reads sources from DATA_PATH/sources.json without writing
+// anything. A missing file means no sources yet; an unreadable or corrupt one
+// is read from its newest good backup, and a file in an older layout is
+// migrated in memory (prepareSources fixes both on disk). Sources are not
+// validated here so invalid ones can still be loaded and fixed.
+func (a *App) loadSources() ([]Source, error) {
+	data, _, err := sourcesFile().Read(checkSources)
+	if errors.Is(err, os.ErrNotExist) {
 		return nil, nil
 	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sources: %w", err)
+	}
 
-	var sources []Source
-	if err := json.Unmarshal(data, &sources); err != nil {
+	sources, _, err := sourcefile.Decode(data)
+	if err != nil {
 		return nil, err
 	}
 
-	t := time.Now()
-	iso := t.Format(time.RFC3339)
+	state := loadFetchState()
+	for i := range sources {
+		if ts, ok := state[sources[i].Name]; ok {
+			sources[i].LastFetched = &ts
+		}
+	}
 
 	// Optional: auto-enable if APIKey exists
 	for i := range sources {
-		sources[i].LastUpdated = &iso
 		if sources[i].Enabled == nil {
 			sources[i].Enabled = new(bool)
-			*sources[i].Enabled = sources[i].APIKey != nil
+			*sources[i].Enabled = sources[i].APIKey != nil || sources[i].APIKeyRef != nil
 		}
 	}
 
 	return sources, nil
 }
 
-// FetchArticlesBySources fetches raw data from all sources and returns a map keyed by source name
-func (a *App) FetchArticlesBySources(sources []Source) (ArticlesBySource, error) {
-	url := fmt.Sprintf("%s/articles/local/fetch", GetNodeBaseUrl())
-	body := map[string]interface{}{"sources": sources}
-
-	bodyJSON, err := json.Marshal(body)
+// prepareSources brings sources.json up to date on disk: a corrupt file is
+// replaced by its newest good backup, a file in an older layout is migrated
+// and rewritten, with the original kept next to it as sources.v<N>.json, and
+// API keys saved before the secret store existed are moved into it once it
+// is unlocked. It runs at startup and after unlocking the secret store;
+// callers hold sourcesMu.
+func (a *App) prepareSources() error {
+	file := sourcesFile()
+	data, recovered, err := file.Load(checkSources)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal sources: %w", err)
+		return fmt.Errorf("failed to read sources: %w", err)
+	}
+	if recovered != "" {
+		log.Printf("[Sources] sources.json was unreadable, restored %s\n", filepath.Base(recovered))
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(bodyJSON))
+	sources, version, err := sourcefile.Decode(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build request: %w", err)
+		return err
+	}
+	if version < sourcefile.Version {
+		backup := fmt.Sprintf("%s/sources.v%d.json", DATA_PATH, version)
+		if err := persist.WriteFile(backup, data, file.Perm); err != nil {
+			return fmt.Errorf("failed to back up sources before migrating: %w", err)
+		}
+		if err := a.saveSources(sources); err != nil {
+			return fmt.Errorf("failed to save migrated sources: %w", err)
+		}
+		log.Printf("[Sources] Migrated sources.json from version %d to %d (backup: %s)\n", version, sourcefile.Version, filepath.Base(backup))
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("POST request failed: %w", err)
+	if hasPlaintextKeys(sources) && !a.secrets.Locked() {
+		if err := a.moveKeysToSecrets(file, sources); err != nil {
+			return fmt.Errorf("failed to move API keys into the secret store: %w", err)
+		}
 	}
-	defer resp.Body.Close()
+	return nil
+}
+
+// fetchStateMu serializes writes to sources.state.json.
+var fetchStateMu sync.Mutex
 
-	respBody, err := io.ReadAll(resp.Body)
+// fetchStatePath is sources.state.json, which holds LastFetched per source
+// name. It changes every scheduler round, so it is kept apart from
+// sources.json and its backups.
+func fetchStatePath() string {
+	return fmt.Sprintf("%s/sources.state.json", DATA_PATH)
+}
+
+// loadFetchState reads LastFetched per source name; a missing or unreadable
+// file means nothing was fetched yet.
+func loadFetchState() map[string]string {
+	state := map[string]string{}
+	data, err := os.ReadFile(fetchStatePath())
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Println("[Sources] Failed to read fetch state:", err)
+		}
+		return state
 	}
-
-	var respObj map[string]json.RawMessage
-	if err := json.Unmarshal(respBody, &respObj); err != nil {
-		return nil, fmt.Errorf("failed to parse response JSON: %w", err)
+	if err := json.Unmarshal(data, &state); err != nil {
+		log.Println("[Sources] Ignoring unreadable fetch state:", err)
+		return map[string]string{}
 	}
+	return state
+}
 
-	grouped := make(ArticlesBySource)
-	for name, raw := range respObj {
-		grouped[name] = raw // store raw JSON/XML/HTML per source
+// markSourcesFetched records LastFetched for the named sources.
+func (a *App) markSourcesFetched(fetched map[string]string) error {
+	fetchStateMu.Lock()
+	defer fetchStateMu.Unlock()
+
+	state := loadFetchState()
+	for name, ts := range fetched {
+		state[name] = ts
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
 	}
+	return persist.WriteFile(fetchStatePath(), data, 0644)
+}
 
-	return grouped, nil
+// FetchArticlesBySources fetches the sources from Go, honouring each source's
+// endpoint, headers, auth and rate limit, parses the payloads into articles
+// and adds the new ones to the node's local store. The data is a
+// FetchResult: articles maps each source name to the JSON array of its
+// articles, meta holds per-source metadata (status code, bytes, duration,
+// error). Requests are conditional on the source's ETag/Last-Modified and
+// skipped while its max-age or Retry-After holds; a source that has not
+// changed gets an empty array and meta.notModified. Sources that failed to
+// fetch, parse or store are only in meta.
+// API keys are read from the secret store just before fetching.
+func (a *App) FetchArticlesBySources(sources []Source, requestID string) string {
+	req := a.beginRequest(requestID, LongRequestTimeout)
+	defer req.end()
+
+	result := FetchResult{
+		Articles: make(ArticlesBySource),
+		Meta:     make(map[string]FetchMeta),
+	}
+	for r := range a.fetchArticles(req.ctx, a.withSecrets(sources)) {
+		name := r.Source.Name
+		if a.storeFetched(req.ctx, &r) {
+			if r.Articles == nil {
+				// Not modified (304 or still fresh): no new articles
+				r.Articles = []Article{}
+			}
+			if raw, err := json.Marshal(r.Articles); err != nil {
+				r.Meta.Error = fmt.Sprintf("failed to encode articles: %v", err)
+			} else {
+				result.Articles[name] = raw
+			}
+		}
+		result.Meta[name] = r.Meta
+	}
+	if err := req.ctx.Err(); err != nil {
+		return req.failWith("Error fetching sources", err)
+	}
+	return req.ok(result)
 }
@@ -1,65 +1,48 @@
 package main
 
 import (
-	"bytes"
+	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+
+	"nous-app/internal/models"
 )
 
 // GET /status
 func (a *App) AppStatus() string {
-	url := GetNodeBaseUrl() + "/status"
+	req := a.beginRequest("", StatusRequestTimeout)
+	defer req.end()
 
-	resp, err := http.Get(url)
+	status, err := a.nodeClient().Status(req.ctx)
 	if err != nil {
-		return fmt.Sprintf(
-			`{"running": false, "port": %d, "error": "GET failed: %v"}`,
-			instanceHTTPPort(),
-			err,
-		)
+		return req.failWith(fmt.Sprintf("Error fetching status on port %d", instanceHTTPPort()), err)
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	return string(body)
+	return req.ok(status)
 }
 
 // POST /status
 func (a *App) AppUpdateStatus(jsonPayload string) string {
-	url := GetNodeBaseUrl() + "/status"
+	req := a.beginRequest("", StatusRequestTimeout)
+	defer req.end()
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer([]byte(jsonPayload)))
-	if err != nil {
-		return fmt.Sprintf(`{"error":"request build failed: %v"}`, err)
+	var patch map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonPayload), &patch); err != nil {
+		return req.fail(models.ErrorBadRequest, fmt.Sprintf("Error updating status: invalid payload: %v", err))
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	status, err := a.nodeClient().UpdateStatus(req.ctx, patch)
 	if err != nil {
-		return fmt.Sprintf(`{"error":"POST failed: %v"}`, err)
+		return req.failWith("Error updating status", err)
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	return string(body)
+	return req.ok(status)
 }
 
 // DELETE /status
 func (a *App) AppDeleteStatus() string {
-	url := GetNodeBaseUrl() + "/status"
+	req := a.beginRequest("", StatusRequestTimeout)
+	defer req.end()
 
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return fmt.Sprintf(`{"error":"request build failed: %v"}`, err)
+	if err := a.nodeClient().DeleteStatus(req.ctx); err != nil {
+		return req.failWith("Error deleting status", err)
 	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Sprintf(`{"error":"DELETE failed: %v"}`, err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	return string(body)
+	return req.ok(map[string]bool{"deleted": true})
 }
@@ -1,16 +1,15 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
-	"io"
 	"log"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // KillLingeringNode kills any lingering bundled node processes
@@ -74,64 +73,34 @@ func CleanOrbitDBLocks() {
 	}
 }
 
+// emitEvent sends a Wails event to the frontend, once the runtime context is available
+func (a *App) emitEvent(name string, data ...interface{}) {
+	if a.ctx == nil {
+		return
+	}
+	wailsruntime.EventsEmit(a.ctx, name, data...)
+}
+
 // Per-instance port helper
 func instanceHTTPPort() int {
 	return httpPortBase + instanceID
 }
 
-// HTTP GET helper with debug logging
-func get(url string) (string, error) {
-	log.Printf("GET %s\n", url)
-
-	resp, err := http.Get(url)
+// toJSON marshals a binding result for the frontend
+func toJSON(v interface{}) string {
+	b, err := json.Marshal(v)
 	if err != nil {
-		log.Printf("GET ERROR %s -> %v\n", url, err)
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	log.Printf("GET %s -> status %d\n", url, resp.StatusCode)
-
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("GET READ ERROR %s -> %v\n", url, err)
-		return "", err
-	}
-
-	// Optional: print truncated body (helps debug HTML errors)
-	if len(b) > 0 {
-		preview := b
-		if len(b) > 300 {
-			preview = b[:300]
-		}
-		log.Printf("GET %s -> body (first 300 bytes): %s\n", url, string(preview))
+		log.Printf("Error encoding response: %v", err)
+		return "null"
 	}
-
-	return string(b), nil
+	return string(b)
 }
 
-// HTTP POST helper
-func post(url string, data interface{}) (string, error) {
-	// Marshal payload to JSON
-	payload, err := json.Marshal(data)
-	if err != nil {
-		return "", err
-	}
-
-	// Log the payload
-	log.Printf("POST %s\nPayload: %s\n", url, string(payload))
-
-	// Send the POST request
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(payload))
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	// Read response body
-	b, err := io.ReadAll(resp.Body)
+// decodeMap converts a loosely typed binding argument into a model struct
+func decodeMap(m map[string]interface{}, out interface{}) error {
+	b, err := json.Marshal(m)
 	if err != nil {
-		return "", err
+		return err
 	}
-	return string(b), nil
+	return json.Unmarshal(b, out)
 }
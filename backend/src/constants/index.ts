@@ -3,4 +3,7 @@ export const STATUS_FILE_NAME = "app-status.json";
 export const STATUS_FILE_PATH = `backend/dist/data/${STATUS_FILE_NAME}`;
 
 export const DB_PATH_FILE = "path.json";
-export const DB_PATH_FILE_PATH = `backend/dist/data/${DB_PATH_FILE}`;
\ No newline at end of file
+export const DB_PATH_FILE_PATH = `backend/dist/data/${DB_PATH_FILE}`;
+
+// Reported to the Go app in the READY handshake; keep in sync with package.json
+export const NODE_VERSION = "0.1.1";
@@ -1,6 +1,6 @@
 import fs from "node:fs";
 import path from "node:path";
-import { DB_PATH_FILE_PATH } from "@/constants";
+import { DB_PATH_FILE_PATH, NODE_VERSION } from "@/constants";
 import { log } from "@/lib/log.server";
 import { loadStatus, updateStatus } from "@/lib/status.server";
 import type { NodeConfig } from "@/types";
@@ -167,6 +167,15 @@ export async function startP2PNode(config: NodeConfig): Promise<NodeInstance> {
 
 	setRunningInstance(runningInstance);
 
+	// Structured READY handshake consumed by the Go app (StartP2PNode)
+	console.log(
+		`READY ${JSON.stringify({
+			httpPort,
+			peerId: libp2p.peerId.toString(),
+			version: NODE_VERSION,
+		})}`,
+	);
+
 	// Prefetch AI models first
 	try {
 		console.log("Prefetching AI models...");
@@ -17,7 +17,7 @@ export type NormalizerFn = (entry: any, source: Source) => Article;
 export type ArticleNormalizer = (article: any, source?: string) => Article;
 
 /** Valid normalizer types */
-export const SourceNormalizers = ["json", "rss", "gdelt", "hn", "reddit"] as const;
+export const SourceNormalizers = ["json", "jsonfeed", "rss", "gdelt", "hn", "reddit"] as const;
 
 /** Zod schema for normalizer names */
 export const SourceNormalizerSchema = z.enum(SourceNormalizers);
@@ -4,13 +4,14 @@ import { type Article, ArticleSchema, type Source } from "@/types";
 /**
  * Supported parser types for different sources.
  * - `"json"`: Source returns JSON payload
+ * - `"jsonfeed"`: Source is a JSON Feed (jsonfeed.org, 1.0 or 1.1)
  * - `"rss"`: Source is an RSS feed
  * - `"gdelt"`: GDELT-style structured data
  * - `"html"`: HTML content requiring parsing
  * - `"hn"`: Hacker News
  * - `"reddit"`: Reddit API / posts
  */
-export const SourceParsers = ["json", "rss", "gdelt", "html", "hn", "reddit"] as const;
+export const SourceParsers = ["json", "jsonfeed", "rss", "gdelt", "html", "hn", "reddit"] as const;
 export const SourceParserSchema = z.enum(SourceParsers);
 export type SourceParser = z.infer<typeof SourceParserSchema>;
 
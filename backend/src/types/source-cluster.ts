@@ -55,10 +55,40 @@ export const StoryClusterSchema = z.object({
 
   /** Optional timestamp for when the cluster was created or updated */
   lastUpdated: z.string().optional(),
+
+  /** Optional IDs of the articles in the cluster, oldest first */
+  articleIds: z.array(z.string()).optional(),
 });
 
 export type StoryCluster = z.infer<typeof StoryClusterSchema>;
 
+/**
+ * A story that one side of the political spectrum barely covers.
+ * `side` is the side missing the story; shares are 0-100 of the sources of known bias.
+ */
+export const BlindspotSchema = StoryClusterSchema.extend({
+  side: z.enum(["left", "right"]),
+  leftShare: z.number().min(0).max(100),
+  rightShare: z.number().min(0).max(100),
+  rated: z.number().int().nonnegative(),
+});
+
+export type Blindspot = z.infer<typeof BlindspotSchema>;
+
+/**
+ * Blindspots of a period, as returned by the GetBlindspotReport binding.
+ */
+export const BlindspotReportSchema = z.object({
+  since: z.string(),
+  until: z.string(),
+  /** Number of story clusters considered */
+  stories: z.number().int().nonnegative(),
+  /** Most widely covered first */
+  blindspots: z.array(BlindspotSchema),
+});
+
+export type BlindspotReport = z.infer<typeof BlindspotReportSchema>;
+
 /**
  * Utility function to compute coverage percentages from a list of sources.
  */
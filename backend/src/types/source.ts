@@ -73,6 +73,42 @@ export const OwnershipSchema = z.object({
 /** TypeScript type inferred from OwnershipSchema */
 export type Ownership = z.infer<typeof OwnershipSchema>;
 
+/**
+ * Zod schema describing how the "html" parser extracts articles from a page.
+ *
+ * `item` is a CSS selector for the element wrapping each article; the other
+ * rules are selectors evaluated inside it. A rule may end in "@attr" to read
+ * an attribute instead of the text, e.g. "a.headline@href" or "img@data-src".
+ */
+export const ScrapeRulesSchema = z.object({
+	/** Selector for each article's container, e.g. "ul.stories > li" */
+	item: z.string().min(1),
+
+	/** Selector for the headline (falls back to the link text) */
+	title: z.string(),
+
+	/** Selector for the article link (defaults to the first a[href]) */
+	link: z.string().optional(),
+
+	/** Selector for the publication date (reads <time datetime> when present) */
+	date: z.string().optional(),
+
+	/** Optional Go time layout for site-specific dates, e.g. "02/01/2006" */
+	dateLayout: z.string().optional(),
+
+	/** Selector for the summary or teaser text */
+	summary: z.string().optional(),
+
+	/** Selector for the lead image (defaults to the first img) */
+	image: z.string().optional(),
+
+	/** Selector for the byline */
+	author: z.string().optional(),
+});
+
+/** TypeScript type inferred from ScrapeRulesSchema */
+export type ScrapeRules = z.infer<typeof ScrapeRulesSchema>;
+
 /**
  * List of authentication types that a source may require.
  */
@@ -150,6 +186,9 @@ export const SourceSchema = z.object({
 	/** Optional API rate limit in requests per minute */
 	rateLimitPerMinute: z.number().optional(),
 
+	/** Optional interval between background fetches, in minutes */
+	refreshIntervalMinutes: z.number().optional(),
+
 	/** Optional custom headers for API requests (key-value map) */
 	headers: z.record(z.string(), z.string()).optional(),
 
@@ -168,9 +207,10 @@ export const SourceSchema = z.object({
 	 * Examples:
 	 * - "rss" → uses rssParser
 	 * - "json" → uses jsonParser
+	 * - "jsonfeed" → JSON Feed 1.0/1.1 (parsed on the Go side)
 	 * - "gdelt" → uses gdeltParser
 	 * - "hn" → Hacker News-specific parser
-	 * - "html" → raw HTML parsing (scraping)
+	 * - "html" → HTML scraping with the `scrape` selector rules (Go side)
 	 *
 	 * Defaults to `"json"` since most APIs return JSON.
 	 */
@@ -184,6 +224,7 @@ export const SourceSchema = z.object({
 	 *
 	 * Examples:
 	 * - "json" → normalizeJson
+	 * - "jsonfeed" → JSON Feed items (normalized on the Go side)
 	 * - "rss" → normalizeRss
 	 * - "gdelt" → normalizeGdelt
 	 * - "reddit" → normalizeReddit
@@ -210,6 +251,9 @@ export const SourceSchema = z.object({
    */
   ownership: OwnershipSchema.optional(),
 
+	/** CSS selector rules used by the "html" parser */
+	scrape: ScrapeRulesSchema.optional(),
+
 	/**
 	 * Confidence score for the source's bias classification
 	 * Range: 0 (uncertain) to 1 (fully confident)
@@ -29,7 +29,7 @@ import { ThemeProvider } from "@/context/ThemeContext";
 import { useNodeStatus } from "@/hooks/useNodeStatus";
 import { addDebugLog } from "@/lib/log";
 import type { Article, DebugStatus } from "@/types";
-import { createEmptyDebugStatus } from "@/types";
+import { createEmptyDebugStatus, parseBindingResponse } from "@/types";
 import type { FilterOptions } from "@/types/filter";
 import type { ViewMode } from "@/types/view";
 import { EventsOff, EventsOn } from "../wailsjs/runtime";
@@ -152,20 +152,21 @@ const App = () => {
 
 		const pollArticle = async () => {
 			try {
-				const res = await FetchLocalArticle(article.id);
-				const data = JSON.parse(res);
+				const res = parseBindingResponse<{ status: string; article?: Article }>(
+					await FetchLocalArticle(article.id),
+				);
 
-				if (data.status === "complete") {
-					const fetchedArticle: Article = JSON.parse(data.body);
-					setFullArticle(fetchedArticle); // update with processed content
+				if (!res.success) {
+					console.error(`Failed to fetch article (${res.code}):`, res.error);
 					setArticleLoading(false);
-					console.log("Article ready:", fetchedArticle);
 					return;
 				}
 
-				if (data.status === "error") {
-					console.error("Failed to fetch article:", data.errorMsg);
+				if (res.data?.status === "complete" && res.data.article) {
+					const fetchedArticle = res.data.article;
+					setFullArticle(fetchedArticle); // update with processed content
 					setArticleLoading(false);
+					console.log("Article ready:", fetchedArticle);
 					return;
 				}
 
@@ -1,11 +1,12 @@
 import { ExternalLink } from "lucide-react";
 import { useState } from "react";
 import { Button } from "@/components/ui/button";
-import type { SourceWithHidden } from "@/types";
+import { SourceParsers, type SourceWithHidden } from "@/types";
 // import { BrowserOpenURL } from "../../../../wailsjs/runtime/runtime";
 import { OpenURL } from "../../../../wailsjs/go/main/App";
 import SourceField from "./source-field";
 import SourceHeader from "./source-header";
+import SourceScrapeRules from "./source-scrape-rules";
 import SourceSelect from "./source-select";
 import SourceSwitch from "./source-switch";
 import SourceTextDisplay from "./source-text-display";
@@ -144,6 +145,20 @@ export const SourceItem: React.FC<Props> = ({
 								disabled={source.hidden}
 							/>
 						</div>
+						<SourceSelect
+							label="Parser"
+							value={source.parser || "json"}
+							options={[...SourceParsers]}
+							onChange={(v) => onUpdate(index, "parser", v)}
+							disabled={source.hidden}
+						/>
+						{source.parser === "html" && (
+							<SourceScrapeRules
+								source={source}
+								onChange={(rules) => onUpdate(index, "scrape", rules)}
+								disabled={source.hidden}
+							/>
+						)}
 
 						<SourceField
 							label="Rate Limit / Minute"
@@ -151,6 +166,12 @@ export const SourceItem: React.FC<Props> = ({
 							onChange={(v) => onUpdate(index, "rateLimitPerMinute", Number(v))}
 							disabled={source.hidden}
 						/>
+						<SourceField
+							label="Refresh Interval (minutes)"
+							value={source.refreshIntervalMinutes?.toString() || "15"}
+							onChange={(v) => onUpdate(index, "refreshIntervalMinutes", Number(v))}
+							disabled={source.hidden}
+						/>
 						<div className="grid grid-cols-2 gap-4">
 							<SourceSwitch
 								checked={source.pinned ?? false}
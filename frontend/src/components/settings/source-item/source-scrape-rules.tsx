@@ -0,0 +1,97 @@
+import { useState } from "react";
+import { Button } from "@/components/ui/button";
+import { Label } from "@/components/ui/label";
+import { previewScrape } from "@/lib/sources";
+import type { ScrapePreview, ScrapeRules, SourceWithHidden } from "@/types";
+import SourceField from "./source-field";
+
+interface Props {
+	source: SourceWithHidden;
+	onChange: (rules: ScrapeRules) => void;
+	disabled?: boolean;
+}
+
+const RULE_FIELDS: { key: keyof ScrapeRules; label: string; placeholder: string }[] = [
+	{ key: "item", label: "Item Selector", placeholder: "e.g., ul.stories > li" },
+	{ key: "title", label: "Title", placeholder: "e.g., h2 (defaults to the link text)" },
+	{ key: "link", label: "Link", placeholder: "e.g., a.headline@href (defaults to the first link)" },
+	{ key: "date", label: "Date", placeholder: "e.g., time (reads the datetime attribute)" },
+	{ key: "dateLayout", label: "Date Layout", placeholder: "Optional Go layout, e.g., 02/01/2006" },
+	{ key: "summary", label: "Summary", placeholder: "e.g., p.dek" },
+	{ key: "image", label: "Image", placeholder: "e.g., img@data-src (defaults to the first image)" },
+	{ key: "author", label: "Author", placeholder: "e.g., .byline" },
+];
+
+/**
+ * Editor for the CSS selector rules of an "html" source, with a dry-run
+ * preview of the first extracted items.
+ */
+export const SourceScrapeRules: React.FC<Props> = ({ source, onChange, disabled = false }) => {
+	const rules: ScrapeRules = source.scrape ?? { item: "", title: "" };
+	const [preview, setPreview] = useState<ScrapePreview | null>(null);
+	const [error, setError] = useState<string | null>(null);
+	const [loading, setLoading] = useState(false);
+
+	const handlePreview = async () => {
+		setLoading(true);
+		setError(null);
+		try {
+			setPreview(await previewScrape({ ...source, scrape: rules }));
+		} catch (err: any) {
+			setPreview(null);
+			setError(err?.message ?? String(err));
+		} finally {
+			setLoading(false);
+		}
+	};
+
+	return (
+		<div className="flex flex-col gap-3 border rounded-lg p-3">
+			<Label className="text-xs font-semibold">Scrape Rules</Label>
+			{RULE_FIELDS.map(({ key, label, placeholder }) => (
+				<SourceField
+					key={key}
+					label={label}
+					value={rules[key] ?? ""}
+					placeholder={placeholder}
+					onChange={(v) => onChange({ ...rules, [key]: v })}
+					disabled={disabled}
+				/>
+			))}
+
+			<div className="flex items-center gap-2">
+				<Button
+					variant="outline"
+					size="sm"
+					onClick={handlePreview}
+					disabled={disabled || loading || !rules.item || !source.endpoint}
+				>
+					{loading ? "Previewing..." : "Preview"}
+				</Button>
+				{preview && (
+					<span className="text-xs text-muted-foreground">
+						{preview.matched} matched, {preview.extracted} extracted
+					</span>
+				)}
+			</div>
+
+			{error && <p className="text-xs text-destructive">{error}</p>}
+
+			{preview && preview.items.length > 0 && (
+				<ul className="flex flex-col gap-2 text-xs">
+					{preview.items.map((item) => (
+						<li key={item.id} className="flex flex-col border-l-2 pl-2">
+							<span className="font-medium">{item.title}</span>
+							<span className="text-muted-foreground truncate">{item.url}</span>
+							{item.publishedAt && (
+								<span className="text-muted-foreground">{item.publishedAt}</span>
+							)}
+						</li>
+					))}
+				</ul>
+			)}
+		</div>
+	);
+};
+
+export default SourceScrapeRules;
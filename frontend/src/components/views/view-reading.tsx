@@ -6,7 +6,7 @@
  */
 
 import React, { useEffect, useState } from "react";
-import type { ArticleAnalyzed, FilterOptions } from "@/types";
+import { type ArticleAnalyzed, type FilterOptions, parseBindingResponse } from "@/types";
 import ArticlesGrid from "@/components/articles/articles-grid";
 import { LoadingOverlay } from "@/components/loading/loading-overlay";
 import FiltersPanel from "../filters-panel";
@@ -50,10 +50,10 @@ const ReadingView: React.FC<Props> = ({ onLoadingChange, filter, setFilter }) =>
         setProgress(40);
         onLoadingChange?.(true, "Fetching analyzed articles…", 40);
 
-        const res = await FetchAnalyzedArticles();
-        const parsed = JSON.parse(res);
+        const res = parseBindingResponse<ArticleAnalyzed[]>(await FetchAnalyzedArticles());
+        if (!res.success) throw new Error(`${res.code}: ${res.error}`);
 
-        setAnalyzed(parsed || []);
+        setAnalyzed(res.data || []);
         setProgress(100);
         onLoadingChange?.(false, "Loaded analyzed articles", 100);
       } catch (err) {
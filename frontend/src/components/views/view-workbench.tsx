@@ -48,7 +48,7 @@ const WorkbenchView: React.FC<Props> = ({
 				return;
 			}
 
-			const resStr = await TranslateArticle([article.id], "en", ["title"], true);
+			const resStr = await TranslateArticle([article.id], "en", ["title"], true, crypto.randomUUID());
 
 			const res: ApiResponse<Article[]> = JSON.parse(resStr);
 			console.log("res", res);
@@ -1,13 +1,14 @@
 // frontend/src/hooks/useNodeStatus.ts
 import { useEffect, useState } from "react";
-import { createEmptyNodeStatus, type NodeStatus } from "@/types";
+import { createEmptyNodeStatus, type NodeStatus, parseBindingResponse } from "@/types";
 import { AppStatus } from "../../wailsjs/go/main/App";
+import { EventsOff, EventsOn } from "../../wailsjs/runtime";
 
 /**
  * Custom React hook to poll the current status of the P2P node.
  *
- * This hook periodically calls the Go `AppStatus` function via Wails, parses
- * the JSON response, and returns a `NodeStatus` object representing the current
+ * This hook periodically calls the Go `AppStatus` function via Wails, unwraps
+ * the response envelope, and returns a `NodeStatus` object representing the current
  * state of the node, including:
  * - Node running status
  * - P2P connection status
@@ -17,7 +18,9 @@ import { AppStatus } from "../../wailsjs/go/main/App";
  * - Peer count
  * - HTTP port
  *
- * The hook updates its state at the interval specified by `pollInterval`.
+ * The hook updates its state at the interval specified by `pollInterval`, and
+ * reacts immediately to the Go supervisor events (`p2p:crashed`,
+ * `p2p:restarting`, `p2p:ready`) instead of waiting for the next poll.
  *
  * @param pollInterval - The interval in milliseconds to poll the node status (default: 3000ms)
  * @returns The latest node status
@@ -32,27 +35,32 @@ export function useNodeStatus(pollInterval = 3000) {
 	const [status, setStatus] = useState<NodeStatus>(createEmptyNodeStatus());
 
 	useEffect(() => {
-		const interval = setInterval(async () => {
+		const poll = async () => {
 			try {
-				const raw = await AppStatus();
-				const parsed: NodeStatus = raw
-					? (() => {
-							try {
-								const data = JSON.parse(raw);
-								return { ...data, port: data.port ?? 9001 };
-							} catch {
-								return createEmptyNodeStatus();
-							}
-						})()
-					: createEmptyNodeStatus();
+				const res = parseBindingResponse<NodeStatus>(await AppStatus());
+				const parsed: NodeStatus =
+					res.success && res.data
+						? { ...res.data, port: res.data.port ?? 9001 }
+						: createEmptyNodeStatus();
 
 				setStatus(parsed);
 			} catch {
 				setStatus(createEmptyNodeStatus());
 			}
-		}, pollInterval);
+		};
 
-		return () => clearInterval(interval);
+		const interval = setInterval(poll, pollInterval);
+
+		// The node process went down; don't wait for the next poll to show it
+		const onDown = () => setStatus((prev) => ({ ...prev, running: false, connected: false }));
+		EventsOn("p2p:crashed", onDown);
+		EventsOn("p2p:restarting", onDown);
+		EventsOn("p2p:ready", poll);
+
+		return () => {
+			clearInterval(interval);
+			EventsOff("p2p:crashed", "p2p:restarting", "p2p:ready");
+		};
 	}, [pollInterval]);
 
 	return status;
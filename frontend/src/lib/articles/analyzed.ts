@@ -1,4 +1,4 @@
-import { type ArticleAnalyzed, ArticleAnalyzedSchema } from "@/types";
+import { type ArticleAnalyzed, ArticleAnalyzedSchema, parseBindingResponse } from "@/types";
 import {
 	DeleteAnalyzedArticle,
 	FetchAnalyzedArticles,
@@ -12,18 +12,13 @@ import {
  */
 export const loadAnalyzedArticles = async (): Promise<ArticleAnalyzed[]> => {
 	try {
-		const result = await FetchAnalyzedArticles();
-		if (!result) return [];
-
-		let parsed: unknown;
-		try {
-			parsed = JSON.parse(result);
-		} catch {
-			console.warn("FetchAnalyzedArticles returned invalid JSON:", result);
+		const res = parseBindingResponse<unknown[]>(await FetchAnalyzedArticles());
+		if (!res.success) {
+			console.warn(`FetchAnalyzedArticles failed (${res.code}):`, res.error);
 			return [];
 		}
 
-		const validArticles = (Array.isArray(parsed) ? parsed : [])
+		const validArticles = (Array.isArray(res.data) ? res.data : [])
 			.map((a: unknown) => {
 				try {
 					const article = ArticleAnalyzedSchema.parse(a);
@@ -56,8 +51,9 @@ export const saveAnalyzedArticle = async (article: Partial<ArticleAnalyzed>): Pr
 			fetchedAt: article.fetchedAt ?? new Date().toISOString(),
 		});
 
-		await SaveAnalyzedArticle(validArticle);
-		return true;
+		const res = parseBindingResponse(await SaveAnalyzedArticle(validArticle));
+		if (!res.success) console.warn(`SaveAnalyzedArticle failed (${res.code}):`, res.error);
+		return res.success;
 	} catch (err) {
 		console.error("Failed to save analyzed article:", err);
 		return false;
@@ -72,8 +68,9 @@ export const saveAnalyzedArticle = async (article: Partial<ArticleAnalyzed>): Pr
  */
 export const deleteAnalyzedArticle = async (id: string): Promise<boolean> => {
 	try {
-		await DeleteAnalyzedArticle(id);
-		return true;
+		const res = parseBindingResponse(await DeleteAnalyzedArticle(id));
+		if (!res.success) console.warn(`DeleteAnalyzedArticle failed (${res.code}):`, res.error);
+		return res.success;
 	} catch (err) {
 		console.error("Failed to delete analyzed article:", err);
 		return false;
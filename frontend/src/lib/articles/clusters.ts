@@ -0,0 +1,72 @@
+// frontend/src/lib/articles/clusters.ts
+import {
+	type BlindspotReport,
+	BlindspotReportSchema,
+	parseBindingResponse,
+	type StoryCluster,
+	StoryClusterSchema,
+} from "@/types";
+import { GetBlindspotReport, GetStoryClusters } from "../../../wailsjs/go/main/App";
+
+/**
+ * Load story clusters built by the Go clustering service from local articles.
+ *
+ * @param since - RFC3339 time or a duration back from now ("48h"); empty for the last 24 hours
+ * @param edition - Edition to limit the articles to; empty or "all" for every edition
+ * @returns Validated clusters, most widely covered first
+ */
+export const loadStoryClusters = async (since = "", edition = ""): Promise<StoryCluster[]> => {
+	try {
+		const res = parseBindingResponse<unknown[]>(await GetStoryClusters(since, edition));
+		if (!res.success) {
+			console.warn(`GetStoryClusters failed (${res.code}):`, res.error);
+			return [];
+		}
+
+		return (Array.isArray(res.data) ? res.data : [])
+			.map((c: unknown) => {
+				const parsed = StoryClusterSchema.safeParse(c);
+				return parsed.success ? parsed.data : null;
+			})
+			.filter(Boolean) as StoryCluster[];
+	} catch (err) {
+		console.error("Failed to load story clusters:", err);
+		return [];
+	}
+};
+
+/** Options of the blindspot report; every field is optional. */
+export interface BlindspotQuery {
+	/** RFC3339 time or a duration back from now ("48h"); default the last 24 hours */
+	since?: string;
+	/** RFC3339 end of the period; default now */
+	until?: string;
+	/** Edition to limit the articles to; empty or "all" for every edition */
+	edition?: string;
+	/** Share (0-100) of sources below which a side misses a story; default 10 */
+	threshold?: number;
+	/** Sources of known bias a story needs; default 3 */
+	minSources?: number;
+	/** Maximum number of blindspots; 0 for all */
+	limit?: number;
+}
+
+/**
+ * Load the stories covered only by one side of the spectrum.
+ *
+ * @returns The validated report, or `null` if it could not be built
+ */
+export const loadBlindspotReport = async (query: BlindspotQuery = {}): Promise<BlindspotReport | null> => {
+	try {
+		const res = parseBindingResponse<unknown>(await GetBlindspotReport(query as any));
+		if (!res.success) {
+			console.warn(`GetBlindspotReport failed (${res.code}):`, res.error);
+			return null;
+		}
+		const parsed = BlindspotReportSchema.safeParse(res.data);
+		return parsed.success ? parsed.data : null;
+	} catch (err) {
+		console.error("Failed to load blindspot report:", err);
+		return null;
+	}
+};
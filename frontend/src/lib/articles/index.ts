@@ -1,5 +1,9 @@
 // frontend/src/lib/articles/index.ts
 export * from "./aggregator";
+export * from "./clusters";
 export * from "./federated";
 export * from "./local";
+export * from "./pages";
+export * from "./query";
+export * from "./search";
 export * from "./sources";
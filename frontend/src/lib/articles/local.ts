@@ -1,5 +1,5 @@
 // frontend/src/lib/articles/local.ts
-import { type Article, ArticleSchema } from "@/types";
+import { type Article, ArticleSchema, parseBindingResponse } from "@/types";
 import {
 	DeleteLocalArticle,
 	FetchLocalArticles,
@@ -13,18 +13,13 @@ import {
  */
 export const loadLocalArticles = async (): Promise<Article[]> => {
 	try {
-		const result = await FetchLocalArticles();
-		if (!result) return [];
-
-		let parsed: unknown;
-		try {
-			parsed = JSON.parse(result);
-		} catch {
-			console.warn("FetchLocalArticles returned invalid JSON:", result);
+		const res = parseBindingResponse<unknown[]>(await FetchLocalArticles());
+		if (!res.success) {
+			console.warn(`FetchLocalArticles failed (${res.code}):`, res.error);
 			return [];
 		}
 
-		const validArticles = (Array.isArray(parsed) ? parsed : [])
+		const validArticles = (Array.isArray(res.data) ? res.data : [])
 			.map((a: unknown) => {
 				try {
 					const article = ArticleSchema.parse(a);
@@ -58,8 +53,9 @@ export const saveLocalArticle = async (article: Partial<Article>): Promise<boole
 			fetchedAt: article.fetchedAt ?? new Date().toISOString(),
 		});
 
-		await SaveLocalArticle(validArticle);
-		return true;
+		const res = parseBindingResponse(await SaveLocalArticle(validArticle, false));
+		if (!res.success) console.warn(`SaveLocalArticle failed (${res.code}):`, res.error);
+		return res.success;
 	} catch (err) {
 		console.error("Failed to save local article:", err);
 		return false;
@@ -74,8 +70,9 @@ export const saveLocalArticle = async (article: Partial<Article>): Promise<boole
  */
 export const deleteLocalArticle = async (id: string): Promise<boolean> => {
 	try {
-		await DeleteLocalArticle(id);
-		return true;
+		const res = parseBindingResponse(await DeleteLocalArticle(id));
+		if (!res.success) console.warn(`DeleteLocalArticle failed (${res.code}):`, res.error);
+		return res.success;
 	} catch (err) {
 		console.error("Failed to delete local article:", err);
 		return false;
@@ -0,0 +1,96 @@
+// frontend/src/lib/articles/pages.ts
+import type { z } from "zod";
+import {
+	type Article,
+	type ArticleAnalyzed,
+	ArticleAnalyzedSchema,
+	type ArticlePageRequest,
+	ArticleSchema,
+	ArticlesPageEventSchema,
+	ArticlesPageSchema,
+	articleStores,
+	type FederatedArticlePointer,
+	FederatedArticlePointerSchema,
+	parseBindingResponse,
+} from "@/types";
+import {
+	FetchAnalyzedArticlesPage,
+	FetchFederatedArticlesPage,
+	FetchLocalArticlesPage,
+	StreamArticles,
+} from "../../../wailsjs/go/main/App";
+import { EventsOn } from "../../../wailsjs/runtime";
+
+export type ArticleStore = (typeof articleStores)[number];
+
+/** A page of a store listing with its items parsed. */
+export interface ArticlesPage<T> {
+	items: T[];
+	total: number;
+	nextCursor?: string;
+}
+
+const itemSchemas = {
+	local: ArticleSchema,
+	analyzed: ArticleAnalyzedSchema,
+	federated: FederatedArticlePointerSchema,
+} satisfies Record<ArticleStore, z.ZodTypeAny>;
+
+const parsePage = async <T>(call: Promise<string>, schema: z.ZodType<T>): Promise<ArticlesPage<T>> => {
+	const res = parseBindingResponse<unknown>(await call);
+	if (!res.success) throw new Error(res.error ?? res.code ?? "Failed to fetch articles");
+	const page = ArticlesPageSchema.parse(res.data);
+	return { ...page, items: page.items.map((item) => schema.parse(item)) };
+};
+
+/** Fetch one page of the local articles; cancel with CancelRequest(requestId). */
+export const fetchLocalArticlesPage = (
+	page: ArticlePageRequest = {},
+	requestId: string = crypto.randomUUID(),
+): Promise<ArticlesPage<Article>> => parsePage(FetchLocalArticlesPage(page as any, requestId), ArticleSchema);
+
+/** Fetch one page of the analyzed articles; cancel with CancelRequest(requestId). */
+export const fetchAnalyzedArticlesPage = (
+	page: ArticlePageRequest = {},
+	requestId: string = crypto.randomUUID(),
+): Promise<ArticlesPage<ArticleAnalyzed>> =>
+	parsePage(FetchAnalyzedArticlesPage(page as any, requestId), ArticleAnalyzedSchema);
+
+/** Fetch one page of the federated article pointers; cancel with CancelRequest(requestId). */
+export const fetchFederatedArticlesPage = (
+	page: ArticlePageRequest = {},
+	requestId: string = crypto.randomUUID(),
+): Promise<ArticlesPage<FederatedArticlePointer>> =>
+	parsePage(FetchFederatedArticlesPage(page as any, requestId), FederatedArticlePointerSchema);
+
+/**
+ * Stream a whole store page by page. `onPage` runs for every page as it
+ * arrives; cancel the stream with CancelRequest(requestId).
+ *
+ * @returns The number of pages and items delivered
+ * @throws If the store could not be read or the stream was cancelled
+ */
+export const streamArticles = async <S extends ArticleStore>(
+	store: S,
+	onPage: (items: z.infer<(typeof itemSchemas)[S]>[], total: number, done: boolean) => void,
+	page: ArticlePageRequest = {},
+	requestId: string = crypto.randomUUID(),
+): Promise<{ pages: number; items: number }> => {
+	const schema = itemSchemas[store];
+	// Other streams may be running; only this call's pages are ours
+	const off = EventsOn("articles:page", (data: unknown) => {
+		const event = ArticlesPageEventSchema.parse(data);
+		if (event.requestId !== requestId) return;
+		onPage(event.items.map((item) => schema.parse(item)), event.total, event.done);
+	});
+
+	try {
+		const res = parseBindingResponse<{ pages: number; items: number }>(
+			await StreamArticles(store, page as any, requestId),
+		);
+		if (!res.success || !res.data) throw new Error(res.error ?? res.code ?? "Failed to stream articles");
+		return res.data;
+	} finally {
+		off();
+	}
+};
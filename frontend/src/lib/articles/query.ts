@@ -0,0 +1,31 @@
+// frontend/src/lib/articles/query.ts
+import {
+	type ArticleQueryFilter,
+	type ArticleQueryPage,
+	ArticleQueryPageSchema,
+	type ArticleSort,
+	parseBindingResponse,
+} from "@/types";
+import { QueryArticles } from "../../../wailsjs/go/main/App";
+
+/**
+ * Filter, sort and page the local, analyzed and federated articles in Go.
+ *
+ * @param filter - Filter panel options and stores to include
+ * @param sort - Sort order; defaults to newest first
+ * @param cursor - nextCursor of the previous page; omit for the first page
+ * @param limit - Articles per page (Go default 50, maximum 500)
+ * @returns The page with its facet counts
+ * @throws If the cursor is stale for this sort or the stores could not be read
+ */
+export const queryArticles = async (
+	filter: ArticleQueryFilter,
+	sort: ArticleSort = "newest",
+	cursor = "",
+	limit = 50,
+	requestId: string = crypto.randomUUID(),
+): Promise<ArticleQueryPage> => {
+	const res = parseBindingResponse<unknown>(await QueryArticles(filter as any, sort, cursor, limit, requestId));
+	if (!res.success) throw new Error(res.error ?? res.code ?? "Query failed");
+	return ArticleQueryPageSchema.parse(res.data);
+};
@@ -0,0 +1,24 @@
+// frontend/src/lib/articles/search.ts
+import {
+	type ArticleSearchQuery,
+	parseBindingResponse,
+	type SearchResults,
+	SearchResultsSchema,
+} from "@/types";
+import { SearchArticles } from "../../../wailsjs/go/main/App";
+
+/**
+ * Search local articles with the Go full-text index.
+ *
+ * @param query - Search text and filters; see ArticleSearchQuerySchema for the syntax
+ * @returns A page of ranked hits
+ * @throws If the query is invalid (e.g. a malformed date) or the search failed
+ */
+export const searchArticles = async (
+	query: ArticleSearchQuery,
+	requestId: string = crypto.randomUUID(),
+): Promise<SearchResults> => {
+	const res = parseBindingResponse<unknown>(await SearchArticles(query as any, requestId));
+	if (!res.success) throw new Error(res.error ?? res.code ?? "Search failed");
+	return SearchResultsSchema.parse(res.data);
+};
@@ -1,6 +1,6 @@
 import { z } from "zod";
 import { type DebugLogEntry, DebugLogEntrySchema } from "@/types";
-import { parseApiResponse } from "@/types/api";
+import { parseBindingResponse } from "@/types/api";
 import { AddDebugLog, FetchDebugLogs } from "../../wailsjs/go/main/App";
 
 /**
@@ -38,7 +38,8 @@ export async function addDebugLog(entry: {
 
 	try {
 		if (AddDebugLog) {
-			await AddDebugLog(logEntry);
+			const res = parseBindingResponse(await AddDebugLog(logEntry));
+			if (!res.success) console.warn(`AddDebugLog failed (${res.code}):`, res.error);
 		} else {
 			console.warn("Wails AddDebugLog not available, skipping backend save");
 		}
@@ -52,17 +53,15 @@ export async function addDebugLog(entry: {
  */
 export async function getDebugLogs(): Promise<DebugLogEntry[]> {
 	try {
-		const raw = await FetchDebugLogs(); // returns JSON string from backend
-
-		// Parse response using generic helper, specifying an array of DebugLogEntry
-		const response = parseApiResponse(JSON.parse(raw), z.array(DebugLogEntrySchema));
+		const response = parseBindingResponse(await FetchDebugLogs()); // JSON envelope string
 
 		if (!response.success) {
-			console.warn("Debug logs fetch error:", response.error);
+			console.warn(`Debug logs fetch error (${response.code}):`, response.error);
 			return [];
 		}
 
-		return response.data ?? [];
+		// Validate the payload as an array of DebugLogEntry
+		return z.array(DebugLogEntrySchema).parse(response.data ?? []);
 	} catch (err) {
 		console.error("Failed to fetch debug logs:", err);
 		return [];
@@ -3,12 +3,31 @@ import { DEFAULT_SOURCES } from "@/constants/sources";
 import {
 	type Article,
 	type AuthType,
+	AuthTypes,
+	type FetchResult,
+	type OPMLExport,
+	type OPMLImport,
+	type ScrapePreview,
+	type SecretsStatus,
 	type Source,
 	type SourceCategory,
+	type SourceIssue,
 	SourcesSchema,
 	type SourceWithHidden,
+	parseBindingResponse,
 } from "@/types";
-import { FetchArticlesBySources, LoadSources, SaveSources } from "../../wailsjs/go/main/App";
+import {
+	ExportOPML,
+	FetchArticlesBySources,
+	ImportOPML,
+	LoadSources,
+	PreviewScrape,
+	SaveSources,
+	SecretsStatus as GetSecretsStatus,
+	SetSecretsPassphrase,
+	UnlockSecrets,
+	ValidateSources,
+} from "../../wailsjs/go/main/App";
 
 /**
  * Normalize raw source data into a valid Source object.
@@ -21,6 +40,7 @@ export function createSource(
 		name: raw.name ?? "Unnamed Source",
 		endpoint: raw.endpoint || raw.url || "",
 		apiKey: raw.apiKey ?? undefined,
+		apiKeyRef: raw.apiKeyRef ?? undefined,
 		instructions: raw.instructions ?? undefined,
 		apiLink: raw.apiLink ?? undefined,
 		enabled: raw.enabled ?? !!raw.apiKey,
@@ -29,13 +49,16 @@ export function createSource(
 		tags: raw.tags ?? [],
 		language: raw.language ?? undefined,
 		region: raw.region ?? undefined,
-		authType: raw.authType as AuthType | undefined,
+		// Auth types this version no longer supports (digestAuth) are dropped
+		authType: AuthTypes.includes(raw.authType as AuthType) ? raw.authType : undefined,
 		rateLimitPerMinute: raw.rateLimitPerMinute ?? undefined,
+		refreshIntervalMinutes: raw.refreshIntervalMinutes ?? undefined,
 		headers: raw.headers ?? undefined,
 		lastUpdated: raw.lastUpdated ? new Date(raw.lastUpdated) : undefined,
 		pinned: raw.pinned ?? undefined,
-		parser: "json",
-		normalizer: "json",
+		parser: raw.parser ?? "json",
+		normalizer: raw.normalizer ?? "json",
+		scrape: raw.scrape ?? undefined,
 	} as Source;
 }
 
@@ -45,8 +68,9 @@ export function createSource(
  */
 export async function loadSources(): Promise<Source[]> {
 	try {
-		const loaded: any[] = await LoadSources();
-		const normalized = (loaded ?? []).map(createSource);
+		const res = parseBindingResponse<any[]>(await LoadSources());
+		if (!res.success) throw new Error(`${res.code}: ${res.error}`);
+		const normalized = (res.data ?? []).map(createSource);
 		return SourcesSchema.parse(normalized);
 	} catch (err) {
 		console.error("Failed to load sources:", err);
@@ -56,7 +80,8 @@ export async function loadSources(): Promise<Source[]> {
 
 /**
  * Save sources to the Wails backend.
- * Only necessary fields are sent.
+ * Only necessary fields are sent. API keys are moved into the Go secret
+ * store; an unchanged SECRET_MASK keeps the stored key.
  */
 export async function saveSources(sources: Source[]): Promise<void> {
 	try {
@@ -64,6 +89,7 @@ export async function saveSources(sources: Source[]): Promise<void> {
 			name: s.name,
 			endpoint: s.endpoint,
 			apiKey: s.apiKey,
+			apiKeyRef: s.apiKeyRef,
 			instructions: s.instructions,
 			apiLink: s.apiLink,
 			enabled: s.enabled,
@@ -74,11 +100,17 @@ export async function saveSources(sources: Source[]): Promise<void> {
 			region: s.region,
 			authType: s.authType,
 			rateLimitPerMinute: s.rateLimitPerMinute,
+			refreshIntervalMinutes: s.refreshIntervalMinutes,
 			headers: s.headers,
 			lastUpdated: s.lastUpdated?.toISOString(),
 			pinned: s.pinned,
+			parser: s.parser,
+			normalizer: s.normalizer,
+			scrape: s.scrape,
 		}));
-		await SaveSources(payload as any);
+		const res = parseBindingResponse<unknown>(await SaveSources(payload as any));
+		if (res.code === "validation_failed") console.warn("Invalid sources:", res.data as SourceIssue[]);
+		if (!res.success) throw new Error(`${res.code}: ${res.error}`);
 	} catch (err) {
 		console.error("Failed to save sources:", err);
 	}
@@ -156,24 +188,41 @@ export const getAvailableSources = async (): Promise<Source[]> => {
 
 /**
  * Fetch articles from the backend using the provided sources.
- * Passes full source objects to Wails → Go → Node.
+ * Go fetches and parses each source and adds new articles to the local store.
  * ONE-SHOT function — no internal polling.
+ * Pass a requestId to be able to abort the fetch with CancelRequest.
  */
-export const fetchArticlesBySources = async (sources: Source[]): Promise<Article[]> => {
+export const fetchArticlesBySources = async (
+	sources: Source[],
+	requestId: string = crypto.randomUUID(),
+): Promise<Article[]> => {
 	try {
 		if (!Array.isArray(sources)) {
 			console.warn("fetchArticlesBySources: invalid sources array");
 			return [];
 		}
 
-		// Send actual sources to backend
-		const raw = await FetchArticlesBySources(sources as any);
+		// articles maps source name → JSON array of its articles; meta holds per-source errors
+		const res = parseBindingResponse<FetchResult>(
+			await FetchArticlesBySources(sources as any, requestId),
+		);
+		if (!res.success || !res.data) {
+			console.warn(`FetchArticlesBySources failed (${res.code}):`, res.error);
+			return [];
+		}
 
-		const parsed: Article[] = JSON.parse(raw);
+		for (const [name, meta] of Object.entries(res.data.meta ?? {})) {
+			if (meta.error) console.warn(`Source ${name} failed: ${meta.error}`);
+		}
 
-		if (!Array.isArray(parsed)) {
-			console.warn("Invalid backend response — expected Article[]");
-			return [];
+		const parsed: Article[] = [];
+		for (const [name, raw] of Object.entries(res.data.articles ?? {})) {
+			try {
+				const items = JSON.parse(raw);
+				if (Array.isArray(items)) parsed.push(...items);
+			} catch {
+				console.warn(`Source ${name} did not return JSON articles`);
+			}
 		}
 
 		return parsed;
@@ -182,3 +231,94 @@ export const fetchArticlesBySources = async (sources: Source[]): Promise<Article
 		return [];
 	}
 };
+
+/**
+ * Dry-run an "html" source's scrape rules against its live page.
+ * Nothing is stored; returns the first `limit` extracted items plus match counts.
+ */
+export const previewScrape = async (
+	source: Source,
+	limit = 5,
+	requestId: string = crypto.randomUUID(),
+): Promise<ScrapePreview> => {
+	const res = parseBindingResponse<ScrapePreview>(
+		await PreviewScrape(source as any, limit, requestId),
+	);
+	if (!res.success) throw new Error(res.error ?? res.code ?? "Preview failed");
+	return res.data;
+};
+
+/**
+ * Add the feeds of an OPML file to the saved sources, skipping feeds whose
+ * endpoint or name is already present.
+ *
+ * @param path - Path of the OPML file
+ * @returns What was added and skipped, and the sources after the import
+ * @throws If the file cannot be read or is not OPML
+ */
+export const importOPML = async (path: string): Promise<OPMLImport> => {
+	const res = parseBindingResponse<OPMLImport>(await ImportOPML(path));
+	if (!res.success || !res.data) throw new Error(res.error ?? res.code ?? "Import failed");
+	return { ...res.data, sources: parseSources(res.data.sources) };
+};
+
+/**
+ * Write the saved sources to an OPML file. API keys are not exported.
+ *
+ * @param path - Path of the OPML file to write
+ */
+export const exportOPML = async (path: string): Promise<OPMLExport> => {
+	const res = parseBindingResponse<OPMLExport>(await ExportOPML(path));
+	if (!res.success || !res.data) throw new Error(res.error ?? res.code ?? "Export failed");
+	return res.data;
+};
+
+/**
+ * Check sources against the Go validation rules (endpoint URL, known parser
+ * and normalizer, bias, confidence 0-1) without saving them.
+ *
+ * @returns The issues found; empty when every source is valid
+ */
+export const validateSources = async (sources: Source[]): Promise<SourceIssue[]> => {
+	const res = parseBindingResponse<SourceIssue[]>(await ValidateSources(sources as any));
+	if (!res.success) throw new Error(res.error ?? res.code ?? "Validation failed");
+	return res.data ?? [];
+};
+
+/**
+ * How stored API keys are protected, and whether the secret store waits for
+ * its passphrase.
+ */
+export const getSecretsStatus = async (): Promise<SecretsStatus> => {
+	const res = parseBindingResponse<SecretsStatus>(await GetSecretsStatus());
+	if (!res.success || !res.data) throw new Error(res.error ?? res.code ?? "Status failed");
+	return res.data;
+};
+
+/**
+ * Unlock a passphrase-protected secret store for this session, so sources
+ * with stored API keys can be fetched and saved again.
+ *
+ * @throws On a wrong passphrase
+ */
+export const unlockSecrets = async (passphrase: string): Promise<SecretsStatus> => {
+	const res = parseBindingResponse<SecretsStatus>(await UnlockSecrets(passphrase));
+	if (!res.success || !res.data) throw new Error(res.error ?? res.code ?? "Unlock failed");
+	return res.data;
+};
+
+/**
+ * Protect stored API keys with a passphrase (at least 8 characters), or
+ * with a key file in the data directory when `passphrase` is empty.
+ *
+ * @param current - The passphrase in use; ignored while a key file is used
+ * @throws On a wrong current passphrase or a too short new one
+ */
+export const setSecretsPassphrase = async (
+	current: string,
+	passphrase: string,
+): Promise<SecretsStatus> => {
+	const res = parseBindingResponse<SecretsStatus>(await SetSecretsPassphrase(current, passphrase));
+	if (!res.success || !res.data) throw new Error(res.error ?? res.code ?? "Changing passphrase failed");
+	return res.data;
+};
@@ -1,5 +1,5 @@
 // frontend/src/lib/status.ts
-import type { NodeStatus } from "@/types";
+import { type NodeStatus, parseBindingResponse } from "@/types";
 // These come from wailsjs/go/main/App
 import { AppDeleteStatus, AppStatus, AppUpdateStatus } from "../../wailsjs/go/main/App";
 import { log } from "./log";
@@ -21,8 +21,9 @@ let status: NodeStatus = {
  */
 export async function loadLatestStatus(): Promise<Partial<NodeStatus> | null> {
 	try {
-		const raw = await AppStatus(); // returns JSON string
-		const data = JSON.parse(raw) as NodeStatus;
+		const res = parseBindingResponse<NodeStatus>(await AppStatus()); // JSON envelope string
+		if (!res.success || !res.data) throw new Error(`${res.code}: ${res.error}`);
+		const data = res.data;
 
 		status = data; // update local singleton
 		return data;
@@ -37,8 +38,9 @@ export async function loadLatestStatus(): Promise<Partial<NodeStatus> | null> {
  */
 export async function updateStatus(newStatus: Partial<NodeStatus>): Promise<NodeStatus> {
 	try {
-		const raw = await AppUpdateStatus(JSON.stringify(newStatus));
-		const updated = JSON.parse(raw) as NodeStatus;
+		const res = parseBindingResponse<NodeStatus>(await AppUpdateStatus(JSON.stringify(newStatus)));
+		if (!res.success || !res.data) throw new Error(`${res.code}: ${res.error}`);
+		const updated = res.data;
 
 		status = updated;
 		return updated;
@@ -89,10 +91,9 @@ export function clearStatus() {
  */
 export async function deleteStatus() {
 	try {
-		const raw = await AppDeleteStatus(); // Go backend DELETE /status
-		const resp = JSON.parse(raw);
+		const resp = parseBindingResponse(await AppDeleteStatus()); // Go backend DELETE /status
 
-		if (resp.error) {
+		if (!resp.success) {
 			log(`❌ Failed to delete status via Go backend: ${resp.error}`);
 		} else {
 			log("✅ Status file deleted.");
@@ -1,23 +1,55 @@
 // frontend/src/types/api.ts
 import { z } from "zod";
 
+/**
+ * Machine-readable error codes returned by the Go bindings.
+ *
+ * - `node_unreachable`: the P2P node is not running or not answering
+ * - `not_found`: the requested item does not exist
+ * - `bad_request`: invalid arguments were passed to the binding
+ * - `validation_failed`: the input failed validation; `data` lists the issues
+ * - `secrets_locked`: the secret store waits for its passphrase (see unlockSecrets)
+ * - `upstream_timeout`: the node or a remote source did not answer in time
+ * - `rate_limited`: the node asked us to slow down
+ * - `upstream_error`: the node answered with an error or a malformed body
+ * - `internal`: failure inside the Go app itself
+ */
+export const ApiErrorCodes = [
+	"node_unreachable",
+	"not_found",
+	"bad_request",
+	"validation_failed",
+	"secrets_locked",
+	"upstream_timeout",
+	"rate_limited",
+	"canceled",
+	"upstream_error",
+	"internal",
+] as const;
+
+export type ApiErrorCode = (typeof ApiErrorCodes)[number];
+
 /**
  * Generic API response wrapper returned by the Wails → Node backend.
  *
  * Structure:
  * - `success`: boolean indicating whether the backend call succeeded
+ * - `code`: machine-readable error code when `success` is false
  * - `error`: optional error message when `success` is false
  * - `data`: the actual response payload (type varies by endpoint)
+ * - `requestId`: identifier of the call, matching the Go logs
  *
- * This wrapper ensures every API call returns valid JSON and is easy
+ * Every data binding returns this envelope as a JSON string, so it is easy
  * to work with on the frontend.
  *
  * @template T - The expected data type of the response payload
  */
 export interface ApiResponse<T> {
 	success: boolean;
+	code?: ApiErrorCode;
 	error?: string;
 	data: T;
+	requestId?: string;
 }
 
 /**
@@ -29,8 +61,10 @@ export interface ApiResponse<T> {
  */
 export const ApiResponseBaseSchema = z.object({
 	success: z.boolean(),
+	code: z.enum(ApiErrorCodes).optional(),
 	error: z.string().optional(),
 	data: z.any(),
+	requestId: z.string().optional(),
 });
 
 /**
@@ -49,8 +83,10 @@ export const ApiResponseBaseSchema = z.object({
 export function createApiResponseSchema<T extends z.ZodTypeAny>(dataSchema: T) {
 	return z.object({
 		success: z.boolean(),
+		code: z.enum(ApiErrorCodes).optional(),
 		error: z.string().optional(),
 		data: dataSchema,
+		requestId: z.string().optional(),
 	});
 }
 
@@ -74,3 +110,31 @@ export function parseApiResponse<T>(json: unknown, dataSchema: z.ZodType<T>): Ap
 	const schema = createApiResponseSchema(dataSchema);
 	return schema.parse(json);
 }
+
+/**
+ * Parse the JSON envelope string returned by a Go binding.
+ *
+ * Never throws: invalid JSON is reported as an `internal` failure so callers
+ * only have to check `success`.
+ *
+ * Example:
+ * ```ts
+ * const res = parseBindingResponse<Article[]>(await FetchLocalArticles());
+ * if (!res.success) console.warn(res.code, res.error);
+ * ```
+ *
+ * @param raw - JSON string returned by the binding
+ * @returns The decoded envelope (data is not validated)
+ */
+export function parseBindingResponse<T>(raw: string): ApiResponse<T | undefined> {
+	try {
+		return ApiResponseBaseSchema.parse(JSON.parse(raw)) as ApiResponse<T | undefined>;
+	} catch (err) {
+		return {
+			success: false,
+			code: "internal",
+			error: `Invalid response from backend: ${(err as Error).message}`,
+			data: undefined,
+		};
+	}
+}
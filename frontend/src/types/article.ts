@@ -201,6 +201,26 @@ export const ArticleSchema = z.object({
 	/** Metadata about the source, including political bias and confidence */
 	sourceMeta: SourceMetaSchema.optional().nullable(),
 
+	/**
+	 * Every source that carried this article, recorded when duplicates from
+	 * several sources are merged (URL canonicalization / dedup on the Go side).
+	 */
+	sources: z
+		.array(
+			z.object({
+				name: z.string(),
+				bias: z.string().optional(),
+				url: z.string(),
+				id: z.string().optional(),
+				publishedAt: z.string().optional().nullable(),
+			}),
+		)
+		.optional()
+		.nullable(),
+
+	/** ID of the article this one is a near copy of (SimHash near-duplicate detection) */
+	duplicateOf: z.string().optional().nullable(),
+
 	/** ISO timestamp when the article was fetched into the system */
 	fetchedAt: z.string().optional().nullable(),
 });
@@ -9,6 +9,8 @@ export * from "./log";
 export * from "./normalizer";
 export * from "./p2p";
 export * from "./parser";
+export * from "./query";
+export * from "./search";
 export * from "./source";
 export * from "./source-cluster";
 export * from "./source-meta";
@@ -10,7 +10,7 @@ import type { Article, Source } from "@/types";
 export type NormalizerFn = (entry: any, source: Source) => Article;
 
 /** Valid normalizer types */
-export const SourceNormalizers = ["json", "rss", "gdelt", "hn", "reddit"] as const;
+export const SourceNormalizers = ["json", "jsonfeed", "rss", "gdelt", "hn", "reddit"] as const;
 
 /** Zod schema for normalizer names */
 export const SourceNormalizerSchema = z.enum(SourceNormalizers);
@@ -54,6 +54,22 @@ export const NodeStatusSchema = z.object({
 
 export type NodeStatus = z.infer<typeof NodeStatusSchema>;
 
+/* -------------------------------------------------------------
+ * READY Handshake Info
+ * ------------------------------------------------------------- */
+
+/**
+ * Zod schema for P2PNodeInfo, what the node advertised in its READY line
+ * (GetP2PNodeInfo data; null until the node is ready)
+ */
+export const P2PNodeInfoSchema = z.object({
+	httpPort: z.number(),
+	peerId: z.string(), // Empty when the node was detected via /status
+	version: z.string(), // Empty when the node was detected via /status
+});
+
+export type P2PNodeInfo = z.infer<typeof P2PNodeInfoSchema>;
+
 /* -------------------------------------------------------------
  * Debug Status (frontend-only extension)
  * ------------------------------------------------------------- */
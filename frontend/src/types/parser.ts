@@ -2,7 +2,7 @@ import { z } from "zod";
 import { type Article, ArticleSchema, type Source } from "@/types";
 
 /** Valid parser types */
-export const SourceParsers = ["json", "rss", "gdelt", "html", "hn", "reddit"] as const;
+export const SourceParsers = ["json", "jsonfeed", "rss", "gdelt", "html", "hn", "reddit"] as const;
 export const SourceParserSchema = z.enum(SourceParsers);
 export type SourceParser = z.infer<typeof SourceParserSchema>;
 
@@ -0,0 +1,92 @@
+// frontend/src/types/query.ts
+import { z } from "zod";
+import { ArticleAnalyzedSchema } from "./article-analyzed";
+import { FilterOptionsSchema } from "./filter";
+
+export const articleStores = ["local", "analyzed", "federated"] as const;
+export const articleSortOptions = ["newest", "oldest", "title", "confidence", "coverage"] as const;
+
+/**
+ * Filter of the QueryArticles binding: the filter panel's options plus the
+ * stores to search. Empty stores means all three; "all" and the
+ * "international" edition do not filter.
+ */
+export const ArticleQueryFilterSchema = FilterOptionsSchema.partial().extend({
+	stores: z.array(z.enum(articleStores)).optional(),
+});
+
+export type ArticleQueryFilter = z.infer<typeof ArticleQueryFilterSchema>;
+export type ArticleSort = (typeof articleSortOptions)[number];
+
+/**
+ * An article of a query page and the store it came from. Federated items only
+ * carry their pointer's data: id (the CID), ipfsHash, publishedAt, source,
+ * edition and analyzed.
+ */
+export const ArticleQueryItemSchema = ArticleAnalyzedSchema.partial().extend({
+	store: z.enum(articleStores),
+	id: z.string(),
+});
+
+export type ArticleQueryItem = z.infer<typeof ArticleQueryItemSchema>;
+
+/** Number of matching articles with one value of a facet. */
+export const FacetCountSchema = z.object({
+	value: z.string(),
+	count: z.number().int().nonnegative(),
+});
+
+export type FacetCount = z.infer<typeof FacetCountSchema>;
+
+/** One page of QueryArticles. */
+export const ArticleQueryPageSchema = z.object({
+	items: z.array(ArticleQueryItemSchema),
+	/** Matching articles across all pages */
+	total: z.number().int().nonnegative(),
+	/** Cursor of the next page; absent on the last page */
+	nextCursor: z.string().optional(),
+	/**
+	 * Counts per dimension (store, bias, edition, sentiment, coverage,
+	 * confidence, tags, source), each computed with every other filter applied.
+	 */
+	facets: z.record(z.string(), z.array(FacetCountSchema)),
+});
+
+export type ArticleQueryPage = z.infer<typeof ArticleQueryPageSchema>;
+
+export const articleListSortOptions = ["publishedAt", "fetchedAt", "analysisTimestamp"] as const;
+
+/**
+ * One page of a store listing (Fetch*ArticlesPage, StreamArticles). Sorted
+ * newest first by publishedAt by default; analysisTimestamp only applies to
+ * analyzed articles.
+ */
+export const ArticlePageRequestSchema = z.object({
+	limit: z.number().int().positive().optional(),
+	/** nextCursor of the previous page; omit for the first page */
+	cursor: z.string().optional(),
+	sort: z.enum(articleListSortOptions).optional(),
+	ascending: z.boolean().optional(),
+});
+
+export type ArticlePageRequest = z.infer<typeof ArticlePageRequestSchema>;
+
+/** A page of a store listing; `items` is parsed per store. */
+export const ArticlesPageSchema = z.object({
+	items: z.array(z.unknown()),
+	/** Size of the whole store when the page was cut; may change between pages */
+	total: z.number().int().nonnegative(),
+	/** Cursor of the next page; absent on the last page */
+	nextCursor: z.string().optional(),
+});
+
+/** Payload of the "articles:page" event sent by StreamArticles. */
+export const ArticlesPageEventSchema = ArticlesPageSchema.extend({
+	requestId: z.string(),
+	store: z.enum(articleStores),
+	/** 1-based */
+	page: z.number().int().positive(),
+	done: z.boolean(),
+});
+
+export type ArticlesPageEvent = z.infer<typeof ArticlesPageEventSchema>;
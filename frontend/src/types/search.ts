@@ -0,0 +1,50 @@
+// frontend/src/types/search.ts
+import { z } from "zod";
+
+/**
+ * A full-text search over local articles (SearchArticles binding).
+ * `text` accepts words, "exact phrases", prefixes (infla*), exclusions (-word),
+ * field terms (title:, summary:, content:, tag:, author:) and inline filters
+ * (source:, bias:, edition:, lang:, after:, before:).
+ */
+export const ArticleSearchQuerySchema = z.object({
+	text: z.string(),
+	source: z.string().optional(),
+	bias: z.string().optional(),
+	edition: z.string().optional(),
+	language: z.string().optional(),
+	/** RFC3339 time or YYYY-MM-DD, inclusive */
+	since: z.string().optional(),
+	/** RFC3339 time or YYYY-MM-DD, inclusive */
+	until: z.string().optional(),
+	limit: z.number().int().positive().optional(),
+	offset: z.number().int().nonnegative().optional(),
+});
+
+export type ArticleSearchQuery = z.infer<typeof ArticleSearchQuerySchema>;
+
+/** One matching article; load the full article with FetchLocalArticle. */
+export const SearchHitSchema = z.object({
+	id: z.string(),
+	url: z.string(),
+	title: z.string(),
+	source: z.string().optional(),
+	bias: z.string().optional(),
+	publishedAt: z.string().optional(),
+	/** BM25 relevance; 0 for filter-only queries */
+	score: z.number(),
+	/** Fields the query matched in: title, summary, content, tags, author */
+	fields: z.array(z.string()).optional(),
+});
+
+export type SearchHit = z.infer<typeof SearchHitSchema>;
+
+/** A page of search hits, best first. */
+export const SearchResultsSchema = z.object({
+	/** Matching articles across all pages */
+	total: z.number().int().nonnegative(),
+	offset: z.number().int().nonnegative(),
+	hits: z.array(SearchHitSchema),
+});
+
+export type SearchResults = z.infer<typeof SearchResultsSchema>;
@@ -1,5 +1,5 @@
 import { z } from "zod";
-import { PoliticalBias, PoliticalBiasValues } from "./article";
+import { type Article, PoliticalBias, PoliticalBiasValues } from "./article";
 import { SourceNormalizerSchema } from "./normalizer";
 import { SourceParserSchema } from "./parser";
 
@@ -73,6 +73,42 @@ export const OwnershipSchema = z.object({
 /** TypeScript type inferred from OwnershipSchema */
 export type Ownership = z.infer<typeof OwnershipSchema>;
 
+/**
+ * Zod schema describing how the "html" parser extracts articles from a page.
+ *
+ * `item` is a CSS selector for the element wrapping each article; the other
+ * rules are selectors evaluated inside it. A rule may end in "@attr" to read
+ * an attribute instead of the text, e.g. "a.headline@href" or "img@data-src".
+ */
+export const ScrapeRulesSchema = z.object({
+	/** Selector for each article's container, e.g. "ul.stories > li" */
+	item: z.string().min(1),
+
+	/** Selector for the headline (falls back to the link text) */
+	title: z.string(),
+
+	/** Selector for the article link (defaults to the first a[href]) */
+	link: z.string().optional(),
+
+	/** Selector for the publication date (reads <time datetime> when present) */
+	date: z.string().optional(),
+
+	/** Optional Go time layout for site-specific dates, e.g. "02/01/2006" */
+	dateLayout: z.string().optional(),
+
+	/** Selector for the summary or teaser text */
+	summary: z.string().optional(),
+
+	/** Selector for the lead image (defaults to the first img) */
+	image: z.string().optional(),
+
+	/** Selector for the byline */
+	author: z.string().optional(),
+});
+
+/** TypeScript type inferred from ScrapeRulesSchema */
+export type ScrapeRules = z.infer<typeof ScrapeRulesSchema>;
+
 /**
  * List of authentication types that a source may require.
  */
@@ -82,7 +118,6 @@ export const AuthTypes = [
 	"bearerToken", // OAuth bearer token
 	"oauth", // Full OAuth flow
 	"basicAuth", // HTTP Basic Auth
-	"digestAuth", // HTTP Digest Auth
 ] as const;
 
 export type AuthType = (typeof AuthTypes)[number];
@@ -117,9 +152,16 @@ export const SourceSchema = z.object({
 	/** API or RSS endpoint URL (without any keys included) */
 	endpoint: z.string().url(),
 
-	/** Optional API key provided by the user for accessing the source */
+	/**
+	 * Optional API key provided by the user for accessing the source.
+	 * Loaded sources carry SECRET_MASK instead of a stored key; sending the
+	 * mask back keeps the stored key, an empty string removes it.
+	 */
 	apiKey: z.string().optional(),
 
+	/** Reference to the API key in the Go secret store */
+	apiKeyRef: z.string().optional(),
+
 	/** Optional instructions for using or integrating with this source */
 	instructions: z.string().optional(),
 
@@ -150,7 +192,14 @@ export const SourceSchema = z.object({
 	/** Optional API rate limit in requests per minute */
 	rateLimitPerMinute: z.number().optional(),
 
-	/** Optional custom headers for API requests (key-value map) */
+	/** Optional interval between background fetches, in minutes */
+	refreshIntervalMinutes: z.number().optional(),
+
+	/**
+	 * Optional custom headers for API requests (key-value map). Credential
+	 * headers such as Authorization are stored like API keys and load as
+	 * SECRET_MASK.
+	 */
 	headers: z.record(z.string(), z.string()).optional(),
 
 	/** Optional timestamp of the last time this source was updated */
@@ -168,9 +217,10 @@ export const SourceSchema = z.object({
 	 * Examples:
 	 * - "rss" → uses rssParser
 	 * - "json" → uses jsonParser
+	 * - "jsonfeed" → JSON Feed 1.0/1.1 (parsed on the Go side)
 	 * - "gdelt" → uses gdeltParser
 	 * - "hn" → Hacker News-specific parser
-	 * - "html" → raw HTML parsing (scraping)
+	 * - "html" → HTML scraping with the `scrape` selector rules (Go side)
 	 *
 	 * Defaults to `"json"` since most APIs return JSON.
 	 */
@@ -184,6 +234,7 @@ export const SourceSchema = z.object({
 	 *
 	 * Examples:
 	 * - "json" → normalizeJson
+	 * - "jsonfeed" → JSON Feed items (normalized on the Go side)
 	 * - "rss" → normalizeRss
 	 * - "gdelt" → normalizeGdelt
 	 * - "reddit" → normalizeReddit
@@ -210,6 +261,9 @@ export const SourceSchema = z.object({
    */
   ownership: OwnershipSchema.optional(),
 
+	/** CSS selector rules used by the "html" parser */
+	scrape: ScrapeRulesSchema.optional(),
+
 	/**
 	 * Confidence score for the source's bias classification
 	 * Range: 0 (uncertain) to 1 (fully confident)
@@ -250,3 +304,98 @@ export const ArticlesBySourceSchema = z.record(z.string(), z.instanceof(Uint8Arr
 
 /** TypeScript type for ArticlesBySource */
 export type ArticlesBySource = z.infer<typeof ArticlesBySourceSchema>;
+
+/**
+ * Dry-run result of a source's scrape rules, returned by PreviewScrape.
+ */
+export interface ScrapePreview {
+	/** Elements matched by the item selector */
+	matched: number;
+	/** Items that yielded a title or link */
+	extracted: number;
+	/** The first extracted items */
+	items: Article[];
+	/** Fetch metadata for the page (status code, bytes, duration, error) */
+	meta: { statusCode?: number; bytes?: number; durationMs?: number; error?: string };
+}
+
+/** How fetching one source went (FetchMeta in Go). */
+export interface FetchMeta {
+	/** HTTP status, 0 if no response was received */
+	statusCode: number;
+	bytes: number;
+	/** Time spent, including rate limit waits */
+	durationMs: number;
+	contentType?: string;
+	/** RFC3339 start time */
+	fetchedAt: string;
+	/** Source answered 304 or is still fresh: no new items */
+	notModified?: boolean;
+	/** RFC3339 time the source asked us to wait until (Retry-After) */
+	retryAt?: string;
+	/** Set if fetching, parsing or storing failed */
+	error?: string;
+}
+
+/**
+ * Result of FetchArticlesBySources: the JSON array of articles per source
+ * name, and metadata for every source.
+ */
+export interface FetchResult {
+	articles: Record<string, string>;
+	meta: Record<string, FetchMeta>;
+}
+
+/**
+ * Result of ImportOPML: which feeds were added, which were skipped and why,
+ * and the full source list after the import.
+ */
+export interface OPMLImport {
+	/** Names of the sources added */
+	added: string[];
+	/** Feeds already present (same endpoint or name) */
+	skipped: { name: string; endpoint: string; reason: string }[];
+	/** Feeds in the file */
+	total: number;
+	sources: Source[];
+}
+
+/** Result of ExportOPML. */
+export interface OPMLExport {
+	path: string;
+	/** Sources written; those without an endpoint are left out */
+	exported: number;
+}
+
+/**
+ * One problem found by ValidateSources, or returned as `data` when
+ * SaveSources fails with "validation_failed".
+ */
+export interface SourceIssue {
+	/** Position of the source in the list */
+	index: number;
+	/** Name of the source */
+	source: string;
+	/** Offending field, e.g. "endpoint" or "confidence" */
+	field: string;
+	message: string;
+}
+
+/**
+ * Stands in for a stored API key in loaded sources; keys never leave Go.
+ * Mirrors SecretMask in app_secrets.go.
+ */
+export const SECRET_MASK = "••••••••";
+
+/**
+ * How the Go secret store protects API keys (SecretsStatus, UnlockSecrets,
+ * SetSecretsPassphrase).
+ */
+export interface SecretsStatus {
+	/** "file": key file in the data directory; "passphrase": derived from the user's passphrase */
+	mode: "file" | "passphrase";
+	/** Waiting for the passphrase; stored keys can't be used or changed */
+	locked: boolean;
+	/** Number of stored keys, 0 while locked */
+	keys: number;
+}
@@ -1,6 +1,9 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {gdelt} from '../models';
 import {main} from '../models';
+import {query} from '../models';
+import {search} from '../models';
 
 export function AddDebugLog(arg1:main.DebugLogEntry):Promise<string>;
 
@@ -10,27 +13,55 @@ export function AppStatus():Promise<string>;
 
 export function AppUpdateStatus(arg1:string):Promise<string>;
 
+export function BuildGDELTEndpoint(arg1:gdelt.Query):Promise<string>;
+
+export function CancelRequest(arg1:string):Promise<string>;
+
+export function DeduplicateArticles(arg1:Array<main.Article>,arg2:boolean,arg3:string):Promise<string>;
+
 export function DeleteAnalyzedArticle(arg1:string):Promise<string>;
 
 export function DeleteFederatedArticle(arg1:string):Promise<string>;
 
 export function DeleteLocalArticle(arg1:string):Promise<string>;
 
+export function ExportOPML(arg1:string):Promise<string>;
+
+export function ExtractArticle(arg1:main.Article,arg2:string):Promise<string>;
+
 export function FetchAnalyzedArticles():Promise<string>;
 
-export function FetchArticlesBySources(arg1:Array<main.Source>):Promise<main.ArticlesBySource>;
+export function FetchAnalyzedArticlesPage(arg1:query.PageRequest,arg2:string):Promise<string>;
+
+export function FetchArticlesBySources(arg1:Array<main.Source>,arg2:string):Promise<string>;
 
 export function FetchDebugLogs():Promise<string>;
 
 export function FetchFederatedArticles():Promise<string>;
 
+export function FetchFederatedArticlesPage(arg1:query.PageRequest,arg2:string):Promise<string>;
+
 export function FetchLocalArticle(arg1:string):Promise<string>;
 
 export function FetchLocalArticles():Promise<string>;
 
+export function FetchLocalArticlesPage(arg1:query.PageRequest,arg2:string):Promise<string>;
+
+export function FetchSourceArticles(arg1:main.Source,arg2:string):Promise<string>;
+
+export function FindNearDuplicates(arg1:Array<main.Article>,arg2:number,arg3:boolean,arg4:string):Promise<string>;
+
+export function GetBlindspotReport(arg1:main.BlindspotQuery):Promise<string>;
+
 export function GetLocation():Promise<string>;
 
-export function LoadSources():Promise<Array<main.Source>>;
+export function GetP2PNodeInfo():Promise<string>;
+
+export function GetStoryClusters(arg1:string,arg2:string):Promise<string>;
+
+export function ImportOPML(arg1:string):Promise<string>;
+
+export function LoadSources():Promise<string>;
 
 export function OpenAbout():Promise<void>;
 
@@ -38,18 +69,36 @@ export function OpenSettings():Promise<void>;
 
 export function OpenURL(arg1:string):Promise<void>;
 
+export function PreviewScrape(arg1:main.Source,arg2:number,arg3:string):Promise<string>;
+
+export function QueryArticles(arg1:query.Filter,arg2:string,arg3:string,arg4:number,arg5:string):Promise<string>;
+
 export function SaveAnalyzedArticle(arg1:Record<string, any>):Promise<string>;
 
 export function SaveFederatedArticle(arg1:Record<string, any>):Promise<string>;
 
 export function SaveLocalArticle(arg1:Record<string, any>,arg2:boolean):Promise<string>;
 
-export function SaveSources(arg1:Array<main.Source>):Promise<void>;
+export function SaveSources(arg1:Array<main.Source>):Promise<string>;
+
+export function SearchArticles(arg1:search.Query,arg2:string):Promise<string>;
+
+export function SearchGDELT(arg1:gdelt.Query,arg2:number,arg3:string):Promise<string>;
+
+export function SecretsStatus():Promise<string>;
 
 export function SetLocation(arg1:string):Promise<string>;
 
+export function SetSecretsPassphrase(arg1:string,arg2:string):Promise<string>;
+
 export function StartP2PNode():Promise<string>;
 
 export function StopP2PNode():Promise<boolean>;
 
-export function TranslateArticle(arg1:any,arg2:string,arg3:Array<string>,arg4:boolean):Promise<string>;
+export function StreamArticles(arg1:string,arg2:query.PageRequest,arg3:string):Promise<string>;
+
+export function TranslateArticle(arg1:any,arg2:string,arg3:Array<string>,arg4:boolean,arg5:string):Promise<string>;
+
+export function UnlockSecrets(arg1:string):Promise<string>;
+
+export function ValidateSources(arg1:Array<main.Source>):Promise<string>;
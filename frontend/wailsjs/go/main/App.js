@@ -18,6 +18,18 @@ export function AppUpdateStatus(arg1) {
   return window['go']['main']['App']['AppUpdateStatus'](arg1);
 }
 
+export function BuildGDELTEndpoint(arg1) {
+  return window['go']['main']['App']['BuildGDELTEndpoint'](arg1);
+}
+
+export function CancelRequest(arg1) {
+  return window['go']['main']['App']['CancelRequest'](arg1);
+}
+
+export function DeduplicateArticles(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DeduplicateArticles'](arg1, arg2, arg3);
+}
+
 export function DeleteAnalyzedArticle(arg1) {
   return window['go']['main']['App']['DeleteAnalyzedArticle'](arg1);
 }
@@ -30,12 +42,24 @@ export function DeleteLocalArticle(arg1) {
   return window['go']['main']['App']['DeleteLocalArticle'](arg1);
 }
 
+export function ExportOPML(arg1) {
+  return window['go']['main']['App']['ExportOPML'](arg1);
+}
+
+export function ExtractArticle(arg1, arg2) {
+  return window['go']['main']['App']['ExtractArticle'](arg1, arg2);
+}
+
 export function FetchAnalyzedArticles() {
   return window['go']['main']['App']['FetchAnalyzedArticles']();
 }
 
-export function FetchArticlesBySources(arg1) {
-  return window['go']['main']['App']['FetchArticlesBySources'](arg1);
+export function FetchAnalyzedArticlesPage(arg1, arg2) {
+  return window['go']['main']['App']['FetchAnalyzedArticlesPage'](arg1, arg2);
+}
+
+export function FetchArticlesBySources(arg1, arg2) {
+  return window['go']['main']['App']['FetchArticlesBySources'](arg1, arg2);
 }
 
 export function FetchDebugLogs() {
@@ -46,6 +70,10 @@ export function FetchFederatedArticles() {
   return window['go']['main']['App']['FetchFederatedArticles']();
 }
 
+export function FetchFederatedArticlesPage(arg1, arg2) {
+  return window['go']['main']['App']['FetchFederatedArticlesPage'](arg1, arg2);
+}
+
 export function FetchLocalArticle(arg1) {
   return window['go']['main']['App']['FetchLocalArticle'](arg1);
 }
@@ -54,10 +82,38 @@ export function FetchLocalArticles() {
   return window['go']['main']['App']['FetchLocalArticles']();
 }
 
+export function FetchLocalArticlesPage(arg1, arg2) {
+  return window['go']['main']['App']['FetchLocalArticlesPage'](arg1, arg2);
+}
+
+export function FetchSourceArticles(arg1, arg2) {
+  return window['go']['main']['App']['FetchSourceArticles'](arg1, arg2);
+}
+
+export function FindNearDuplicates(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['FindNearDuplicates'](arg1, arg2, arg3, arg4);
+}
+
+export function GetBlindspotReport(arg1) {
+  return window['go']['main']['App']['GetBlindspotReport'](arg1);
+}
+
 export function GetLocation() {
   return window['go']['main']['App']['GetLocation']();
 }
 
+export function GetP2PNodeInfo() {
+  return window['go']['main']['App']['GetP2PNodeInfo']();
+}
+
+export function GetStoryClusters(arg1, arg2) {
+  return window['go']['main']['App']['GetStoryClusters'](arg1, arg2);
+}
+
+export function ImportOPML(arg1) {
+  return window['go']['main']['App']['ImportOPML'](arg1);
+}
+
 export function LoadSources() {
   return window['go']['main']['App']['LoadSources']();
 }
@@ -74,6 +130,14 @@ export function OpenURL(arg1) {
   return window['go']['main']['App']['OpenURL'](arg1);
 }
 
+export function PreviewScrape(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PreviewScrape'](arg1, arg2, arg3);
+}
+
+export function QueryArticles(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['QueryArticles'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function SaveAnalyzedArticle(arg1) {
   return window['go']['main']['App']['SaveAnalyzedArticle'](arg1);
 }
@@ -90,10 +154,26 @@ export function SaveSources(arg1) {
   return window['go']['main']['App']['SaveSources'](arg1);
 }
 
+export function SearchArticles(arg1, arg2) {
+  return window['go']['main']['App']['SearchArticles'](arg1, arg2);
+}
+
+export function SearchGDELT(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SearchGDELT'](arg1, arg2, arg3);
+}
+
+export function SecretsStatus() {
+  return window['go']['main']['App']['SecretsStatus']();
+}
+
 export function SetLocation(arg1) {
   return window['go']['main']['App']['SetLocation'](arg1);
 }
 
+export function SetSecretsPassphrase(arg1, arg2) {
+  return window['go']['main']['App']['SetSecretsPassphrase'](arg1, arg2);
+}
+
 export function StartP2PNode() {
   return window['go']['main']['App']['StartP2PNode']();
 }
@@ -102,6 +182,18 @@ export function StopP2PNode() {
   return window['go']['main']['App']['StopP2PNode']();
 }
 
-export function TranslateArticle(arg1, arg2, arg3, arg4) {
-  return window['go']['main']['App']['TranslateArticle'](arg1, arg2, arg3, arg4);
+export function StreamArticles(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StreamArticles'](arg1, arg2, arg3);
+}
+
+export function TranslateArticle(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['TranslateArticle'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function UnlockSecrets(arg1) {
+  return window['go']['main']['App']['UnlockSecrets'](arg1);
+}
+
+export function ValidateSources(arg1) {
+  return window['go']['main']['App']['ValidateSources'](arg1);
 }
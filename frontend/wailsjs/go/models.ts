@@ -1,5 +1,160 @@
+export namespace gdelt {
+	
+	export class Query {
+	    keywords: string[];
+	    sourceCountry?: string;
+	    language?: string;
+	    domain?: string;
+	    timespan?: string;
+	    // Go type: time
+	    start: any;
+	    // Go type: time
+	    end: any;
+	    maxRecords?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Query(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.keywords = source["keywords"];
+	        this.sourceCountry = source["sourceCountry"];
+	        this.language = source["language"];
+	        this.domain = source["domain"];
+	        this.timespan = source["timespan"];
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	        this.maxRecords = source["maxRecords"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace main {
 	
+	export class Article {
+	    id: string;
+	    title: string;
+	    url: string;
+	    content?: string;
+	    summary?: string;
+	    image?: string;
+	    categories?: string[];
+	    tags?: string[];
+	    language?: string;
+	    author?: string;
+	    publishedAt?: string;
+	    edition?: string;
+	    analyzed: boolean;
+	    ipfsHash?: string;
+	    raw?: any;
+	    sourceMeta?: SourceMeta;
+	    fetchedAt?: string;
+	    parser: string;
+	    normalizer: string;
+	    confidence?: number;
+	    mobileUrl?: string;
+	    source?: string;
+	    sourceDomain?: string;
+	    sourceType?: string;
+	    sourceCountry?: string;
+	    sources?: SourceRef[];
+	    duplicateOf?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Article(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.title = source["title"];
+	        this.url = source["url"];
+	        this.content = source["content"];
+	        this.summary = source["summary"];
+	        this.image = source["image"];
+	        this.categories = source["categories"];
+	        this.tags = source["tags"];
+	        this.language = source["language"];
+	        this.author = source["author"];
+	        this.publishedAt = source["publishedAt"];
+	        this.edition = source["edition"];
+	        this.analyzed = source["analyzed"];
+	        this.ipfsHash = source["ipfsHash"];
+	        this.raw = source["raw"];
+	        this.sourceMeta = this.convertValues(source["sourceMeta"], SourceMeta);
+	        this.fetchedAt = source["fetchedAt"];
+	        this.parser = source["parser"];
+	        this.normalizer = source["normalizer"];
+	        this.confidence = source["confidence"];
+	        this.mobileUrl = source["mobileUrl"];
+	        this.source = source["source"];
+	        this.sourceDomain = source["sourceDomain"];
+	        this.sourceType = source["sourceType"];
+	        this.sourceCountry = source["sourceCountry"];
+	        this.sources = this.convertValues(source["sources"], SourceRef);
+	        this.duplicateOf = source["duplicateOf"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BlindspotQuery {
+	    since?: string;
+	    until?: string;
+	    edition?: string;
+	    threshold?: number;
+	    minSources?: number;
+	    limit?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BlindspotQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.since = source["since"];
+	        this.until = source["until"];
+	        this.edition = source["edition"];
+	        this.threshold = source["threshold"];
+	        this.minSources = source["minSources"];
+	        this.limit = source["limit"];
+	    }
+	}
 	export class DebugLogEntry {
 	    _id: string;
 	    timestamp: string;
@@ -36,10 +191,37 @@ export namespace main {
 	        this.country = source["country"];
 	    }
 	}
+	export class ScrapeRules {
+	    item: string;
+	    title: string;
+	    link?: string;
+	    date?: string;
+	    dateLayout?: string;
+	    summary?: string;
+	    image?: string;
+	    author?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScrapeRules(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.item = source["item"];
+	        this.title = source["title"];
+	        this.link = source["link"];
+	        this.date = source["date"];
+	        this.dateLayout = source["dateLayout"];
+	        this.summary = source["summary"];
+	        this.image = source["image"];
+	        this.author = source["author"];
+	    }
+	}
 	export class Source {
 	    name: string;
 	    endpoint: string;
 	    apiKey?: string;
+	    apiKeyRef?: string;
 	    instructions?: string;
 	    apiLink?: string;
 	    enabled?: boolean;
@@ -50,6 +232,7 @@ export namespace main {
 	    region?: string;
 	    authType?: string;
 	    rateLimitPerMinute?: number;
+	    refreshIntervalMinutes?: number;
 	    headers?: Record<string, string>;
 	    lastUpdated?: string;
 	    pinned?: boolean;
@@ -59,6 +242,7 @@ export namespace main {
 	    factuality?: string;
 	    confidence?: number;
 	    ownership?: Ownership;
+	    scrape?: ScrapeRules;
 	    lastFetched?: string;
 	
 	    static createFrom(source: any = {}) {
@@ -70,6 +254,7 @@ export namespace main {
 	        this.name = source["name"];
 	        this.endpoint = source["endpoint"];
 	        this.apiKey = source["apiKey"];
+	        this.apiKeyRef = source["apiKeyRef"];
 	        this.instructions = source["instructions"];
 	        this.apiLink = source["apiLink"];
 	        this.enabled = source["enabled"];
@@ -80,6 +265,7 @@ export namespace main {
 	        this.region = source["region"];
 	        this.authType = source["authType"];
 	        this.rateLimitPerMinute = source["rateLimitPerMinute"];
+	        this.refreshIntervalMinutes = source["refreshIntervalMinutes"];
 	        this.headers = source["headers"];
 	        this.lastUpdated = source["lastUpdated"];
 	        this.pinned = source["pinned"];
@@ -89,6 +275,7 @@ export namespace main {
 	        this.factuality = source["factuality"];
 	        this.confidence = source["confidence"];
 	        this.ownership = this.convertValues(source["ownership"], Ownership);
+	        this.scrape = this.convertValues(source["scrape"], ScrapeRules);
 	        this.lastFetched = source["lastFetched"];
 	    }
 	
@@ -110,6 +297,124 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class SourceMeta {
+	    name: string;
+	    bias: string;
+	    confidence?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SourceMeta(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.bias = source["bias"];
+	        this.confidence = source["confidence"];
+	    }
+	}
+	export class SourceRef {
+	    name: string;
+	    bias?: string;
+	    url: string;
+	    id?: string;
+	    publishedAt?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SourceRef(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.bias = source["bias"];
+	        this.url = source["url"];
+	        this.id = source["id"];
+	        this.publishedAt = source["publishedAt"];
+	    }
+	}
+
+}
+
+export namespace query {
+	
+	export class Filter {
+	    stores?: string[];
+	    bias?: string;
+	    edition?: string;
+	    sentiment?: string;
+	    coverage?: string;
+	    confidence?: string;
+	    tags?: string[];
+	    source?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Filter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stores = source["stores"];
+	        this.bias = source["bias"];
+	        this.edition = source["edition"];
+	        this.sentiment = source["sentiment"];
+	        this.coverage = source["coverage"];
+	        this.confidence = source["confidence"];
+	        this.tags = source["tags"];
+	        this.source = source["source"];
+	    }
+	}
+	export class PageRequest {
+	    limit?: number;
+	    cursor?: string;
+	    sort?: string;
+	    ascending?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PageRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.limit = source["limit"];
+	        this.cursor = source["cursor"];
+	        this.sort = source["sort"];
+	        this.ascending = source["ascending"];
+	    }
+	}
+
+}
+
+export namespace search {
+	
+	export class Query {
+	    text: string;
+	    source?: string;
+	    bias?: string;
+	    edition?: string;
+	    language?: string;
+	    since?: string;
+	    until?: string;
+	    limit?: number;
+	    offset?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Query(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.source = source["source"];
+	        this.bias = source["bias"];
+	        this.edition = source["edition"];
+	        this.language = source["language"];
+	        this.since = source["since"];
+	        this.until = source["until"];
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	    }
+	}
 
 }
 
@@ -0,0 +1,94 @@
+package cluster
+
+import (
+	"sort"
+
+	"nous-app/internal/models"
+)
+
+// Defaults for BlindspotOptions.
+const (
+	DefaultBlindspotThreshold  = 10.0
+	DefaultBlindspotMinSources = 3
+)
+
+// Sides a blindspot can be on.
+const (
+	SideLeft  = "left"
+	SideRight = "right"
+)
+
+// BlindspotOptions tunes Blindspots.
+type BlindspotOptions struct {
+	// Threshold is the share (0-100) of a story's sources below which a side
+	// counts as not covering it. Defaults to DefaultBlindspotThreshold.
+	Threshold float64
+	// MinSources is the fewest sources of known bias a story needs before it
+	// can be a blindspot. Defaults to DefaultBlindspotMinSources.
+	MinSources int
+}
+
+// Blindspot is a story one side of the spectrum barely covers.
+type Blindspot struct {
+	models.StoryCluster
+	Side       string  `json:"side"`       // SideLeft or SideRight: the side missing the story
+	LeftShare  float64 `json:"leftShare"`  // Left and lean-left share (0-100) of the sources of known bias
+	RightShare float64 `json:"rightShare"` // Right and lean-right share (0-100) of the sources of known bias
+	Rated      int     `json:"rated"`      // Sources of known bias
+}
+
+// Blindspots returns the clusters where the left or the right share of
+// coverage is below the threshold while the other side's is not, ranked by
+// the number of sources covering the story. Shares are computed over the
+// sources whose bias is known, so unrated sources do not hide a blindspot.
+func Blindspots(clusters []models.StoryCluster, opts BlindspotOptions) []Blindspot {
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultBlindspotThreshold
+	}
+	if opts.MinSources <= 0 {
+		opts.MinSources = DefaultBlindspotMinSources
+	}
+
+	out := []Blindspot{}
+	for _, c := range clusters {
+		var left, right, rated int
+		for _, s := range c.Sources {
+			switch NormalizeBias(s.Bias) {
+			case models.BiasLeft, models.BiasLeanLeft:
+				left++
+			case models.BiasRight, models.BiasLeanRight:
+				right++
+			case models.BiasUnknown:
+				continue
+			}
+			rated++
+		}
+		if rated < opts.MinSources {
+			continue
+		}
+
+		b := Blindspot{
+			StoryCluster: c,
+			LeftShare:    100 * float64(left) / float64(rated),
+			RightShare:   100 * float64(right) / float64(rated),
+			Rated:        rated,
+		}
+		switch {
+		case b.LeftShare < opts.Threshold && b.RightShare >= opts.Threshold:
+			b.Side = SideLeft
+		case b.RightShare < opts.Threshold && b.LeftShare >= opts.Threshold:
+			b.Side = SideRight
+		default:
+			continue
+		}
+		out = append(out, b)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if len(out[i].Sources) != len(out[j].Sources) {
+			return len(out[i].Sources) > len(out[j].Sources)
+		}
+		return out[i].LastUpdated > out[j].LastUpdated
+	})
+	return out
+}
@@ -0,0 +1,316 @@
+// Package cluster groups articles about the same event into story clusters
+// and measures how each story is covered across the political spectrum.
+//
+// Articles are compared by the TF-IDF cosine similarity of their title and
+// lede, computed over the batch being clustered, so no embedding model is
+// needed. Clustering is a single pass in publication order: an article joins
+// the most similar cluster that was updated within the time window, or starts
+// a new one.
+package cluster
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"nous-app/internal/models"
+)
+
+// Defaults for Options.
+const (
+	DefaultThreshold = 0.3
+	DefaultWindow    = 48 * time.Hour
+)
+
+// Options tunes Build.
+type Options struct {
+	// Threshold is the cosine similarity (0-1) an article needs with a
+	// cluster to join it. Defaults to DefaultThreshold.
+	Threshold float64
+	// Window is how long after a cluster's latest article a new one may
+	// still join it. Defaults to DefaultWindow.
+	Window time.Duration
+	// Bias returns the bias of a source by name, for articles whose source
+	// metadata does not carry one. Optional.
+	Bias func(source string) string
+}
+
+type story struct {
+	members  []int              // Indexes into the sorted articles
+	centroid map[string]float64 // Sum of member vectors
+	norm     float64
+	latest   time.Time
+}
+
+// Build clusters articles into stories, ordered by the number of sources
+// covering them and then by recency. Every article ends up in exactly one
+// cluster; a story covered by a single source is a cluster of one.
+func Build(articles []models.Article, opts Options) []models.StoryCluster {
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultThreshold
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultWindow
+	}
+
+	sorted := make([]models.Article, len(articles))
+	copy(sorted, articles)
+	times := make([]time.Time, len(sorted))
+	for i := range sorted {
+		times[i] = ArticleTime(sorted[i])
+	}
+	order := make([]int, len(sorted))
+	for i := range order {
+		order[i] = i
+	}
+	// Undated articles go last so they can join the dated stories.
+	sort.SliceStable(order, func(i, j int) bool {
+		ti, tj := times[order[i]], times[order[j]]
+		if ti.IsZero() != tj.IsZero() {
+			return !ti.IsZero()
+		}
+		return ti.Before(tj)
+	})
+
+	tfs := make([]map[string]float64, len(sorted))
+	df := map[string]int{}
+	for i, a := range sorted {
+		tfs[i] = terms(a)
+		for t := range tfs[i] {
+			df[t]++
+		}
+	}
+	idf := make(map[string]float64, len(df))
+	n := float64(len(sorted))
+	for t, d := range df {
+		idf[t] = math.Log((n+1)/(float64(d)+1)) + 1
+	}
+
+	var stories []*story
+	storyOf := map[string]*story{} // Article ID → story
+	vectors := make([]vector, len(sorted))
+	for _, i := range order {
+		a := sorted[i]
+		vectors[i] = weigh(tfs[i], idf)
+		t := times[i]
+
+		var best *story
+		if a.DuplicateOf != nil {
+			best = storyOf[*a.DuplicateOf]
+		}
+		if best == nil {
+			bestSim := opts.Threshold
+			for _, s := range stories {
+				if !t.IsZero() && !s.latest.IsZero() && t.Sub(s.latest) > opts.Window {
+					continue
+				}
+				if sim := cosine(vectors[i], s.centroid, s.norm); sim >= bestSim {
+					best, bestSim = s, sim
+				}
+			}
+		}
+		if best == nil {
+			best = &story{centroid: map[string]float64{}}
+			stories = append(stories, best)
+		}
+
+		best.members = append(best.members, i)
+		for term, w := range vectors[i] {
+			best.centroid[term] += w
+		}
+		best.norm = norm(best.centroid)
+		if t.After(best.latest) {
+			best.latest = t
+		}
+		if a.ID != "" {
+			storyOf[a.ID] = best
+		}
+	}
+
+	clusters := make([]models.StoryCluster, 0, len(stories))
+	for _, s := range stories {
+		clusters = append(clusters, s.build(sorted, vectors, opts))
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if len(clusters[i].Sources) != len(clusters[j].Sources) {
+			return len(clusters[i].Sources) > len(clusters[j].Sources)
+		}
+		return clusters[i].LastUpdated > clusters[j].LastUpdated
+	})
+	return clusters
+}
+
+func (s *story) build(articles []models.Article, vectors []vector, opts Options) models.StoryCluster {
+	first := articles[s.members[0]]
+	c := models.StoryCluster{StoryID: storyID(first)}
+
+	// The canonical title is that of the article closest to the centroid,
+	// the earliest one on ties.
+	rep, repSim := s.members[0], -1.0
+	for _, i := range s.members {
+		if sim := cosine(vectors[i], s.centroid, s.norm); sim > repSim+1e-9 {
+			rep, repSim = i, sim
+		}
+	}
+	c.Title = CleanTitle(articles[rep].Title)
+	for _, i := range append([]int{rep}, s.members...) {
+		if sum := articles[i].Summary; sum != nil && strings.TrimSpace(*sum) != "" {
+			c.Summary = strings.TrimSpace(*sum)
+			break
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, i := range s.members {
+		a := articles[i]
+		if a.ID != "" {
+			c.ArticleIDs = append(c.ArticleIDs, a.ID)
+		}
+		for _, sc := range sourcesOf(a, opts.Bias) {
+			key := strings.ToLower(sc.Name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			c.Sources = append(c.Sources, sc)
+		}
+	}
+	c.Coverage = ComputeCoverage(c.Sources)
+	if !s.latest.IsZero() {
+		c.LastUpdated = s.latest.UTC().Format(time.RFC3339)
+	}
+	return c
+}
+
+// sourcesOf lists the sources that carried a: every merged source when dedup
+// recorded them, otherwise the article's own source.
+func sourcesOf(a models.Article, biasOf func(string) string) []models.SourceCluster {
+	var out []models.SourceCluster
+	add := func(name, bias, articleURL string) {
+		if name == "" {
+			name = hostOf(articleURL)
+		}
+		if name == "" {
+			return
+		}
+		bias = NormalizeBias(bias)
+		if bias == models.BiasUnknown && biasOf != nil {
+			bias = NormalizeBias(biasOf(name))
+		}
+		out = append(out, models.SourceCluster{Name: name, Bias: bias, ArticleURL: articleURL})
+	}
+
+	if len(a.Sources) > 0 {
+		for _, ref := range a.Sources {
+			add(ref.Name, ref.Bias, ref.URL)
+		}
+		return out
+	}
+
+	name, bias := "", ""
+	if a.Source != nil {
+		name = *a.Source
+	}
+	if a.SourceMeta != nil {
+		if name == "" {
+			name = a.SourceMeta.Name
+		}
+		bias = a.SourceMeta.Bias
+	}
+	if name == "" && a.SourceDomain != nil {
+		name = *a.SourceDomain
+	}
+	add(name, bias, a.URL)
+	return out
+}
+
+// ComputeCoverage returns the percentage of sources per bias, like
+// computeCoverage in the frontend.
+func ComputeCoverage(sources []models.SourceCluster) models.Coverage {
+	var c models.Coverage
+	if len(sources) == 0 {
+		return c
+	}
+	share := 100 / float64(len(sources))
+	for _, s := range sources {
+		switch NormalizeBias(s.Bias) {
+		case models.BiasLeft:
+			c.Left += share
+		case models.BiasLeanLeft:
+			c.LeanLeft += share
+		case models.BiasCenter:
+			c.Center += share
+		case models.BiasLeanRight:
+			c.LeanRight += share
+		case models.BiasRight:
+			c.Right += share
+		default:
+			c.Unknown += share
+		}
+	}
+	return c
+}
+
+// NormalizeBias maps the bias labels found in source lists ("Lean Left",
+// "left-center", "centre", ...) onto the Bias* values.
+func NormalizeBias(bias string) string {
+	b := strings.NewReplacer("_", "-", " ", "-").Replace(strings.ToLower(strings.TrimSpace(bias)))
+	switch b {
+	case "left", "far-left", "extreme-left":
+		return models.BiasLeft
+	case "lean-left", "left-center", "center-left", "centre-left", "left-leaning":
+		return models.BiasLeanLeft
+	case "center", "centre", "neutral", "least-biased", "least", "balanced":
+		return models.BiasCenter
+	case "lean-right", "right-center", "center-right", "centre-right", "right-leaning":
+		return models.BiasLeanRight
+	case "right", "far-right", "extreme-right":
+		return models.BiasRight
+	}
+	return models.BiasUnknown
+}
+
+// ArticleTime is when an article was published, or fetched if its publication
+// date is unknown; zero if neither parses.
+func ArticleTime(a models.Article) time.Time {
+	for _, s := range []*string{a.PublishedAt, a.FetchedAt} {
+		if s == nil {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, *s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// storyID derives the cluster ID from its first article, so the ID stays the
+// same as the story grows.
+func storyID(first models.Article) string {
+	key := first.ID
+	if key == "" {
+		key = first.URL + "\n" + first.Title
+	}
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+func hostOf(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+func norm(v map[string]float64) float64 {
+	var sum float64
+	for _, w := range v {
+		sum += w * w
+	}
+	return math.Sqrt(sum)
+}
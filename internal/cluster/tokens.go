@@ -0,0 +1,146 @@
+package cluster
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"nous-app/internal/models"
+)
+
+// bodyWords is how many words of an article's summary or content count
+// towards its vector; the lede carries the event, the rest adds noise.
+const bodyWords = 60
+
+// titleWeight is how much more a title word counts than a body word.
+const titleWeight = 2
+
+// vector is a sparse, L2-normalised term weight vector.
+type vector map[string]float64
+
+// terms returns the term frequencies of an article's title and lede.
+func terms(a models.Article) map[string]float64 {
+	tf := map[string]float64{}
+	for _, w := range words(CleanTitle(a.Title)) {
+		tf[w] += titleWeight
+	}
+
+	body := ""
+	if a.Summary != nil && strings.TrimSpace(*a.Summary) != "" {
+		body = *a.Summary
+	} else if a.Content != nil {
+		body = *a.Content
+	}
+	for i, w := range words(stripTags(body)) {
+		if i >= bodyWords {
+			break
+		}
+		tf[w]++
+	}
+	return tf
+}
+
+// weigh turns term frequencies into a normalised TF-IDF vector.
+func weigh(tf map[string]float64, idf map[string]float64) vector {
+	v := make(vector, len(tf))
+	var norm float64
+	for t, f := range tf {
+		w := (1 + math.Log(f)) * idf[t]
+		v[t] = w
+		norm += w * w
+	}
+	if norm == 0 {
+		return v
+	}
+	norm = math.Sqrt(norm)
+	for t := range v {
+		v[t] /= norm
+	}
+	return v
+}
+
+// cosine is the cosine similarity of a normalised vector and an
+// unnormalised centroid with the given norm.
+func cosine(v vector, centroid map[string]float64, norm float64) float64 {
+	if norm == 0 {
+		return 0
+	}
+	var dot float64
+	for t, w := range v {
+		dot += w * centroid[t]
+	}
+	return dot / norm
+}
+
+// words lower-cases text and returns its words, dropping stop words, single
+// letters and possessive suffixes.
+func words(text string) []string {
+	var out []string
+	for _, f := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’'
+	}) {
+		f = strings.Trim(f, "'’")
+		f = strings.TrimSuffix(strings.TrimSuffix(f, "'s"), "’s")
+		if len([]rune(f)) < 2 || stopWords[f] {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// stripTags removes HTML tags so markup in Content does not become terms.
+func stripTags(s string) string {
+	if !strings.Contains(s, "<") {
+		return s
+	}
+	var b strings.Builder
+	inTag := false
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+			b.WriteByte(' ')
+		case r == '>':
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// CleanTitle removes a trailing " - Source" or " | Source" site name from a
+// headline, as added by most feeds and aggregators.
+func CleanTitle(title string) string {
+	title = strings.TrimSpace(title)
+	for _, sep := range []string{" | ", " - ", " – ", " — "} {
+		i := strings.LastIndex(title, sep)
+		if i <= 0 {
+			continue
+		}
+		// Only strip short suffixes; a long one is part of the headline.
+		if suffix := title[i+len(sep):]; len(strings.Fields(suffix)) <= 4 && len(title[:i]) >= 20 {
+			return strings.TrimSpace(title[:i])
+		}
+	}
+	return title
+}
+
+var stopWords = func() map[string]bool {
+	m := map[string]bool{}
+	for _, w := range strings.Fields(`
+		a about above after again against all also am an and any are as at be because been
+		before being below between both but by can could did do does doing down during each
+		few for from further had has have having he her here hers herself him himself his how
+		if in into is it its itself just me more most my myself no nor not now of off on once
+		only or other our ours out over own said same says she should so some such than that
+		the their theirs them themselves then there these they this those through to too under
+		until up us very was we were what when where which while who whom why will with would
+		you your yours new news after amid over says say year years week today yesterday
+		tomorrow one two three first last latest update live report reports video photos
+		watch read more breaking exclusive`) {
+		m[w] = true
+	}
+	return m
+}()
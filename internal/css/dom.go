@@ -0,0 +1,80 @@
+package css
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Attr returns the value of n's attribute name, or "".
+func Attr(n *html.Node, name string) string {
+	v, _ := lookupAttr(n, name)
+	return v
+}
+
+func lookupAttr(n *html.Node, name string) (string, bool) {
+	if n == nil {
+		return "", false
+	}
+	for _, a := range n.Attr {
+		if a.Namespace == "" && strings.EqualFold(a.Key, name) {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// blockElements break text: their content is separated from the text
+// around them.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"br": true, "dd": true, "details": true, "dialog": true, "div": true,
+	"dl": true, "dt": true, "fieldset": true, "figcaption": true,
+	"figure": true, "footer": true, "form": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "header": true,
+	"hr": true, "li": true, "main": true, "nav": true, "ol": true, "p": true,
+	"pre": true, "section": true, "summary": true, "table": true,
+	"td": true, "th": true, "tr": true, "ul": true,
+}
+
+// Text returns the text content of n with whitespace collapsed. Text in
+// inline elements runs on as in the rendered page ("<b>un</b>usual" is
+// "unusual"), while block elements and <br> separate words. Script, style
+// and template contents are skipped.
+func Text(n *html.Node) string {
+	var b strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			switch n.Data {
+			case "script", "style", "template", "noscript":
+				return
+			}
+			if blockElements[n.Data] {
+				b.WriteByte(' ')
+				defer b.WriteByte(' ')
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	if n != nil {
+		collect(n)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// Closest returns n or its nearest ancestor matching s, or nil.
+func (s *Selector) Closest(n *html.Node) *html.Node {
+	for ; n != nil; n = n.Parent {
+		if s.Match(n) {
+			return n
+		}
+	}
+	return nil
+}
@@ -0,0 +1,603 @@
+// Package css compiles CSS selectors and matches them against parsed HTML
+// (golang.org/x/net/html). It covers the selectors useful for scraping:
+//
+//   - type, universal, #id and .class
+//   - attributes: [a], [a=v], [a~=v], [a|=v], [a^=v], [a$=v], [a*=v], with an
+//     optional " i" flag for case-insensitive values
+//   - combinators: descendant (space), child (>), adjacent (+) and sibling (~)
+//   - :first-child, :last-child, :only-child, :first-of-type, :last-of-type,
+//     :nth-child(an+b), :nth-last-child(an+b), :nth-of-type(an+b), :empty and
+//     :not(selector list)
+//   - selector lists separated by commas
+package css
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Selector is a compiled selector list.
+type Selector struct {
+	source string
+	groups []complexSelector
+}
+
+// Compile parses a selector list.
+func Compile(selector string) (*Selector, error) {
+	p := &selectorParser{s: selector}
+	groups, err := p.parseList()
+	if err != nil {
+		return nil, fmt.Errorf("css: %q: %w", selector, err)
+	}
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return nil, fmt.Errorf("css: %q: unexpected %q at offset %d", selector, p.s[p.pos], p.pos)
+	}
+	return &Selector{source: selector, groups: groups}, nil
+}
+
+// MustCompile is like Compile but panics on an invalid selector.
+func MustCompile(selector string) *Selector {
+	s, err := Compile(selector)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// String returns the selector as it was written.
+func (s *Selector) String() string {
+	return s.source
+}
+
+// Match reports whether n is an element matching the selector.
+func (s *Selector) Match(n *html.Node) bool {
+	if n == nil || n.Type != html.ElementNode {
+		return false
+	}
+	for _, g := range s.groups {
+		if g.match(n, len(g.parts)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAll returns the descendants of root matching the selector, in document
+// order.
+func (s *Selector) MatchAll(root *html.Node) []*html.Node {
+	var out []*html.Node
+	walk(root, func(n *html.Node) bool {
+		if s.Match(n) {
+			out = append(out, n)
+		}
+		return true
+	})
+	return out
+}
+
+// MatchFirst returns the first descendant of root matching the selector, or
+// nil.
+func (s *Selector) MatchFirst(root *html.Node) *html.Node {
+	var found *html.Node
+	walk(root, func(n *html.Node) bool {
+		if s.Match(n) {
+			found = n
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// walk visits the descendants of root depth-first until visit returns false.
+func walk(root *html.Node, visit func(*html.Node) bool) bool {
+	if root == nil {
+		return true
+	}
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && !visit(c) {
+			return false
+		}
+		if !walk(c, visit) {
+			return false
+		}
+	}
+	return true
+}
+
+// ----------------------
+// Matching
+// ----------------------
+
+// complexSelector is a chain of compounds joined by combinators; combs[i]
+// joins parts[i] and parts[i+1].
+type complexSelector struct {
+	parts []compound
+	combs []byte
+}
+
+// match checks parts[i] against n and the rest of the chain against n's
+// ancestors or preceding siblings, right to left.
+func (c complexSelector) match(n *html.Node, i int) bool {
+	if !c.parts[i].match(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	switch c.combs[i-1] {
+	case ' ':
+		for p := n.Parent; p != nil && p.Type == html.ElementNode; p = p.Parent {
+			if c.match(p, i-1) {
+				return true
+			}
+		}
+	case '>':
+		if p := n.Parent; p != nil && p.Type == html.ElementNode {
+			return c.match(p, i-1)
+		}
+	case '+':
+		if p := prevElement(n); p != nil {
+			return c.match(p, i-1)
+		}
+	case '~':
+		for p := prevElement(n); p != nil; p = prevElement(p) {
+			if c.match(p, i-1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compound is a sequence of simple selectors that must all match one element.
+type compound struct {
+	tag     string // Lower case, "" for any
+	id      string
+	classes []string
+	attrs   []attrSelector
+	pseudos []func(*html.Node) bool
+}
+
+func (c compound) match(n *html.Node) bool {
+	if c.tag != "" && n.Data != c.tag {
+		return false
+	}
+	if c.id != "" && Attr(n, "id") != c.id {
+		return false
+	}
+	for _, class := range c.classes {
+		if !containsWord(Attr(n, "class"), class, false) {
+			return false
+		}
+	}
+	for _, a := range c.attrs {
+		if !a.match(n) {
+			return false
+		}
+	}
+	for _, p := range c.pseudos {
+		if !p(n) {
+			return false
+		}
+	}
+	return true
+}
+
+type attrSelector struct {
+	name  string
+	op    string // "" for presence, else "=", "~=", "|=", "^=", "$=" or "*="
+	value string
+	fold  bool // Case-insensitive value comparison
+}
+
+func (a attrSelector) match(n *html.Node) bool {
+	v, ok := lookupAttr(n, a.name)
+	if !ok {
+		return false
+	}
+	want := a.value
+	if a.fold {
+		v, want = strings.ToLower(v), strings.ToLower(want)
+	}
+	switch a.op {
+	case "":
+		return true
+	case "=":
+		return v == want
+	case "~=":
+		return containsWord(v, want, false)
+	case "|=":
+		return v == want || strings.HasPrefix(v, want+"-")
+	case "^=":
+		return want != "" && strings.HasPrefix(v, want)
+	case "$=":
+		return want != "" && strings.HasSuffix(v, want)
+	case "*=":
+		return want != "" && strings.Contains(v, want)
+	}
+	return false
+}
+
+func containsWord(list, word string, fold bool) bool {
+	if word == "" {
+		return false
+	}
+	for _, w := range strings.Fields(list) {
+		if w == word || fold && strings.EqualFold(w, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func prevElement(n *html.Node) *html.Node {
+	for p := n.PrevSibling; p != nil; p = p.PrevSibling {
+		if p.Type == html.ElementNode {
+			return p
+		}
+	}
+	return nil
+}
+
+func nextElement(n *html.Node) *html.Node {
+	for p := n.NextSibling; p != nil; p = p.NextSibling {
+		if p.Type == html.ElementNode {
+			return p
+		}
+	}
+	return nil
+}
+
+// position returns n's 1-based index among its element siblings, counted from
+// the start or the end, optionally only among siblings of the same type.
+func position(n *html.Node, fromEnd, sameType bool) int {
+	i := 1
+	step := prevElement
+	if fromEnd {
+		step = nextElement
+	}
+	for s := step(n); s != nil; s = step(s) {
+		if !sameType || s.Data == n.Data {
+			i++
+		}
+	}
+	return i
+}
+
+// nth is an an+b expression.
+type nth struct{ a, b int }
+
+func (x nth) match(i int) bool {
+	if x.a == 0 {
+		return i == x.b
+	}
+	d := i - x.b
+	return d/x.a >= 0 && d%x.a == 0
+}
+
+// ----------------------
+// Parsing
+// ----------------------
+
+type selectorParser struct {
+	s   string
+	pos int
+}
+
+func (p *selectorParser) parseList() ([]complexSelector, error) {
+	var groups []complexSelector
+	for {
+		p.skipSpace()
+		c, err := p.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, c)
+		p.skipSpace()
+		if !p.consume(',') {
+			return groups, nil
+		}
+	}
+}
+
+func (p *selectorParser) parseComplex() (complexSelector, error) {
+	var c complexSelector
+	first, err := p.parseCompound()
+	if err != nil {
+		return c, err
+	}
+	c.parts = append(c.parts, first)
+
+	for {
+		hadSpace := p.skipSpace()
+		if p.pos >= len(p.s) || p.s[p.pos] == ',' || p.s[p.pos] == ')' {
+			return c, nil
+		}
+		comb := byte(' ')
+		switch p.s[p.pos] {
+		case '>', '+', '~':
+			comb = p.s[p.pos]
+			p.pos++
+			p.skipSpace()
+		default:
+			if !hadSpace {
+				return c, fmt.Errorf("unexpected %q at offset %d", p.s[p.pos], p.pos)
+			}
+		}
+		next, err := p.parseCompound()
+		if err != nil {
+			return c, err
+		}
+		c.combs = append(c.combs, comb)
+		c.parts = append(c.parts, next)
+	}
+}
+
+func (p *selectorParser) parseCompound() (compound, error) {
+	var c compound
+	start := p.pos
+
+	if p.consume('*') {
+		// Universal selector, matches any tag
+	} else if name := p.ident(); name != "" {
+		c.tag = strings.ToLower(name)
+	}
+
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case '#':
+			p.pos++
+			id := p.ident()
+			if id == "" {
+				return c, fmt.Errorf("expected an id at offset %d", p.pos)
+			}
+			c.id = id
+		case '.':
+			p.pos++
+			class := p.ident()
+			if class == "" {
+				return c, fmt.Errorf("expected a class name at offset %d", p.pos)
+			}
+			c.classes = append(c.classes, class)
+		case '[':
+			p.pos++
+			a, err := p.parseAttr()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, a)
+		case ':':
+			p.pos++
+			fn, err := p.parsePseudo()
+			if err != nil {
+				return c, err
+			}
+			c.pseudos = append(c.pseudos, fn)
+		default:
+			if p.pos == start {
+				return c, fmt.Errorf("expected a selector at offset %d", p.pos)
+			}
+			return c, nil
+		}
+	}
+	if p.pos == start {
+		return c, fmt.Errorf("expected a selector at offset %d", p.pos)
+	}
+	return c, nil
+}
+
+func (p *selectorParser) parseAttr() (attrSelector, error) {
+	var a attrSelector
+	p.skipSpace()
+	a.name = strings.ToLower(p.ident())
+	if a.name == "" {
+		return a, fmt.Errorf("expected an attribute name at offset %d", p.pos)
+	}
+	p.skipSpace()
+	if p.consume(']') {
+		return a, nil
+	}
+
+	for _, op := range []string{"=", "~=", "|=", "^=", "$=", "*="} {
+		if strings.HasPrefix(p.s[p.pos:], op) {
+			a.op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if a.op == "" {
+		return a, fmt.Errorf("expected an attribute operator at offset %d", p.pos)
+	}
+	p.skipSpace()
+
+	if p.pos < len(p.s) && (p.s[p.pos] == '"' || p.s[p.pos] == '\'') {
+		v, err := p.quoted()
+		if err != nil {
+			return a, err
+		}
+		a.value = v
+	} else {
+		a.value = p.ident()
+	}
+	p.skipSpace()
+	if p.pos < len(p.s) && (p.s[p.pos] == 'i' || p.s[p.pos] == 'I') {
+		a.fold = true
+		p.pos++
+		p.skipSpace()
+	}
+	if !p.consume(']') {
+		return a, fmt.Errorf("expected ] at offset %d", p.pos)
+	}
+	return a, nil
+}
+
+func (p *selectorParser) parsePseudo() (func(*html.Node) bool, error) {
+	name := strings.ToLower(p.ident())
+	switch name {
+	case "first-child":
+		return func(n *html.Node) bool { return prevElement(n) == nil }, nil
+	case "last-child":
+		return func(n *html.Node) bool { return nextElement(n) == nil }, nil
+	case "only-child":
+		return func(n *html.Node) bool { return prevElement(n) == nil && nextElement(n) == nil }, nil
+	case "first-of-type":
+		return func(n *html.Node) bool { return position(n, false, true) == 1 }, nil
+	case "last-of-type":
+		return func(n *html.Node) bool { return position(n, true, true) == 1 }, nil
+	case "empty":
+		return func(n *html.Node) bool {
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode || c.Type == html.TextNode && c.Data != "" {
+					return false
+				}
+			}
+			return true
+		}, nil
+	case "nth-child", "nth-last-child", "nth-of-type", "nth-last-of-type":
+		arg, err := p.argument()
+		if err != nil {
+			return nil, err
+		}
+		x, err := parseNth(arg)
+		if err != nil {
+			return nil, err
+		}
+		fromEnd := strings.Contains(name, "last")
+		sameType := strings.HasSuffix(name, "of-type")
+		return func(n *html.Node) bool { return x.match(position(n, fromEnd, sameType)) }, nil
+	case "not":
+		if !p.consume('(') {
+			return nil, fmt.Errorf("expected ( after :not")
+		}
+		groups, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(')') {
+			return nil, fmt.Errorf("expected ) at offset %d", p.pos)
+		}
+		inner := &Selector{groups: groups}
+		return func(n *html.Node) bool { return !inner.Match(n) }, nil
+	case "":
+		return nil, fmt.Errorf("expected a pseudo-class at offset %d", p.pos)
+	default:
+		return nil, fmt.Errorf("unsupported pseudo-class :%s", name)
+	}
+}
+
+// argument reads a parenthesised argument verbatim.
+func (p *selectorParser) argument() (string, error) {
+	if !p.consume('(') {
+		return "", fmt.Errorf("expected ( at offset %d", p.pos)
+	}
+	end := strings.IndexByte(p.s[p.pos:], ')')
+	if end < 0 {
+		return "", fmt.Errorf("unterminated ( at offset %d", p.pos)
+	}
+	arg := p.s[p.pos : p.pos+end]
+	p.pos += end + 1
+	return strings.TrimSpace(arg), nil
+}
+
+// parseNth parses "odd", "even", "3", "n", "2n+1", "-n+3" and the like.
+func parseNth(s string) (nth, error) {
+	s = strings.ToLower(strings.ReplaceAll(s, " ", ""))
+	switch s {
+	case "odd":
+		return nth{2, 1}, nil
+	case "even":
+		return nth{2, 0}, nil
+	}
+	i := strings.IndexByte(s, 'n')
+	if i < 0 {
+		b, err := strconv.Atoi(s)
+		if err != nil {
+			return nth{}, fmt.Errorf("invalid nth expression %q", s)
+		}
+		return nth{0, b}, nil
+	}
+
+	var x nth
+	switch a := s[:i]; a {
+	case "", "+":
+		x.a = 1
+	case "-":
+		x.a = -1
+	default:
+		v, err := strconv.Atoi(a)
+		if err != nil {
+			return nth{}, fmt.Errorf("invalid nth expression %q", s)
+		}
+		x.a = v
+	}
+	if b := s[i+1:]; b != "" {
+		v, err := strconv.Atoi(b)
+		if err != nil {
+			return nth{}, fmt.Errorf("invalid nth expression %q", s)
+		}
+		x.b = v
+	}
+	return x, nil
+}
+
+func (p *selectorParser) skipSpace() bool {
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n\r\f", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *selectorParser) consume(b byte) bool {
+	if p.pos < len(p.s) && p.s[p.pos] == b {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// ident reads an identifier, honouring backslash escapes.
+func (p *selectorParser) ident() string {
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.s):
+			b.WriteByte(p.s[p.pos+1])
+			p.pos += 2
+		case c == '-' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80:
+			b.WriteByte(c)
+			p.pos++
+		default:
+			return b.String()
+		}
+	}
+	return b.String()
+}
+
+func (p *selectorParser) quoted() (string, error) {
+	q := p.s[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.s):
+			b.WriteByte(p.s[p.pos+1])
+			p.pos += 2
+		case c == q:
+			p.pos++
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
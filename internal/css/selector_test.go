@@ -0,0 +1,202 @@
+package css
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// page has an id on every element the selectors below may match.
+const page = `<html><body id="body">
+<div id="main" class="content wide" data-kind="Story">
+  <h1 id="h1" lang="en-US">Title</h1>
+  <p id="p1" class="lead">First</p>
+  <p id="p2">Second <a id="a1" href="https://example.com/a.pdf" rel="nofollow noopener">link</a></p>
+  <span id="s1"></span>
+  <p id="p3" class="note">Third</p>
+  <ul id="list">
+    <li id="li1">one</li><li id="li2">two</li><li id="li3">three</li><li id="li4">four</li><li id="li5">five</li>
+  </ul>
+</div>
+<aside id="side"><p id="p4"> </p><a id="a2" href="/local">x</a></aside>
+</body></html>`
+
+func parsePage(t *testing.T) *html.Node {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// ids returns the ids of the elements s matches, in document order.
+func ids(s *Selector, doc *html.Node) string {
+	var out []string
+	for _, n := range s.MatchAll(doc) {
+		out = append(out, Attr(n, "id"))
+	}
+	return strings.Join(out, " ")
+}
+
+func TestMatch(t *testing.T) {
+	doc := parsePage(t)
+	tests := []struct {
+		selector string
+		want     string
+	}{
+		// Simple selectors
+		{"h1", "h1"},
+		{"H1", "h1"},
+		{"#p2", "p2"},
+		{".lead", "p1"},
+		{"div.content.wide", "main"},
+		{"p.lead.note", ""},
+		{"aside *", "p4 a2"},
+		{"h1, #s1, h1", "h1 s1"},
+
+		// Combinators
+		{"div p", "p1 p2 p3"},
+		{"body > p", ""},
+		{"div > p > a", "a1"},
+		{"body a", "a1 a2"},
+		{"h1 + p", "p1"},
+		{"p + span", "s1"},
+		{"h1 ~ p", "p1 p2 p3"},
+		{"span ~ *", "p3 list"},
+		{"div>p+p", "p2"},
+		{"div li + li ~ li", "li3 li4 li5"},
+
+		// Attributes
+		{"[data-kind]", "main"},
+		{"[DATA-KIND=Story]", "main"},
+		{"[data-kind=story]", ""},
+		{"[data-kind=story i]", "main"},
+		{`[rel~="noopener"]`, "a1"},
+		{"[rel~=noo]", ""},
+		{"[lang|=en]", "h1"},
+		{"[lang|=en-US]", "h1"},
+		{"[lang|=e]", ""},
+		{`a[href^="https://"]`, "a1"},
+		{"a[href$='.pdf']", "a1"},
+		{"a[href*=local]", "a2"},
+		{`a[href^=""]`, ""},
+		{`[class="content wide"]`, "main"},
+
+		// Structural pseudo-classes
+		{"li:first-child", "li1"},
+		{"li:last-child", "li5"},
+		{"p:first-child", "p4"},
+		{"p:first-of-type", "p1 p4"},
+		{"p:last-of-type", "p3 p4"},
+		{"#list :only-child", ""},
+		{"span:empty", "s1"},
+		{"p:empty", ""},
+		{"li:nth-child(2)", "li2"},
+		{"li:nth-child(odd)", "li1 li3 li5"},
+		{"li:nth-child(even)", "li2 li4"},
+		{"li:nth-child(2n+1)", "li1 li3 li5"},
+		{"li:nth-child(3n)", "li3"},
+		{"li:nth-child(n+4)", "li4 li5"},
+		{"li:nth-child(-n+2)", "li1 li2"},
+		{"li:nth-child( -2n + 5 )", "li1 li3 li5"},
+		{"li:nth-last-child(1)", "li5"},
+		{"li:nth-last-child(-n+2)", "li4 li5"},
+		{"#main > *:nth-of-type(2)", "p2"},
+		{"#main > p:nth-last-of-type(1)", "p3"},
+
+		// :not
+		{"p:not(.lead)", "p2 p3 p4"},
+		{"p:not(.lead, .note)", "p2 p4"},
+		{"li:not(:nth-child(odd))", "li2 li4"},
+		{"#main > :not(p):not(ul)", "h1 s1"},
+		{"a:not([href^=http])", "a2"},
+	}
+	for _, tt := range tests {
+		s, err := Compile(tt.selector)
+		if err != nil {
+			t.Errorf("%s: %v", tt.selector, err)
+			continue
+		}
+		if got := ids(s, doc); got != tt.want {
+			t.Errorf("%s matched %q, want %q", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestMatchFirstClosest(t *testing.T) {
+	doc := parsePage(t)
+	first := MustCompile("li").MatchFirst(doc)
+	if Attr(first, "id") != "li1" {
+		t.Fatalf("MatchFirst = %v, want li1", first)
+	}
+	if n := MustCompile("div").Closest(first); Attr(n, "id") != "main" {
+		t.Errorf("Closest = %v, want main", n)
+	}
+	if n := MustCompile("aside").Closest(first); n != nil {
+		t.Errorf("Closest outside the ancestors = %v", n)
+	}
+	if MustCompile("table").MatchFirst(doc) != nil {
+		t.Error("MatchFirst found a missing element")
+	}
+	if MustCompile("*").Match(first.FirstChild) {
+		t.Error("matched a text node")
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []string{
+		"",
+		" ",
+		",p",
+		"p,",
+		"p >",
+		"> p",
+		"p + + a",
+		"#",
+		".",
+		"p.",
+		"[",
+		"[]",
+		"[href",
+		"[href=x",
+		"[href==x]",
+		"[href!=x]",
+		`[href="x]`,
+		"[href=x y]",
+		"p:",
+		"p:hover",
+		"p::before",
+		"li:nth-child",
+		"li:nth-child(",
+		"li:nth-child()",
+		"li:nth-child(foo)",
+		"li:nth-child(2n+)",
+		"li:nth-child(xn+1)",
+		"p:not",
+		"p:not()",
+		"p:not(.a",
+		"p)",
+		"a{}",
+	}
+	for _, selector := range tests {
+		if s, err := Compile(selector); err == nil {
+			t.Errorf("Compile(%q) = %v, want an error", selector, s.groups)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustCompile of an invalid selector did not panic")
+		}
+	}()
+	MustCompile("p:hover")
+}
+
+func TestText(t *testing.T) {
+	doc, _ := html.Parse(strings.NewReader(`<div>An <b>un</b>usual day.<p>Next<br>line</p><script>x()</script><ul><li>a</li><li>b</li></ul></div>`))
+	if got, want := Text(doc), "An unusual day. Next line a b"; got != want {
+		t.Errorf("Text = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,230 @@
+// Package dedup canonicalises article URLs and merges articles that point at
+// the same story, so a wire piece syndicated by several outlets, or the same
+// link shared with tracking parameters, an AMP variant or a redirect wrapper,
+// ends up as one Article that records every source that carried it.
+package dedup
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// trackingParams are dropped from query strings. Parameters starting with one
+// of trackingPrefixes are dropped as well. Only keys that are tracking on
+// every site belong here: generic names such as ref, src or amp select the
+// content on some sites, and dropping them would merge different pages.
+var (
+	trackingParams = map[string]bool{
+		"fbclid": true, "gclid": true, "dclid": true, "gclsrc": true, "msclkid": true, "yclid": true,
+		"igshid": true, "_hsenc": true, "_hsmi": true, "mkt_tok": true, "ref_src": true, "ref_url": true,
+		"cmpid": true, "ocid": true, "smid": true, "smtyp": true, "sr_share": true, "ito": true,
+		"ns_mchannel": true, "ns_source": true, "ns_campaign": true, "ns_linkname": true, "ns_fee": true,
+		"__twitter_impression": true, "guccounter": true, "guce_referrer": true, "guce_referrer_sig": true,
+		"spm": true, "s_cid": true,
+	}
+	trackingPrefixes = []string{"utm_", "mc_", "pk_", "mtm_", "_ga", "oly_"}
+)
+
+// redirectParams names the parameter holding the target URL of known redirect
+// wrappers, by host.
+var redirectParams = map[string][]string{
+	"google.com":      {"q", "url"},
+	"l.facebook.com":  {"u"},
+	"lm.facebook.com": {"u"},
+	"out.reddit.com":  {"url"},
+	"href.li":         {""},
+	"l.messenger.com": {"u"},
+	"t.umblr.com":     {"z"},
+	"away.vk.com":     {"to"},
+}
+
+// Canonicalize returns the canonical form of an article URL:
+//   - redirect wrappers (google.com/url, l.facebook.com, ...) are unwrapped
+//   - AMP variants (AMP cache and Google AMP viewer URLs, amp. hosts, /amp
+//     path segments, .amp.html suffixes) become the regular page
+//   - scheme and host are lower-cased, default ports and fragments dropped
+//   - tracking parameters (utm_*, fbclid, gclid, ...) are removed and the
+//     remaining parameters sorted
+//   - a trailing slash is removed from non-root paths
+//
+// URLs that do not parse as absolute http(s) URLs are returned trimmed but
+// otherwise unchanged.
+func Canonicalize(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return raw
+	}
+
+	for i := 0; i < 3; i++ {
+		target := unwrap(u)
+		if target == nil {
+			break
+		}
+		u = target
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if port == "80" && u.Scheme == "http" || port == "443" && u.Scheme == "https" {
+		port = ""
+	}
+	host = strings.TrimPrefix(strings.TrimSuffix(host, "."), "amp.")
+	if port != "" {
+		u.Host = host + ":" + port
+	} else {
+		u.Host = host
+	}
+
+	u.Path = cleanPath(u.Path)
+	u.RawPath = ""
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.User = nil
+	u.RawQuery = cleanQuery(u.Query())
+	u.ForceQuery = false
+	return u.String()
+}
+
+// Key is the comparison key of a URL: its canonical form without scheme and
+// without a leading "www.", so http/https and www/non-www variants match.
+func Key(raw string) string {
+	c := Canonicalize(raw)
+	u, err := url.Parse(c)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(c)
+	}
+	host := strings.TrimPrefix(u.Host, "www.")
+	key := host + u.EscapedPath()
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key
+}
+
+// ID returns the stable article ID for a URL: the hex SHA-1 of its Key.
+func ID(raw string) string {
+	sum := sha1.Sum([]byte(Key(raw)))
+	return hex.EncodeToString(sum[:])
+}
+
+// unwrap returns the target of a redirect wrapper or AMP viewer URL, or nil.
+func unwrap(u *url.URL) *url.URL {
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	// AMP cache: https://example-com.cdn.ampproject.org/c/s/example.com/path
+	if strings.HasSuffix(host, ".cdn.ampproject.org") || host == "cdn.ampproject.org" {
+		return ampTarget(u.Path, []string{"/c/s/", "/v/s/", "/i/s/", "/c/", "/v/", "/i/"})
+	}
+	// Google AMP viewer: https://www.google.com/amp/s/example.com/path
+	if (host == "google.com" || strings.HasPrefix(host, "google.")) && strings.HasPrefix(u.Path, "/amp/") {
+		return ampTarget(u.Path, []string{"/amp/s/", "/amp/"})
+	}
+
+	params, ok := redirectParams[host]
+	if !ok {
+		if strings.HasPrefix(host, "google.") && u.Path == "/url" {
+			params = redirectParams["google.com"]
+		} else {
+			return nil
+		}
+	}
+	if host == "google.com" && u.Path != "/url" {
+		return nil
+	}
+
+	if params[0] == "" {
+		// href.li puts the target straight into the query: href.li/?https://...
+		return parseTarget(u.RawQuery)
+	}
+	q := u.Query()
+	for _, p := range params {
+		if t := parseTarget(q.Get(p)); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// ampTarget rebuilds the origin URL from an AMP cache or viewer path.
+func ampTarget(p string, prefixes []string) *url.URL {
+	for _, prefix := range prefixes {
+		if rest, ok := strings.CutPrefix(p, prefix); ok && rest != "" {
+			scheme := "https://"
+			if !strings.Contains(prefix, "/s/") {
+				scheme = "http://"
+			}
+			return parseTarget(scheme + rest)
+		}
+	}
+	return nil
+}
+
+func parseTarget(s string) *url.URL {
+	if s == "" {
+		return nil
+	}
+	t, err := url.Parse(s)
+	if err != nil || t.Host == "" || (t.Scheme != "http" && t.Scheme != "https") {
+		return nil
+	}
+	return t
+}
+
+// cleanPath removes AMP path variants, duplicate slashes and a trailing slash.
+func cleanPath(p string) string {
+	if p == "" || p == "/" {
+		return "/"
+	}
+	segments := strings.Split(p, "/")
+	kept := segments[:0]
+	for _, s := range segments {
+		if strings.EqualFold(s, "amp") {
+			continue
+		}
+		kept = append(kept, s)
+	}
+	p = strings.Join(kept, "/")
+
+	// story.amp.html → story.html, story.amp → story
+	dir, base := path.Split(p)
+	for _, ext := range []string{".html", ".htm", ""} {
+		if stem, ok := strings.CutSuffix(base, ".amp"+ext); ok && stem != "" {
+			p = dir + stem + ext
+			break
+		}
+	}
+
+	p = path.Clean("/" + p)
+	if p == "." {
+		return "/"
+	}
+	return p
+}
+
+// cleanQuery drops tracking parameters; Encode sorts the rest by key.
+func cleanQuery(q url.Values) string {
+	for key := range q {
+		if isTracking(key) {
+			delete(q, key)
+		}
+	}
+	return q.Encode()
+}
+
+func isTracking(key string) bool {
+	if trackingParams[key] || trackingParams[strings.ToLower(key)] {
+		return true
+	}
+	lower := strings.ToLower(key)
+	for _, prefix := range trackingPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package dedup
+
+import "testing"
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://Example.com:443/a/?utm_source=x&UTM_Medium=y&b=2&a=1#top", "https://example.com/a?a=1&b=2"},
+		{"http://example.com:80/", "http://example.com/"},
+		{"https://example.com/a?fbclid=1&gclid=2&mc_cid=3&mc_eid=4&_ga=5&ref_src=twsrc", "https://example.com/a"},
+		// Generic names are content on some sites and stay
+		{"https://example.com/a?ref=main&src=feed&share=1&feed=rss&rss=1&amp=1&outputType=amp&CMP=x",
+			"https://example.com/a?CMP=x&amp=1&feed=rss&outputType=amp&ref=main&rss=1&share=1&src=feed"},
+		{"https://example.com/view?id=7&page=2", "https://example.com/view?id=7&page=2"},
+		{"https://amp.example.com/amp/story.amp.html", "https://example.com/story.html"},
+		{"https://example-com.cdn.ampproject.org/c/s/example.com/story", "https://example.com/story"},
+		{"https://www.google.com/url?q=https://example.com/a%3Futm_source%3Dx", "https://example.com/a"},
+		{"https://l.facebook.com/l.php?u=https%3A%2F%2Fexample.com%2Fb", "https://example.com/b"},
+		{" /relative?utm_source=x ", "/relative?utm_source=x"},
+		{"mailto:a@example.com", "mailto:a@example.com"},
+	}
+	for _, tt := range tests {
+		if got := Canonicalize(tt.in); got != tt.want {
+			t.Errorf("Canonicalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	same := []string{"https://www.example.com/a?utm_campaign=x", "http://example.com/a/", "https://example.com/amp/a"}
+	for _, u := range same[1:] {
+		if Key(u) != Key(same[0]) || ID(u) != ID(same[0]) {
+			t.Errorf("Key(%q) = %q, want %q", u, Key(u), Key(same[0]))
+		}
+	}
+	if Key("https://example.com/a?ref=b") == Key(same[0]) {
+		t.Error("a page with a ref parameter shares the key of the page without it")
+	}
+}
@@ -0,0 +1,161 @@
+package dedup
+
+import (
+	"strings"
+	"time"
+
+	"nous-app/internal/models"
+)
+
+// Normalize canonicalises a's URL and MobileURL, sets its ID to the stable ID
+// of the canonical URL and, if a has no Sources yet, records the source it
+// came from with its original URL and ID.
+func Normalize(a *models.Article) {
+	if len(a.Sources) == 0 {
+		a.Sources = []models.SourceRef{sourceRef(*a)}
+	}
+	if a.URL == "" {
+		return
+	}
+	a.URL = Canonicalize(a.URL)
+	a.ID = ID(a.URL)
+	if a.MobileURL != nil {
+		if m := Canonicalize(*a.MobileURL); m != "" && Key(m) != Key(a.URL) {
+			a.MobileURL = &m
+		} else {
+			a.MobileURL = nil
+		}
+	}
+}
+
+// Merge normalizes every article and merges those that share a canonical URL,
+// or whose MobileURL is another article's URL. The first article seen for a
+// story is kept, in order of first appearance; fields it lacks are filled
+// from its duplicates, PublishedAt becomes the earliest date, categories and
+// tags are combined, and Sources lists every contributing source once.
+// Articles without a URL are passed through unmerged.
+func Merge(articles []models.Article) []models.Article {
+	out := make([]models.Article, 0, len(articles))
+	groups := map[string]int{} // URL key → index in out
+
+	for _, a := range articles {
+		Normalize(&a)
+		if a.URL == "" {
+			out = append(out, a)
+			continue
+		}
+
+		keys := []string{Key(a.URL)}
+		if a.MobileURL != nil {
+			keys = append(keys, Key(*a.MobileURL))
+		}
+
+		i, found := -1, false
+		for _, k := range keys {
+			if i, found = groups[k]; found {
+				break
+			}
+		}
+		if !found {
+			i = len(out)
+			out = append(out, a)
+		} else {
+			mergeInto(&out[i], a)
+		}
+		for _, k := range keys {
+			if _, taken := groups[k]; !taken {
+				groups[k] = i
+			}
+		}
+	}
+	return out
+}
+
+// mergeInto folds dup into primary.
+func mergeInto(primary *models.Article, dup models.Article) {
+	fill := func(dst **string, src *string) {
+		if (*dst == nil || strings.TrimSpace(**dst) == "") && src != nil && strings.TrimSpace(*src) != "" {
+			*dst = src
+		}
+	}
+	fill(&primary.Content, dup.Content)
+	fill(&primary.Summary, dup.Summary)
+	fill(&primary.Image, dup.Image)
+	fill(&primary.Author, dup.Author)
+	fill(&primary.Language, dup.Language)
+	fill(&primary.MobileURL, dup.MobileURL)
+	fill(&primary.SourceCountry, dup.SourceCountry)
+	if t := strings.TrimSpace(primary.Title); (t == "" || t == "Untitled") && dup.Title != "" {
+		primary.Title = dup.Title
+	}
+	if earlier(dup.PublishedAt, primary.PublishedAt) {
+		primary.PublishedAt = dup.PublishedAt
+	}
+
+	primary.Categories = union(primary.Categories, dup.Categories)
+	primary.Tags = union(primary.Tags, dup.Tags)
+
+	for _, ref := range dup.Sources {
+		if !hasSource(primary.Sources, ref) {
+			primary.Sources = append(primary.Sources, ref)
+		}
+	}
+}
+
+func sourceRef(a models.Article) models.SourceRef {
+	ref := models.SourceRef{URL: a.URL, ID: a.ID, PublishedAt: a.PublishedAt}
+	if a.Source != nil {
+		ref.Name = *a.Source
+	}
+	if a.SourceMeta != nil {
+		if ref.Name == "" {
+			ref.Name = a.SourceMeta.Name
+		}
+		ref.Bias = a.SourceMeta.Bias
+	}
+	return ref
+}
+
+func hasSource(refs []models.SourceRef, ref models.SourceRef) bool {
+	for _, r := range refs {
+		if r.Name == ref.Name && Key(r.URL) == Key(ref.URL) {
+			return true
+		}
+	}
+	return false
+}
+
+// earlier reports whether a is a valid date before b (or b is missing).
+func earlier(a, b *string) bool {
+	if a == nil {
+		return false
+	}
+	ta, err := time.Parse(time.RFC3339, *a)
+	if err != nil {
+		return false
+	}
+	if b == nil {
+		return true
+	}
+	tb, err := time.Parse(time.RFC3339, *b)
+	return err != nil || ta.Before(tb)
+}
+
+// union appends the values of b missing from a, case-insensitively. a is
+// capped first so appending never writes into a caller's backing array.
+func union(a, b []string) []string {
+	a = a[:len(a):len(a)]
+	for _, v := range b {
+		dup := false
+		for _, w := range a {
+			if strings.EqualFold(v, w) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			a = append(a, v)
+		}
+	}
+	return a
+}
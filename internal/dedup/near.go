@@ -0,0 +1,324 @@
+package dedup
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"nous-app/internal/models"
+)
+
+// Defaults for NewIndex and Index.Group.
+const (
+	DefaultNearThreshold = 0.9
+	DefaultNearWindow    = 72 * time.Hour
+	DefaultNearEntries   = 20000
+)
+
+// Entry is one fingerprinted article in an Index.
+type Entry struct {
+	ID          string      `json:"id"`
+	Title       string      `json:"title"`
+	URL         string      `json:"url"`
+	Source      string      `json:"source,omitempty"`
+	Fingerprint Fingerprint `json:"fingerprint"`
+	Seen        time.Time   `json:"seen"`
+
+	root string // ID of the entry whose group this one belongs to
+}
+
+// Member is an article in a near-duplicate Group.
+type Member struct {
+	ID         string  `json:"id"`
+	Title      string  `json:"title"`
+	URL        string  `json:"url"`
+	Source     string  `json:"source,omitempty"`
+	Similarity float64 `json:"similarity"` // To the group's first member, 0-1
+	Indexed    bool    `json:"indexed"`    // Seen in an earlier batch rather than this one
+}
+
+// Group is a set of articles whose text is nearly identical. Members[0] is
+// the earliest seen and the one the others are duplicates of.
+type Group struct {
+	ID      string   `json:"id"` // ID of Members[0]
+	Members []Member `json:"members"`
+}
+
+// Index keeps the fingerprints of recently seen articles so copies of a story
+// are recognised across fetches, not only within one batch. Entries older
+// than Window are dropped, as are the oldest entries beyond MaxEntries.
+// Lookups scan every entry, which stays cheap at the sizes a recent window
+// holds and works for any threshold.
+type Index struct {
+	Window     time.Duration
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries []Entry // Oldest first
+}
+
+// NewIndex creates an Index with the default window and size.
+func NewIndex() *Index {
+	return &Index{Window: DefaultNearWindow, MaxEntries: DefaultNearEntries}
+}
+
+// Len returns the number of indexed fingerprints.
+func (x *Index) Len() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return len(x.entries)
+}
+
+// Group fingerprints articles, matches each against the index (including the
+// articles before it in the batch) and adds it. Articles whose similarity to
+// an indexed article is at least threshold join that article's Group; matches
+// chain, so a group can hold two articles only related through a third, and
+// groups persist across calls for as long as their entries stay indexed.
+// Only groups with at least one article from this batch are returned.
+// Articles without an ID or URL, or with too little text, are skipped.
+func (x *Index) Group(articles []models.Article, threshold float64) []Group {
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultNearThreshold
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.prune(time.Now())
+
+	inBatch := map[string]bool{}
+	for _, a := range articles {
+		id := articleKey(a)
+		if id == "" {
+			continue
+		}
+		fp, ok := ArticleFingerprint(a)
+		if !ok {
+			continue
+		}
+
+		e := x.add(entryFor(a, id, fp))
+		if match, found := x.nearest(fp, threshold, id); found {
+			x.join(e.root, match.root)
+		}
+		inBatch[id] = true
+	}
+	x.trim()
+
+	// Entries are oldest first, so each group's members come out in the
+	// order they were seen and Members[0] is the original.
+	byRoot := map[string]*Group{}
+	var roots []string
+	for _, e := range x.entries {
+		g, ok := byRoot[e.root]
+		if !ok {
+			g = &Group{ID: e.ID}
+			byRoot[e.root] = g
+			roots = append(roots, e.root)
+		}
+		g.Members = append(g.Members, Member{
+			ID:      e.ID,
+			Title:   e.Title,
+			URL:     e.URL,
+			Source:  e.Source,
+			Indexed: !inBatch[e.ID],
+		})
+	}
+
+	var groups []Group
+	for _, root := range roots {
+		g := byRoot[root]
+		if len(g.Members) < 2 || !anyFromBatch(g.Members) {
+			continue
+		}
+		original := x.entries[x.find(g.ID)].Fingerprint
+		for i := range g.Members {
+			g.Members[i].Similarity = x.entries[x.find(g.Members[i].ID)].Fingerprint.Similarity(original)
+		}
+		groups = append(groups, *g)
+	}
+	return groups
+}
+
+func anyFromBatch(members []Member) bool {
+	for _, m := range members {
+		if !m.Indexed {
+			return true
+		}
+	}
+	return false
+}
+
+// join merges the group rooted at b into the one rooted at a, or the other
+// way round, keeping the root that was indexed first.
+func (x *Index) join(a, b string) {
+	if a == b {
+		return
+	}
+	if x.find(b) < x.find(a) {
+		a, b = b, a
+	}
+	for i := range x.entries {
+		if x.entries[i].root == b {
+			x.entries[i].root = a
+		}
+	}
+}
+
+// find returns the position of the entry with the given ID, or len(entries).
+func (x *Index) find(id string) int {
+	for i := range x.entries {
+		if x.entries[i].ID == id {
+			return i
+		}
+	}
+	return len(x.entries)
+}
+
+// nearest returns the most similar indexed entry other than exclude, if its
+// similarity reaches threshold. Callers hold x.mu.
+func (x *Index) nearest(fp Fingerprint, threshold float64, exclude string) (Entry, bool) {
+	best, bestSim := -1, threshold
+	for i, e := range x.entries {
+		if e.ID == exclude {
+			continue
+		}
+		if s := e.Fingerprint.Similarity(fp); s >= bestSim {
+			best, bestSim = i, s
+		}
+	}
+	if best < 0 {
+		return Entry{}, false
+	}
+	return x.entries[best], true
+}
+
+// add indexes e and returns the stored entry. An entry with the same ID is
+// updated in place, keeping its position and group.
+func (x *Index) add(e Entry) Entry {
+	for i := range x.entries {
+		if x.entries[i].ID == e.ID {
+			e.root = x.entries[i].root
+			x.entries[i] = e
+			return e
+		}
+	}
+	e.root = e.ID
+	x.entries = append(x.entries, e)
+	return e
+}
+
+func (x *Index) prune(now time.Time) {
+	if x.Window <= 0 {
+		return
+	}
+	cutoff := now.Add(-x.Window)
+	kept := x.entries[:0]
+	for _, e := range x.entries {
+		if e.Seen.After(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	x.entries = kept
+}
+
+func (x *Index) trim() {
+	if x.MaxEntries > 0 && len(x.entries) > x.MaxEntries {
+		x.entries = append(x.entries[:0], x.entries[len(x.entries)-x.MaxEntries:]...)
+	}
+}
+
+func entryFor(a models.Article, id string, fp Fingerprint) Entry {
+	e := Entry{ID: id, Title: a.Title, URL: a.URL, Fingerprint: fp, Seen: time.Now()}
+	if ref := sourceRef(a); ref.Name != "" {
+		e.Source = ref.Name
+	}
+	return e
+}
+
+// articleKey is the ID an article is indexed under: its ID, or the stable ID
+// of its URL.
+func articleKey(a models.Article) string {
+	if id := strings.TrimSpace(a.ID); id != "" {
+		return id
+	}
+	if a.URL != "" {
+		return ID(a.URL)
+	}
+	return ""
+}
+
+// Flag sets DuplicateOf on every article that is a near duplicate of an
+// earlier group member.
+func Flag(articles []models.Article, groups []Group) {
+	for i := range articles {
+		id := articleKey(articles[i])
+		for _, g := range groups {
+			if id != g.ID && containsMember(g, id) {
+				original := g.ID
+				articles[i].DuplicateOf = &original
+				break
+			}
+		}
+	}
+}
+
+// MergeGroups folds the near duplicates in articles into one article per
+// group, the way Merge folds exact duplicates: the group's original when it is
+// in the batch, otherwise the first member that is, which is then flagged as
+// DuplicateOf the original seen in an earlier batch.
+func MergeGroups(articles []models.Article, groups []Group) []models.Article {
+	groupOf := map[string]int{}
+	for gi, g := range groups {
+		for _, m := range g.Members {
+			groupOf[m.ID] = gi
+		}
+	}
+
+	survivor := map[int]string{} // group → ID of the article the rest merge into
+	for _, a := range articles {
+		id := articleKey(a)
+		if gi, ok := groupOf[id]; ok {
+			if _, chosen := survivor[gi]; !chosen || id == groups[gi].ID {
+				survivor[gi] = id
+			}
+		}
+	}
+
+	out := make([]models.Article, 0, len(articles))
+	kept := map[int]int{} // group → index in out
+	var dups []models.Article
+	for _, a := range articles {
+		id := articleKey(a)
+		gi, ok := groupOf[id]
+		if !ok {
+			out = append(out, a)
+			continue
+		}
+		if len(a.Sources) == 0 {
+			a.Sources = []models.SourceRef{sourceRef(a)}
+		}
+		if _, done := kept[gi]; done || survivor[gi] != id {
+			dups = append(dups, a)
+			continue
+		}
+		if id != groups[gi].ID {
+			original := groups[gi].ID
+			a.DuplicateOf = &original
+		}
+		kept[gi] = len(out)
+		out = append(out, a)
+	}
+	for _, a := range dups {
+		mergeInto(&out[kept[groupOf[articleKey(a)]]], a)
+	}
+	return out
+}
+
+func containsMember(g Group, id string) bool {
+	for _, m := range g.Members {
+		if m.ID == id {
+			return true
+		}
+	}
+	return false
+}
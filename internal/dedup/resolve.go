@@ -0,0 +1,246 @@
+package dedup
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"nous-app/internal/css"
+	"nous-app/internal/models"
+)
+
+// Defaults for Resolver.
+const (
+	DefaultResolveConcurrency = 6 // Pages ResolveAll fetches at once
+	DefaultResolveTTL         = 24 * time.Hour
+	DefaultResolveEntries     = 5000
+)
+
+// maxHeadBytes is how much of a page Resolve reads looking for the canonical
+// link, which sits in <head>.
+const maxHeadBytes = 256 << 10
+
+var (
+	canonicalLink = css.MustCompile(`link[rel~="canonical" i][href]`)
+	ogURL         = css.MustCompile(`meta[property="og:url"][content]`)
+)
+
+// Resolver finds the canonical URL of a page over the network: it follows
+// redirects (shorteners, feed proxies) and reads the page's rel=canonical
+// link, falling back to og:url. Results are cached per input URL for TTL;
+// beyond MaxEntries the least recently used are dropped.
+type Resolver struct {
+	HTTPClient  *http.Client      // Defaults to http.DefaultClient
+	Headers     map[string]string // Sent with every request, e.g. User-Agent
+	Concurrency int               // Pages fetched at once by ResolveAll
+	TTL         time.Duration     // How long a result is cached, defaults to DefaultResolveTTL
+	MaxEntries  int               // Cached results, defaults to DefaultResolveEntries
+
+	mu    sync.Mutex
+	cache map[string]*list.Element // Input URL → element of lru
+	lru   list.List                // *resolved, most recently used first
+}
+
+// resolved is a cached Resolve result.
+type resolved struct {
+	url       string
+	canonical string
+	expires   time.Time
+}
+
+// NewResolver creates a Resolver with an empty cache.
+func NewResolver() *Resolver {
+	return &Resolver{TTL: DefaultResolveTTL, MaxEntries: DefaultResolveEntries}
+}
+
+// Resolve returns the canonical URL of rawURL. On failure it returns the
+// offline Canonicalize form along with the error.
+func (r *Resolver) Resolve(ctx context.Context, rawURL string) (string, error) {
+	fallback := Canonicalize(rawURL)
+	if c, ok := r.cached(fallback); ok {
+		return c, nil
+	}
+
+	canonical, err := r.fetchCanonical(ctx, fallback)
+	if err != nil {
+		return fallback, err
+	}
+	r.remember(fallback, canonical)
+	return canonical, nil
+}
+
+// cached returns the unexpired result for pageURL.
+func (r *Resolver) cached(pageURL string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	el, ok := r.cache[pageURL]
+	if !ok {
+		return "", false
+	}
+	entry := el.Value.(*resolved)
+	if time.Now().After(entry.expires) {
+		r.lru.Remove(el)
+		delete(r.cache, pageURL)
+		return "", false
+	}
+	r.lru.MoveToFront(el)
+	return entry.canonical, true
+}
+
+// remember caches a result, dropping the least recently used beyond
+// MaxEntries.
+func (r *Resolver) remember(pageURL, canonical string) {
+	ttl := r.TTL
+	if ttl <= 0 {
+		ttl = DefaultResolveTTL
+	}
+	limit := r.MaxEntries
+	if limit <= 0 {
+		limit = DefaultResolveEntries
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cache == nil {
+		r.cache = make(map[string]*list.Element)
+	}
+
+	entry := &resolved{url: pageURL, canonical: canonical, expires: time.Now().Add(ttl)}
+	if el, ok := r.cache[pageURL]; ok {
+		el.Value = entry
+		r.lru.MoveToFront(el)
+	} else {
+		r.cache[pageURL] = r.lru.PushFront(entry)
+	}
+	for r.lru.Len() > limit {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.cache, oldest.Value.(*resolved).url)
+	}
+}
+
+func (r *Resolver) fetchCanonical(ctx context.Context, pageURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("dedup: failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.5")
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("dedup: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("dedup: HTTP %d for %s", resp.StatusCode, pageURL)
+	}
+
+	final := pageURL
+	if resp.Request != nil && resp.Request.URL != nil {
+		final = resp.Request.URL.String()
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		return Canonicalize(final), nil
+	}
+
+	doc, err := html.Parse(io.LimitReader(resp.Body, maxHeadBytes))
+	if err != nil {
+		return Canonicalize(final), nil
+	}
+	return Canonicalize(pickCanonical(doc, final)), nil
+}
+
+// pickCanonical returns the page's declared canonical URL resolved against
+// final, or final itself. A canonical pointing at the site root from an
+// article page is a common misconfiguration and is ignored.
+func pickCanonical(doc *html.Node, final string) string {
+	base, err := url.Parse(final)
+	if err != nil {
+		return final
+	}
+	for _, candidate := range []string{
+		attrOf(canonicalLink.MatchFirst(doc), "href"),
+		attrOf(ogURL.MatchFirst(doc), "content"),
+	} {
+		if candidate == "" {
+			continue
+		}
+		ref, err := url.Parse(candidate)
+		if err != nil {
+			continue
+		}
+		c := base.ResolveReference(ref)
+		if c.Scheme != "http" && c.Scheme != "https" {
+			continue
+		}
+		if (c.Path == "" || c.Path == "/") && base.Path != "" && base.Path != "/" {
+			continue
+		}
+		return c.String()
+	}
+	return final
+}
+
+func attrOf(n *html.Node, name string) string {
+	if n == nil {
+		return ""
+	}
+	return strings.TrimSpace(css.Attr(n, name))
+}
+
+// ResolveAll replaces the URL of each article with its resolved canonical URL,
+// keeping the original as a source reference so Merge still records it.
+// Articles that fail to resolve keep their URL. It returns once every article
+// is done or ctx ends.
+func (r *Resolver) ResolveAll(ctx context.Context, articles []models.Article) {
+	workers := r.Concurrency
+	if workers <= 0 {
+		workers = DefaultResolveConcurrency
+	}
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := range articles {
+		if articles[i].URL == "" {
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func(a *models.Article) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			canonical, err := r.Resolve(ctx, a.URL)
+			if err != nil || canonical == "" {
+				return
+			}
+			if len(a.Sources) == 0 {
+				a.Sources = []models.SourceRef{sourceRef(*a)}
+			}
+			a.URL = canonical
+		}(&articles[i])
+	}
+	wg.Wait()
+}
@@ -0,0 +1,123 @@
+package dedup
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+
+	"nous-app/internal/models"
+)
+
+// ShingleSize is the number of consecutive words hashed together into one
+// SimHash feature. Word trigrams keep the fingerprint sensitive to word order
+// while tolerating small edits.
+const ShingleSize = 3
+
+// MinFingerprintWords is the fewest words an article needs before it is
+// fingerprinted. SimHash over a headline alone is too noisy to compare.
+const MinFingerprintWords = 12
+
+// Fingerprint is a 64-bit SimHash. Texts that share most of their shingles
+// have fingerprints that differ in few bits.
+type Fingerprint uint64
+
+// Distance is the Hamming distance between two fingerprints.
+func (f Fingerprint) Distance(g Fingerprint) int {
+	return bits.OnesCount64(uint64(f ^ g))
+}
+
+// Similarity is 1 minus the fraction of differing bits: 1 for identical
+// fingerprints, around 0.5 for unrelated texts.
+func (f Fingerprint) Similarity(g Fingerprint) float64 {
+	return 1 - float64(f.Distance(g))/64
+}
+
+// SimHash fingerprints text. It returns false when the text has fewer than
+// MinFingerprintWords words.
+func SimHash(text string) (Fingerprint, bool) {
+	words := tokenize(text)
+	if len(words) < MinFingerprintWords {
+		return 0, false
+	}
+
+	var weights [64]int
+	h := fnv.New64a()
+	for i := 0; i+ShingleSize <= len(words); i++ {
+		h.Reset()
+		for j, w := range words[i : i+ShingleSize] {
+			if j > 0 {
+				h.Write([]byte{' '})
+			}
+			h.Write([]byte(w))
+		}
+		sum := h.Sum64()
+		for b := 0; b < 64; b++ {
+			if sum&(1<<b) != 0 {
+				weights[b]++
+			} else {
+				weights[b]--
+			}
+		}
+	}
+
+	var fp Fingerprint
+	for b, w := range weights {
+		if w > 0 {
+			fp |= 1 << b
+		}
+	}
+	return fp, true
+}
+
+// ArticleFingerprint fingerprints an article's Title and Content, falling back
+// to Summary when there is no content.
+func ArticleFingerprint(a models.Article) (Fingerprint, bool) {
+	body := ""
+	if a.Content != nil && strings.TrimSpace(*a.Content) != "" {
+		body = *a.Content
+	} else if a.Summary != nil {
+		body = *a.Summary
+	}
+	return SimHash(a.Title + "\n" + body)
+}
+
+// tokenize lower-cases text and splits it into words, skipping HTML tags and
+// entities so markup in Content does not count as text.
+func tokenize(text string) []string {
+	var words []string
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			words = append(words, b.String())
+			b.Reset()
+		}
+	}
+
+	inTag, inEntity := false, false
+	for _, r := range text {
+		switch {
+		case inTag:
+			inTag = r != '>'
+			continue
+		case r == '<':
+			flush()
+			inTag = true
+			continue
+		case inEntity:
+			inEntity = r != ';' && !unicode.IsSpace(r)
+			continue
+		case r == '&':
+			flush()
+			inEntity = true
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		} else if r != '\'' && r != '’' {
+			flush()
+		}
+	}
+	flush()
+	return words
+}
@@ -0,0 +1,199 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nous-app/internal/persist"
+)
+
+// CacheEntry holds the HTTP caching state of one source.
+type CacheEntry struct {
+	Endpoint     string    `json:"endpoint"`               // Endpoint the validators belong to
+	ETag         string    `json:"etag,omitempty"`         // Sent back as If-None-Match
+	LastModified string    `json:"lastModified,omitempty"` // Sent back as If-Modified-Since
+	FreshUntil   time.Time `json:"freshUntil,omitempty"`   // From Cache-Control max-age; no request before this
+	RetryAt      time.Time `json:"retryAt,omitempty"`      // From Retry-After; no request before this
+}
+
+// Cache stores CacheEntry values per source name and persists them to a JSON
+// file, so validators survive restarts. A nil *Cache disables caching.
+type Cache struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]CacheEntry
+	dirty   bool
+}
+
+// NewCache creates a cache persisted at path, loading any existing entries.
+// A missing or unreadable file starts an empty cache.
+func NewCache(path string) (*Cache, error) {
+	c := &Cache{path: path, entries: make(map[string]CacheEntry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to read fetch cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		c.entries = make(map[string]CacheEntry)
+		return c, fmt.Errorf("failed to parse fetch cache: %w", err)
+	}
+	return c, nil
+}
+
+// Get returns the entry for a source, ignoring entries recorded for a
+// different endpoint.
+func (c *Cache) Get(name, endpoint string) (CacheEntry, bool) {
+	if c == nil {
+		return CacheEntry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[name]
+	if !ok || e.Endpoint != endpoint {
+		return CacheEntry{}, false
+	}
+	return e, true
+}
+
+// Set records the entry for a source.
+func (c *Cache) Set(name string, e CacheEntry) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[name] = e
+	c.dirty = true
+}
+
+// Forget drops the entry of a source, so its next fetch is unconditional.
+func (c *Cache) Forget(name string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[name]; ok {
+		delete(c.entries, name)
+		c.dirty = true
+	}
+}
+
+// Flush writes the cache to disk if it changed since the last flush.
+func (c *Cache) Flush() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := persist.WriteFile(c.path, data, 0644); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// ----------------------
+// Header handling
+// ----------------------
+
+// applyValidators adds conditional request headers from e.
+func applyValidators(req *http.Request, e CacheEntry) {
+	if e.ETag != "" {
+		req.Header.Set("If-None-Match", e.ETag)
+	}
+	if e.LastModified != "" {
+		req.Header.Set("If-Modified-Since", e.LastModified)
+	}
+}
+
+// updateEntry folds the caching headers of resp into e. Validators are only
+// replaced by a full response; a 304 keeps the ones that matched.
+func updateEntry(e CacheEntry, resp *http.Response, now time.Time) CacheEntry {
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		e.ETag = resp.Header.Get("ETag")
+		e.LastModified = resp.Header.Get("Last-Modified")
+	}
+
+	e.FreshUntil = time.Time{}
+	if maxAge, ok := parseMaxAge(resp.Header.Get("Cache-Control")); ok && maxAge > 0 {
+		e.FreshUntil = now.Add(maxAge)
+	}
+
+	e.RetryAt = time.Time{}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			e.RetryAt = now.Add(d)
+		}
+	}
+	return e
+}
+
+// parseMaxAge returns the max-age directive of a Cache-Control header.
+// no-store and no-cache disable freshness.
+func parseMaxAge(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	var maxAge time.Duration
+	found := false
+	for _, directive := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store", "no-cache":
+			return 0, false
+		case "max-age":
+			secs, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err != nil || secs < 0 {
+				return 0, false
+			}
+			maxAge = time.Duration(secs) * time.Second
+			found = true
+		}
+	}
+	return maxAge, found
+}
+
+// parseRetryAfter parses a Retry-After header given either as seconds or as
+// an HTTP date.
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,299 @@
+// Package fetcher downloads the raw payload of each configured Source from Go,
+// so fetching can be scheduled and observed without going through the P2P node.
+//
+// A Fetcher honours Source.Endpoint, Headers, AuthType, APIKey and
+// RateLimitPerMin, and fetches several sources at once with a bounded pool of
+// workers. Parsing the payload into articles is left to the caller. Adapters
+// that need several requests per source go through Get.
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"nous-app/internal/models"
+)
+
+// Defaults used when the corresponding Fetcher field is zero.
+const (
+	DefaultWorkers      = 4
+	DefaultTimeout      = 30 * time.Second
+	DefaultMaxBodyBytes = 10 << 20 // 10 MB
+	DefaultUserAgent    = "NousClient/1.0 (P2P; OrbitDB; Helia)"
+	DefaultAccept       = "application/json, application/feed+json, application/rss+xml, application/atom+xml, application/xml;q=0.9, text/html;q=0.8, */*;q=0.5"
+)
+
+// Fetcher fetches sources concurrently. It is safe for concurrent use and
+// keeps per-source rate limit state between calls, so a single Fetcher should
+// be shared by everything that fetches sources.
+type Fetcher struct {
+	HTTPClient   *http.Client  // Defaults to http.DefaultClient
+	Workers      int           // Maximum concurrent fetches, defaults to DefaultWorkers
+	Timeout      time.Duration // Per-source timeout, defaults to DefaultTimeout
+	MaxBodyBytes int64         // Bodies larger than this are rejected, defaults to DefaultMaxBodyBytes
+	Cache        *Cache        // ETag/Last-Modified validators and backoff per source; nil disables caching
+
+	mu       sync.Mutex
+	limiters map[string]*limiter // keyed by source name
+}
+
+// New creates a Fetcher with the given worker pool size (0 for the default).
+func New(workers int) *Fetcher {
+	return &Fetcher{Workers: workers}
+}
+
+// Result is the outcome of fetching a single source.
+type Result struct {
+	Source models.Source
+	Body   []byte // Raw payload, nil on error or when not modified
+	Meta   models.FetchMeta
+}
+
+// Fetch fetches every enabled source on the worker pool and streams the
+// results as they complete. The channel is closed once all sources are done
+// or ctx ends, after the cache has been flushed to disk.
+func (f *Fetcher) Fetch(ctx context.Context, sources []models.Source) <-chan Result {
+	jobs := make(chan models.Source)
+	results := make(chan Result)
+
+	workers := f.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for src := range jobs {
+				r := f.FetchOne(ctx, src)
+				select {
+				case results <- r:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, src := range sources {
+			if src.Enabled != nil && !*src.Enabled {
+				continue
+			}
+			select {
+			case jobs <- src:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		if err := f.Cache.Flush(); err != nil {
+			log.Println("[Fetch] Failed to save fetch cache:", err)
+		}
+		close(results)
+	}()
+
+	return results
+}
+
+// FetchOne fetches a single source, waiting for its rate limit first.
+//
+// With a Cache, the request is conditional on the stored ETag/Last-Modified
+// and a 304 answer is reported as NotModified with no body. Sources still
+// fresh per Cache-Control max-age are not requested at all, and sources that
+// sent Retry-After are skipped with an error until the given time. FetchOne
+// does not flush the cache; Fetch does once all sources are done.
+func (f *Fetcher) FetchOne(ctx context.Context, src models.Source) Result {
+	start := time.Now()
+	r := Result{Source: src}
+
+	fail := func(status int, err error) Result {
+		r.Meta.StatusCode = status
+		r.Meta.Error = err.Error()
+		r.Meta.DurationMs = time.Since(start).Milliseconds()
+		r.Meta.FetchedAt = start.UTC().Format(time.RFC3339)
+		log.Printf("[Fetch] %s: %v\n", src.Name, err)
+		return r
+	}
+
+	if src.Endpoint == "" {
+		return fail(0, fmt.Errorf("source has no endpoint"))
+	}
+
+	entry, cached := f.Cache.Get(src.Name, src.Endpoint)
+	if cached && start.Before(entry.RetryAt) {
+		r.Meta.RetryAt = entry.RetryAt.UTC().Format(time.RFC3339)
+		return fail(0, fmt.Errorf("source asked to retry after %s", r.Meta.RetryAt))
+	}
+	if cached && start.Before(entry.FreshUntil) {
+		r.Meta = models.FetchMeta{
+			NotModified: true,
+			FetchedAt:   start.UTC().Format(time.RFC3339),
+		}
+		return r
+	}
+	entry.Endpoint = src.Endpoint
+
+	if err := f.limiter(src).wait(ctx); err != nil {
+		return fail(0, err)
+	}
+
+	timeout := f.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := newRequest(ctx, src)
+	if err != nil {
+		return fail(0, err)
+	}
+	applyValidators(req, entry)
+
+	log.Printf("[Fetch] GET %s (%s)\n", redactURL(req.URL), src.Name)
+
+	resp, err := f.httpClient().Do(req)
+	if err != nil {
+		// The URL in the error may carry the API key
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactURL(req.URL)
+		}
+		return fail(0, fmt.Errorf("request failed: %w", err))
+	}
+	defer resp.Body.Close()
+
+	entry = updateEntry(entry, resp, time.Now())
+	f.Cache.Set(src.Name, entry)
+
+	if resp.StatusCode == http.StatusNotModified {
+		r.Meta = models.FetchMeta{
+			StatusCode:  resp.StatusCode,
+			DurationMs:  time.Since(start).Milliseconds(),
+			FetchedAt:   start.UTC().Format(time.RFC3339),
+			NotModified: true,
+		}
+		return r
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		if !entry.RetryAt.IsZero() {
+			r.Meta.RetryAt = entry.RetryAt.UTC().Format(time.RFC3339)
+		}
+		return fail(resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode))
+	}
+
+	limit := f.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return fail(resp.StatusCode, fmt.Errorf("failed to read response: %w", err))
+	}
+	if int64(len(body)) > limit {
+		return fail(resp.StatusCode, fmt.Errorf("response larger than %d bytes", limit))
+	}
+
+	r.Body = body
+	r.Meta = models.FetchMeta{
+		StatusCode:  resp.StatusCode,
+		Bytes:       int64(len(body)),
+		DurationMs:  time.Since(start).Milliseconds(),
+		ContentType: resp.Header.Get("Content-Type"),
+		FetchedAt:   start.UTC().Format(time.RFC3339),
+	}
+	return r
+}
+
+// Get fetches endpoint on behalf of src, for adapters that make several
+// requests per source (hn, reddit): it waits for src's rate limit and sends
+// src's headers and auth like FetchOne, but bypasses the cache. Responses
+// other than 2xx are errors.
+func (f *Fetcher) Get(ctx context.Context, src models.Source, endpoint string) ([]byte, error) {
+	if err := f.limiter(src).wait(ctx); err != nil {
+		return nil, err
+	}
+
+	timeout := f.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	src.Endpoint = endpoint
+	req, err := newRequest(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.httpClient().Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactURL(req.URL)
+		}
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil, fmt.Errorf("GET %s: HTTP %d", redactURL(req.URL), resp.StatusCode)
+	}
+	limit := f.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("response larger than %d bytes", limit)
+	}
+	return body, nil
+}
+
+func (f *Fetcher) httpClient() *http.Client {
+	if f.HTTPClient != nil {
+		return f.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// limiter returns the rate limiter for src, creating or resizing it as needed.
+func (f *Fetcher) limiter(src models.Source) *limiter {
+	perMin := 0
+	if src.RateLimitPerMin != nil {
+		perMin = *src.RateLimitPerMin
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.limiters == nil {
+		f.limiters = make(map[string]*limiter)
+	}
+	l, ok := f.limiters[src.Name]
+	if !ok {
+		l = &limiter{}
+		f.limiters[src.Name] = l
+	}
+	l.setRate(perMin)
+	return l
+}
@@ -0,0 +1,79 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nous-app/internal/models"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestGet(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	f := New(1)
+	src := models.Source{
+		Name:     "API",
+		Endpoint: "https://ignored.example/",
+		AuthType: ptr(AuthBearerToken),
+		APIKey:   ptr("secret"),
+		Headers:  map[string]string{"X-Client": "nous"},
+	}
+	body, err := f.Get(context.Background(), src, srv.URL+"/items/1")
+	if err != nil || string(body) != `{"ok":true}` {
+		t.Fatalf("Get = %q, %v", body, err)
+	}
+	if got.URL.Path != "/items/1" || got.Header.Get("Authorization") != "Bearer secret" || got.Header.Get("X-Client") != "nous" {
+		t.Errorf("request %s with headers %v", got.URL, got.Header)
+	}
+
+	if _, err := f.Get(context.Background(), src, srv.URL+"/missing"); err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("Get of a missing page: %v, want HTTP 404", err)
+	}
+	if _, err := f.Get(context.Background(), models.Source{AuthType: ptr(AuthBasic)}, srv.URL); err == nil {
+		t.Error("Get without the key its auth type needs succeeded")
+	}
+
+	f.MaxBodyBytes = 4
+	if _, err := f.Get(context.Background(), src, srv.URL); err == nil {
+		t.Error("Get of a body over MaxBodyBytes succeeded")
+	}
+}
+
+func TestGetRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	f := New(1)
+	src := models.Source{Name: "Slow", RateLimitPerMin: ptr(600)} // one request per 100ms
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := f.Get(context.Background(), src, srv.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("3 requests at 600/min took %v, want at least 200ms", elapsed)
+	}
+
+	// FetchOne shares the limiter of the source
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if r := f.FetchOne(ctx, models.Source{Name: "Slow", Endpoint: srv.URL, RateLimitPerMin: ptr(600)}); r.Meta.Error == "" {
+		t.Error("FetchOne did not wait for the limiter Get used")
+	}
+}
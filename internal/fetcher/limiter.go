@@ -0,0 +1,56 @@
+package fetcher
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter spaces requests to one source evenly so that no more than perMin
+// requests start in any minute. A zero rate means unlimited.
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // Earliest start time for the next request
+}
+
+func (l *limiter) setRate(perMin int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if perMin <= 0 {
+		l.interval = 0
+		return
+	}
+	l.interval = time.Minute / time.Duration(perMin)
+}
+
+// wait blocks until the next request may start, or ctx ends.
+func (l *limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	if l.interval == 0 {
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,126 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"nous-app/internal/models"
+)
+
+// Supported values of Source.AuthType.
+const (
+	AuthNone        = "none"
+	AuthAPIKey      = "apiKey"
+	AuthBearerToken = "bearerToken"
+	AuthOAuth       = "oauth"
+	AuthBasic       = "basicAuth"
+)
+
+// APIKeyPlaceholder is replaced by the source's API key wherever it appears in
+// the endpoint, e.g. "https://newsapi.org/v2/top-headlines?apiKey=YOUR_KEY".
+const APIKeyPlaceholder = "YOUR_KEY"
+
+// APIKeyHeader carries the API key for "apiKey" sources whose endpoint has no
+// placeholder or key parameter to fill in.
+const APIKeyHeader = "X-Api-Key"
+
+// apiKeyParams are the query parameter names recognised as API key slots.
+var apiKeyParams = []string{"apiKey", "api-key", "api_key", "apikey", "key", "token"}
+
+// newRequest builds the GET request for src, applying its headers and auth.
+func newRequest(ctx context.Context, src models.Source) (*http.Request, error) {
+	endpoint := src.Endpoint
+	apiKey := ""
+	if src.APIKey != nil {
+		apiKey = strings.TrimSpace(*src.APIKey)
+	}
+
+	authType := AuthNone
+	if src.AuthType != nil && *src.AuthType != "" {
+		authType = *src.AuthType
+	}
+	if authType != AuthNone && apiKey == "" {
+		return nil, fmt.Errorf("auth type %q requires an API key", authType)
+	}
+
+	keyInURL := false
+	if authType == AuthAPIKey {
+		endpoint, keyInURL = injectAPIKey(endpoint, apiKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported endpoint scheme %q", req.URL.Scheme)
+	}
+
+	req.Header.Set("User-Agent", DefaultUserAgent)
+	req.Header.Set("Accept", DefaultAccept)
+
+	switch authType {
+	case AuthNone:
+	case AuthAPIKey:
+		if !keyInURL {
+			req.Header.Set(APIKeyHeader, apiKey)
+		}
+	case AuthBearerToken, AuthOAuth:
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	case AuthBasic:
+		user, pass, _ := strings.Cut(apiKey, ":")
+		req.SetBasicAuth(user, pass)
+	default:
+		return nil, fmt.Errorf("unsupported auth type %q", authType)
+	}
+
+	// Source headers win over the defaults, including User-Agent and auth.
+	for k, v := range src.Headers {
+		req.Header.Set(k, v)
+	}
+
+	return req, nil
+}
+
+// injectAPIKey puts key into endpoint, either in place of APIKeyPlaceholder or
+// into an empty API key query parameter. It reports whether the key was placed.
+func injectAPIKey(endpoint, key string) (string, bool) {
+	if strings.Contains(endpoint, APIKeyPlaceholder) {
+		return strings.ReplaceAll(endpoint, APIKeyPlaceholder, url.QueryEscape(key)), true
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint, false
+	}
+	query := u.Query()
+	for _, name := range apiKeyParams {
+		if vals, ok := query[name]; ok && (len(vals) == 0 || vals[0] == "") {
+			query.Set(name, key)
+			u.RawQuery = query.Encode()
+			return u.String(), true
+		}
+	}
+	return endpoint, false
+}
+
+// redactURL formats u for logging with API key parameters masked.
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	redacted := false
+	for _, name := range apiKeyParams {
+		if query.Has(name) {
+			query.Set(name, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.String()
+	}
+	c := *u
+	c.RawQuery = query.Encode()
+	return c.String()
+}
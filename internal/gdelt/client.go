@@ -0,0 +1,309 @@
+package gdelt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"nous-app/internal/models"
+)
+
+// DefaultMinInterval is the pause between page requests. GDELT asks clients
+// to send no more than one request every five seconds.
+const DefaultMinInterval = 5 * time.Second
+
+// Client queries the DOC 2.0 API.
+type Client struct {
+	BaseURL     string        // Defaults to DefaultBaseURL; point at a fixture server in tests
+	HTTPClient  *http.Client  // Defaults to http.DefaultClient
+	UserAgent   string        // Optional User-Agent header
+	MinInterval time.Duration // Pause between pages, defaults to DefaultMinInterval; negative disables
+}
+
+// New creates a client for the public API.
+func New() *Client {
+	return &Client{}
+}
+
+// ArtListItem is one article in an artlist response.
+type ArtListItem struct {
+	URL           string `json:"url"`
+	URLMobile     string `json:"url_mobile"`
+	Title         string `json:"title"`
+	SeenDate      string `json:"seendate"` // e.g. "20240102T150405Z"
+	SocialImage   string `json:"socialimage"`
+	Domain        string `json:"domain"`
+	Language      string `json:"language"`      // Language name, e.g. "English"
+	SourceCountry string `json:"sourcecountry"` // Country name, e.g. "United States"
+}
+
+// Seen returns the time GDELT first saw the article, or the zero time.
+func (it ArtListItem) Seen() time.Time {
+	t, err := time.Parse("20060102T150405Z", it.SeenDate)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// ParseArtList decodes an artlist JSON response. GDELT answers invalid
+// queries with a plain-text message and status 200, which is returned as an
+// error; an empty result set is "{}" and yields no items.
+func ParseArtList(data []byte) ([]ArtListItem, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+	if trimmed[0] != '{' {
+		msg := string(trimmed)
+		if len(msg) > 200 {
+			msg = msg[:200]
+		}
+		return nil, fmt.Errorf("gdelt: %s", msg)
+	}
+
+	var res struct {
+		Articles []ArtListItem `json:"articles"`
+	}
+	if err := json.Unmarshal(trimmed, &res); err != nil {
+		return nil, fmt.Errorf("gdelt: invalid artlist response: %w", err)
+	}
+	return res.Articles, nil
+}
+
+// ArtList fetches a single page of results for q.
+func (c *Client) ArtList(ctx context.Context, q Query) ([]ArtListItem, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	endpoint := q.URL(c.BaseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gdelt: failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	log.Printf("[GDELT] GET %s\n", endpoint)
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gdelt: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 20<<20))
+	if err != nil {
+		return nil, fmt.Errorf("gdelt: failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gdelt: HTTP %d", resp.StatusCode)
+	}
+	return ParseArtList(body)
+}
+
+// Search pages backwards through the query window, newest first, fetching up
+// to maxPages pages. The DOC API has no page parameter, so each following
+// page ends where the oldest article of the previous one was seen. Results
+// are de-duplicated by URL.
+func (c *Client) Search(ctx context.Context, q Query, maxPages int) ([]ArtListItem, error) {
+	if maxPages <= 0 {
+		maxPages = 1
+	}
+
+	// A relative timespan cannot be combined with a moving end, so pin it to
+	// an absolute window first.
+	if q.Start.IsZero() && q.Timespan != "" && maxPages > 1 {
+		if span, ok := parseTimespan(q.Timespan); ok {
+			if q.End.IsZero() {
+				q.End = time.Now().UTC()
+			}
+			q.Start = q.End.Add(-span)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var all []ArtListItem
+
+	for page := 0; page < maxPages; page++ {
+		if page > 0 && !c.pause(ctx) {
+			return all, ctx.Err()
+		}
+
+		items, err := c.ArtList(ctx, q)
+		if err != nil {
+			if len(all) > 0 {
+				log.Printf("[GDELT] Stopping after %d page(s): %v\n", page, err)
+				return all, nil
+			}
+			return nil, err
+		}
+
+		var oldest time.Time
+		for _, it := range items {
+			if !seen[it.URL] {
+				seen[it.URL] = true
+				all = append(all, it)
+			}
+			if t := it.Seen(); !t.IsZero() && (oldest.IsZero() || t.Before(oldest)) {
+				oldest = t
+			}
+		}
+
+		// A short page means the window is exhausted
+		if len(items) < q.pageSize() || oldest.IsZero() || q.Start.IsZero() {
+			break
+		}
+		// Step back at least a second so a page full of identical timestamps can't repeat forever
+		if !q.End.IsZero() && !oldest.Before(q.End) {
+			oldest = q.End.Add(-time.Second)
+		}
+		if !oldest.After(q.Start) {
+			break
+		}
+		q.End = oldest
+	}
+	return all, nil
+}
+
+// pause waits MinInterval between requests; it reports false if ctx ended.
+func (c *Client) pause(ctx context.Context) bool {
+	d := c.MinInterval
+	if d == 0 {
+		d = DefaultMinInterval
+	}
+	if d < 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+var timespanPattern = regexp.MustCompile(`^(\d+)\s*(min|h|d|w|m)?$`)
+
+// parseTimespan parses GDELT's timespan syntax: 15min, 24h, 7d, 2w or 3m
+// (months). A bare number is minutes.
+func parseTimespan(s string) (time.Duration, bool) {
+	m := timespanPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	unit := map[string]time.Duration{
+		"": time.Minute, "min": time.Minute, "h": time.Hour,
+		"d": 24 * time.Hour, "w": 7 * 24 * time.Hour, "m": 30 * 24 * time.Hour,
+	}[m[2]]
+	return time.Duration(n) * unit, true
+}
+
+// ----------------------
+// Article mapping
+// ----------------------
+
+// ToArticles maps artlist items onto Articles for src. seendate becomes
+// PublishedAt, domain SourceDomain, sourcecountry SourceCountry (ISO code when
+// known) and language Language (ISO 639-1 when known).
+func ToArticles(items []ArtListItem, src models.Source) []models.Article {
+	sourceType := "gdelt"
+	articles := make([]models.Article, 0, len(items))
+
+	for _, it := range items {
+		if it.URL == "" {
+			continue
+		}
+		item := it
+
+		title := strings.TrimSpace(it.Title)
+		if title == "" {
+			title = "Untitled"
+		}
+
+		a := models.Article{
+			ID:         it.URL,
+			Title:      title,
+			URL:        it.URL,
+			Parser:     src.Parser,
+			Normalizer: src.Normalizer,
+			Confidence: src.Confidence,
+			Raw:        &item,
+			SourceType: &sourceType,
+			SourceMeta: &models.SourceMeta{
+				Name:       src.Name,
+				Bias:       src.Bias,
+				Confidence: src.Confidence,
+			},
+		}
+		if src.Name != "" {
+			name := src.Name
+			a.Source = &name
+		}
+		if v := strings.TrimSpace(it.URLMobile); v != "" {
+			a.MobileURL = &v
+		}
+		if v := strings.TrimSpace(it.SocialImage); v != "" {
+			a.Image = &v
+		}
+		if t := it.Seen(); !t.IsZero() {
+			iso := t.Format(time.RFC3339)
+			a.PublishedAt = &iso
+		}
+		if v := strings.TrimSpace(it.Domain); v != "" {
+			a.SourceDomain = &v
+		}
+		if v := languageCode(it.Language); v != "" {
+			a.Language = &v
+		} else {
+			a.Language = src.Language
+		}
+
+		edition := models.EditionInternational
+		if v := countryCode(it.SourceCountry); v != "" {
+			a.SourceCountry = &v
+			edition = editionFor(v)
+		}
+		a.Edition = &edition
+
+		articles = append(articles, a)
+	}
+	return articles
+}
+
+// editionFor maps an ISO country code to an Edition.
+func editionFor(country string) models.Edition {
+	switch strings.ToUpper(country) {
+	case "US":
+		return models.EditionUS
+	case "GB", "UK":
+		return models.EditionUK
+	case "KR":
+		return models.EditionKR
+	case "CN":
+		return models.EditionCN
+	default:
+		return models.EditionOther
+	}
+}
@@ -0,0 +1,204 @@
+package gdelt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nous-app/internal/models"
+)
+
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseArtList(t *testing.T) {
+	items, err := ParseArtList(fixture(t, "artlist_page1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	it := items[0]
+	if it.URL != "https://www.bbc.co.uk/news/science-environment-67890123" || it.Domain != "bbc.co.uk" || it.SourceCountry != "United Kingdom" {
+		t.Errorf("unexpected item: %+v", it)
+	}
+	if want := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC); !it.Seen().Equal(want) {
+		t.Errorf("Seen() = %v, want %v", it.Seen(), want)
+	}
+}
+
+func TestParseArtListEmpty(t *testing.T) {
+	for _, data := range [][]byte{fixture(t, "artlist_empty.json"), nil, []byte("  \n")} {
+		items, err := ParseArtList(data)
+		if err != nil || len(items) != 0 {
+			t.Errorf("ParseArtList(%q) = %v, %v; want no items", data, items, err)
+		}
+	}
+}
+
+func TestParseArtListError(t *testing.T) {
+	_, err := ParseArtList(fixture(t, "artlist_error.txt"))
+	if err == nil || !strings.Contains(err.Error(), "phrase is too short") {
+		t.Fatalf("err = %v, want the API message", err)
+	}
+
+	if _, err := ParseArtList([]byte(`{"articles": [`)); err == nil {
+		t.Error("truncated JSON parsed without error")
+	}
+}
+
+// fixtureServer answers artlist requests with the recorded pages: the first
+// page for the initial window, the second once the window ends where page
+// one did, and an empty result after that.
+func fixtureServer(t *testing.T, requests *[]string) *httptest.Server {
+	pages := map[string]string{
+		"20240103000000": "artlist_page1.json",
+		"20240102143000": "artlist_page2.json",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		end := r.URL.Query().Get("enddatetime")
+		*requests = append(*requests, end)
+		name, ok := pages[end]
+		if !ok {
+			name = "artlist_empty.json"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture(t, name))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSearchPages(t *testing.T) {
+	var requests []string
+	srv := fixtureServer(t, &requests)
+
+	c := &Client{BaseURL: srv.URL, MinInterval: -1}
+	q := Query{
+		Keywords:   []string{"climate"},
+		Start:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:        time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		MaxRecords: 2,
+	}
+	items, err := c.Search(context.Background(), q, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"20240103000000", "20240102143000", "20240102120000"}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("requested windows ending %v, want %v", requests, want)
+	}
+	// The Reuters article is on both pages
+	if len(items) != 3 {
+		t.Fatalf("got %d items, want 3 unique ones", len(items))
+	}
+	if items[2].Domain != "spiegel.de" {
+		t.Errorf("last item is from %s, want spiegel.de", items[2].Domain)
+	}
+}
+
+func TestSearchStopsAtMaxPages(t *testing.T) {
+	var requests []string
+	srv := fixtureServer(t, &requests)
+
+	c := &Client{BaseURL: srv.URL, MinInterval: -1}
+	q := Query{
+		Keywords:   []string{"climate"},
+		Start:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:        time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		MaxRecords: 2,
+	}
+	items, err := c.Search(context.Background(), q, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 || len(items) != 2 {
+		t.Errorf("got %d request(s) and %d items, want 1 and 2", len(requests), len(items))
+	}
+}
+
+func TestArtListErrorBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// GDELT reports bad queries as text with status 200
+		w.Header().Set("Content-Type", "text/html")
+		w.Write(fixture(t, "artlist_error.txt"))
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL}
+	_, err := c.ArtList(context.Background(), Query{Keywords: []string{"climate"}})
+	if err == nil || !strings.Contains(err.Error(), "phrase is too short") {
+		t.Fatalf("err = %v, want the API message", err)
+	}
+}
+
+func TestArtListHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL}
+	_, err := c.ArtList(context.Background(), Query{Keywords: []string{"climate"}})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 429") {
+		t.Fatalf("err = %v, want HTTP 429", err)
+	}
+}
+
+func TestToArticles(t *testing.T) {
+	items, err := ParseArtList(fixture(t, "artlist_page2.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items = append(items, ArtListItem{Title: "No URL"})
+
+	src := models.Source{Name: "GDELT", Parser: "gdelt", Normalizer: "gdelt"}
+	articles := ToArticles(items, src)
+	if len(articles) != 2 {
+		t.Fatalf("got %d articles, want 2 (items without URL are skipped)", len(articles))
+	}
+
+	us := articles[0]
+	if us.ID != us.URL || us.Title != "Climate talks resume in Bonn" {
+		t.Errorf("unexpected article: %+v", us)
+	}
+	if us.PublishedAt == nil || *us.PublishedAt != "2024-01-02T14:30:00Z" {
+		t.Errorf("PublishedAt = %v, want 2024-01-02T14:30:00Z", us.PublishedAt)
+	}
+	if us.SourceCountry == nil || *us.SourceCountry != "US" || us.Edition == nil || *us.Edition != models.EditionUS {
+		t.Errorf("country/edition = %v/%v, want US/us", us.SourceCountry, us.Edition)
+	}
+	if us.Language == nil || *us.Language != "en" {
+		t.Errorf("Language = %v, want en", us.Language)
+	}
+	if us.Source == nil || *us.Source != "GDELT" || us.SourceMeta == nil || us.SourceMeta.Bias != "" {
+		t.Errorf("source = %v, meta = %+v; want GDELT without bias", us.Source, us.SourceMeta)
+	}
+	if us.MobileURL != nil || us.Image != nil {
+		t.Errorf("empty url_mobile/socialimage mapped to %v/%v", us.MobileURL, us.Image)
+	}
+
+	de := articles[1]
+	if de.Title != "Untitled" {
+		t.Errorf("Title = %q, want Untitled", de.Title)
+	}
+	if de.Language == nil || *de.Language != "de" {
+		t.Errorf("Language = %v, want de", de.Language)
+	}
+	if de.SourceCountry == nil || *de.SourceCountry != "DE" || de.Edition == nil || *de.Edition != models.EditionOther {
+		t.Errorf("country/edition = %v/%v, want DE/other", de.SourceCountry, de.Edition)
+	}
+}
@@ -0,0 +1,76 @@
+package gdelt
+
+import "strings"
+
+// languages maps ISO 639-1 codes to the language names GDELT uses in
+// sourcelang: filters and in the "language" field of results.
+var languages = map[string]string{
+	"af": "afrikaans", "ar": "arabic", "bg": "bulgarian", "bn": "bengali",
+	"ca": "catalan", "cs": "czech", "da": "danish", "de": "german",
+	"el": "greek", "en": "english", "es": "spanish", "et": "estonian",
+	"fa": "persian", "fi": "finnish", "fr": "french", "he": "hebrew",
+	"hi": "hindi", "hr": "croatian", "hu": "hungarian", "id": "indonesian",
+	"it": "italian", "ja": "japanese", "ko": "korean", "lt": "lithuanian",
+	"lv": "latvian", "ms": "malay", "nl": "dutch", "no": "norwegian",
+	"pl": "polish", "pt": "portuguese", "ro": "romanian", "ru": "russian",
+	"sk": "slovak", "sl": "slovenian", "sr": "serbian", "sv": "swedish",
+	"sw": "swahili", "ta": "tamil", "th": "thai", "tl": "tagalog",
+	"tr": "turkish", "uk": "ukrainian", "ur": "urdu", "vi": "vietnamese",
+	"zh": "chinese",
+}
+
+// languageCodes is the reverse of languages.
+var languageCodes = func() map[string]string {
+	m := make(map[string]string, len(languages))
+	for code, name := range languages {
+		m[name] = code
+	}
+	return m
+}()
+
+// languageName returns the GDELT name for an ISO 639-1 code; other values are
+// passed through lower-cased so names and GDELT's 3-letter codes still work.
+func languageName(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if name, ok := languages[lang]; ok {
+		return name
+	}
+	return strings.ReplaceAll(lang, " ", "")
+}
+
+// languageCode returns the ISO 639-1 code for a GDELT language name, or the
+// name itself if it is not known.
+func languageCode(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if code, ok := languageCodes[key]; ok {
+		return code
+	}
+	return strings.TrimSpace(name)
+}
+
+// countries maps the country names GDELT returns in "sourcecountry" to ISO
+// 3166-1 alpha-2 codes, for the countries most sources come from.
+var countries = map[string]string{
+	"united states": "US", "united kingdom": "GB", "canada": "CA", "australia": "AU",
+	"new zealand": "NZ", "ireland": "IE", "india": "IN", "pakistan": "PK",
+	"south africa": "ZA", "nigeria": "NG", "kenya": "KE", "egypt": "EG",
+	"germany": "DE", "france": "FR", "spain": "ES", "italy": "IT",
+	"netherlands": "NL", "belgium": "BE", "switzerland": "CH", "austria": "AT",
+	"sweden": "SE", "norway": "NO", "denmark": "DK", "finland": "FI",
+	"poland": "PL", "ukraine": "UA", "russia": "RU", "turkey": "TR",
+	"israel": "IL", "saudi arabia": "SA", "united arab emirates": "AE", "qatar": "QA",
+	"iran": "IR", "china": "CN", "hong kong": "HK", "taiwan": "TW",
+	"japan": "JP", "south korea": "KR", "north korea": "KP", "singapore": "SG",
+	"malaysia": "MY", "indonesia": "ID", "philippines": "PH", "vietnam": "VN",
+	"thailand": "TH", "brazil": "BR", "mexico": "MX", "argentina": "AR",
+	"chile": "CL", "colombia": "CO", "peru": "PE", "venezuela": "VE",
+}
+
+// countryCode returns the ISO code for a GDELT country name, or the name
+// itself if it is not known.
+func countryCode(name string) string {
+	if code, ok := countries[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return code
+	}
+	return strings.TrimSpace(name)
+}
@@ -0,0 +1,191 @@
+// Package gdelt is a client for the GDELT DOC 2.0 API
+// (https://blog.gdeltproject.org/gdelt-doc-2-0-api-debuts/). It builds artlist
+// queries, pages through results and maps them onto models.Article.
+//
+// The API is plain HTTP with JSON responses, so the client can be pointed at a
+// recorded fixture server via Client.BaseURL, and ParseArtList decodes a
+// recorded response body without any network access.
+package gdelt
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the DOC 2.0 API endpoint.
+const DefaultBaseURL = "https://api.gdeltproject.org/api/v2/doc/doc"
+
+// MaxRecordsLimit is the largest page the API returns.
+const MaxRecordsLimit = 250
+
+// timeLayout is the API's datetime format, used both for startdatetime /
+// enddatetime and for seendate in responses.
+const timeLayout = "20060102150405"
+
+// Query describes an artlist search.
+type Query struct {
+	Keywords      []string  `json:"keywords"`                // Terms ANDed together; multi-word terms are searched as phrases
+	SourceCountry string    `json:"sourceCountry,omitempty"` // FIPS country code or name, e.g. "US", "UK", "southkorea"
+	Language      string    `json:"language,omitempty"`      // ISO 639-1 code ("en") or GDELT language name ("english")
+	Domain        string    `json:"domain,omitempty"`        // Restrict to a publisher domain, e.g. "bbc.co.uk"
+	Timespan      string    `json:"timespan,omitempty"`      // Relative window, e.g. "15min", "24h", "7d", "3m"; ignored if Start is set
+	Start         time.Time `json:"start"`                   // Absolute window start, zero for none
+	End           time.Time `json:"end"`                     // Absolute window end, zero for now
+	MaxRecords    int       `json:"maxRecords,omitempty"`    // Page size, 1..MaxRecordsLimit (default MaxRecordsLimit)
+}
+
+// Expression returns the GDELT query expression, e.g.
+// `climate "carbon tax" sourcecountry:US sourcelang:english`.
+func (q Query) Expression() string {
+	var parts []string
+	for _, k := range q.Keywords {
+		k = strings.TrimSpace(k)
+		switch {
+		case k == "":
+		case strings.ContainsAny(k, " \t") && !strings.HasPrefix(k, `"`):
+			parts = append(parts, `"`+k+`"`)
+		default:
+			parts = append(parts, k)
+		}
+	}
+	if c := strings.ReplaceAll(strings.TrimSpace(q.SourceCountry), " ", ""); c != "" {
+		parts = append(parts, "sourcecountry:"+c)
+	}
+	if l := languageName(q.Language); l != "" {
+		parts = append(parts, "sourcelang:"+l)
+	}
+	if d := strings.TrimSpace(q.Domain); d != "" {
+		parts = append(parts, "domain:"+d)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Validate reports queries the API would reject.
+func (q Query) Validate() error {
+	if q.Expression() == "" {
+		return fmt.Errorf("gdelt: query needs at least one keyword or filter")
+	}
+	for _, k := range q.Keywords {
+		if k = strings.Trim(strings.TrimSpace(k), `"`); k != "" && len(k) < 3 && !strings.Contains(k, ":") {
+			return fmt.Errorf("gdelt: keyword %q is too short (minimum 3 characters)", k)
+		}
+	}
+	if q.MaxRecords < 0 || q.MaxRecords > MaxRecordsLimit {
+		return fmt.Errorf("gdelt: maxRecords must be between 1 and %d", MaxRecordsLimit)
+	}
+	if !q.Start.IsZero() && !q.End.IsZero() && !q.End.After(q.Start) {
+		return fmt.Errorf("gdelt: end must be after start")
+	}
+	return nil
+}
+
+// Values returns the URL parameters for an artlist request.
+func (q Query) Values() url.Values {
+	v := url.Values{
+		"query":      {q.Expression()},
+		"mode":       {"artlist"},
+		"format":     {"json"},
+		"sort":       {"datedesc"},
+		"maxrecords": {strconv.Itoa(q.pageSize())},
+	}
+	if !q.Start.IsZero() {
+		v.Set("startdatetime", q.Start.UTC().Format(timeLayout))
+		if !q.End.IsZero() {
+			v.Set("enddatetime", q.End.UTC().Format(timeLayout))
+		}
+	} else {
+		if q.Timespan != "" {
+			v.Set("timespan", q.Timespan)
+		}
+		if !q.End.IsZero() {
+			v.Set("enddatetime", q.End.UTC().Format(timeLayout))
+		}
+	}
+	return v
+}
+
+// URL returns the full request URL against baseURL (DefaultBaseURL if empty).
+func (q Query) URL(baseURL string) string {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return baseURL + "?" + q.Values().Encode()
+}
+
+func (q Query) pageSize() int {
+	if q.MaxRecords <= 0 || q.MaxRecords > MaxRecordsLimit {
+		return MaxRecordsLimit
+	}
+	return q.MaxRecords
+}
+
+// ParseQuery recovers a Query from an existing DOC API endpoint, such as the
+// Endpoint of a "gdelt" Source. Filters the Query type does not model stay in
+// the keywords verbatim.
+func ParseQuery(endpoint string) (Query, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return Query{}, fmt.Errorf("gdelt: invalid endpoint: %w", err)
+	}
+	v := u.Query()
+
+	var q Query
+	for _, term := range splitExpression(v.Get("query")) {
+		name, value, found := strings.Cut(term, ":")
+		switch {
+		case found && strings.EqualFold(name, "sourcecountry"):
+			q.SourceCountry = value
+		case found && strings.EqualFold(name, "sourcelang"):
+			q.Language = value
+		case found && strings.EqualFold(name, "domain"):
+			q.Domain = value
+		default:
+			q.Keywords = append(q.Keywords, strings.Trim(term, `"`))
+		}
+	}
+
+	q.Timespan = v.Get("timespan")
+	if s := v.Get("startdatetime"); s != "" {
+		if q.Start, err = time.Parse(timeLayout, s); err != nil {
+			return Query{}, fmt.Errorf("gdelt: invalid startdatetime %q", s)
+		}
+	}
+	if s := v.Get("enddatetime"); s != "" {
+		if q.End, err = time.Parse(timeLayout, s); err != nil {
+			return Query{}, fmt.Errorf("gdelt: invalid enddatetime %q", s)
+		}
+	}
+	if s := v.Get("maxrecords"); s != "" {
+		q.MaxRecords, _ = strconv.Atoi(s)
+	}
+	return q, nil
+}
+
+// splitExpression splits a query expression on spaces, keeping quoted phrases
+// together.
+func splitExpression(expr string) []string {
+	var terms []string
+	var cur strings.Builder
+	quoted := false
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			cur.WriteRune(r)
+		case r == ' ' && !quoted:
+			if cur.Len() > 0 {
+				terms = append(terms, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		terms = append(terms, cur.String())
+	}
+	return terms
+}
@@ -0,0 +1,110 @@
+package gdelt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryExpression(t *testing.T) {
+	q := Query{
+		Keywords:      []string{"climate", "carbon tax", " ", `"net zero"`},
+		SourceCountry: "south korea",
+		Language:      "en",
+		Domain:        "bbc.co.uk",
+	}
+	want := `climate "carbon tax" "net zero" sourcecountry:southkorea sourcelang:english domain:bbc.co.uk`
+	if got := q.Expression(); got != want {
+		t.Errorf("Expression() = %q, want %q", got, want)
+	}
+}
+
+func TestQueryValidate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		q    Query
+		ok   bool
+	}{
+		{"keyword", Query{Keywords: []string{"climate"}}, true},
+		{"filter only", Query{Domain: "bbc.co.uk"}, true},
+		{"empty", Query{Keywords: []string{" "}}, false},
+		{"short keyword", Query{Keywords: []string{"eu"}}, false},
+		{"page too large", Query{Keywords: []string{"climate"}, MaxRecords: MaxRecordsLimit + 1}, false},
+		{"end before start", Query{Keywords: []string{"climate"}, Start: start, End: start.Add(-time.Hour)}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.q.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate() = %v", tt.name, err)
+		}
+	}
+}
+
+func TestQueryValues(t *testing.T) {
+	q := Query{Keywords: []string{"climate"}, Timespan: "24h"}
+	v := q.Values()
+	for key, want := range map[string]string{
+		"query": "climate", "mode": "artlist", "format": "json", "sort": "datedesc",
+		"maxrecords": "250", "timespan": "24h",
+	} {
+		if got := v.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+
+	// An absolute window replaces the timespan
+	q.Start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	q.End = time.Date(2024, 1, 2, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+	q.MaxRecords = 50
+	v = q.Values()
+	if v.Has("timespan") {
+		t.Errorf("timespan %q sent with an absolute window", v.Get("timespan"))
+	}
+	if got := v.Get("startdatetime"); got != "20240101000000" {
+		t.Errorf("startdatetime = %q", got)
+	}
+	if got := v.Get("enddatetime"); got != "20240102113000" {
+		t.Errorf("enddatetime = %q, want UTC", got)
+	}
+	if got := v.Get("maxrecords"); got != "50" {
+		t.Errorf("maxrecords = %q", got)
+	}
+}
+
+func TestParseQueryRoundTrip(t *testing.T) {
+	q := Query{
+		Keywords:      []string{"climate", "carbon tax"},
+		SourceCountry: "US",
+		Language:      "english",
+		Domain:        "reuters.com",
+		Start:         time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:           time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		MaxRecords:    100,
+	}
+	got, err := ParseQuery(q.URL(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, q) {
+		t.Errorf("ParseQuery(q.URL()) = %+v, want %+v", got, q)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	endpoint := DefaultBaseURL + `?query=%22artificial+intelligence%22+theme:TAX_FNCACT+sourcelang:german&mode=artlist&timespan=7d`
+	q, err := ParseQuery(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unknown filters are kept as keywords
+	if want := []string{"artificial intelligence", "theme:TAX_FNCACT"}; !reflect.DeepEqual(q.Keywords, want) {
+		t.Errorf("Keywords = %q, want %q", q.Keywords, want)
+	}
+	if q.Language != "german" || q.Timespan != "7d" || !q.Start.IsZero() || q.MaxRecords != 0 {
+		t.Errorf("unexpected query: %+v", q)
+	}
+
+	if _, err := ParseQuery(DefaultBaseURL + "?query=climate&startdatetime=2024-01-01"); err == nil {
+		t.Error("invalid startdatetime parsed without error")
+	}
+}
@@ -0,0 +1,287 @@
+// Package hn reads stories from the Hacker News Firebase API
+// (https://github.com/HackerNews/API): a story list such as topstories.json
+// returns item IDs, and each item is then fetched individually.
+package hn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nous-app/internal/fetcher"
+	"nous-app/internal/models"
+)
+
+// DefaultBaseURL is the Firebase API root.
+const DefaultBaseURL = "https://hacker-news.firebaseio.com/v0"
+
+// DefaultConcurrency is how many items are fetched at once during fan-out.
+const DefaultConcurrency = 8
+
+// DefaultLimit is how many stories Stories returns when no limit is given.
+const DefaultLimit = 30
+
+// List names a story list.
+type List string
+
+const (
+	Top  List = "top"
+	New  List = "new"
+	Best List = "best"
+	Ask  List = "ask"
+	Show List = "show"
+	Job  List = "job"
+)
+
+// Client reads from the Firebase API.
+type Client struct {
+	BaseURL     string           // Defaults to DefaultBaseURL
+	Fetcher     *fetcher.Fetcher // Sends the requests; defaults to a Fetcher of its own
+	Source      models.Source    // Headers, auth and rate limit applied to every request
+	Concurrency int              // Item fetches in flight, defaults to DefaultConcurrency
+}
+
+// ForSource returns a client that makes its requests through f with the
+// headers, auth and rate limit of src.
+func ForSource(f *fetcher.Fetcher, src models.Source) *Client {
+	return &Client{Fetcher: f, Source: src}
+}
+
+// Item is a Hacker News item. Score and Descendants (the comment count) are
+// kept in Article.Raw.
+type Item struct {
+	ID          int64  `json:"id"`
+	Type        string `json:"type"` // "story", "job", "poll", ...
+	By          string `json:"by,omitempty"`
+	Time        int64  `json:"time"` // Unix seconds
+	Title       string `json:"title,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Text        string `json:"text,omitempty"` // HTML, for Ask HN and jobs
+	Score       int    `json:"score"`
+	Descendants int    `json:"descendants"` // Total comment count
+	Deleted     bool   `json:"deleted,omitempty"`
+	Dead        bool   `json:"dead,omitempty"`
+}
+
+// DiscussionURL is the item's page on news.ycombinator.com.
+func (it Item) DiscussionURL() string {
+	return fmt.Sprintf("https://news.ycombinator.com/item?id=%d", it.ID)
+}
+
+// StoryIDs returns the IDs in a story list, best ranked first.
+func (c *Client) StoryIDs(ctx context.Context, list List) ([]int64, error) {
+	var ids []int64
+	if err := c.get(ctx, fmt.Sprintf("/%sstories.json", list), &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Item fetches a single item. It returns nil for IDs the API has no item for.
+func (c *Client) Item(ctx context.Context, id int64) (*Item, error) {
+	var it *Item
+	if err := c.get(ctx, fmt.Sprintf("/item/%d.json", id), &it); err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
+// Stories fetches the first limit stories of a list, fanning out over the
+// item endpoint. Deleted, dead and missing items are skipped, and the list
+// order is kept. Individual item failures are logged and skipped; Stories
+// only fails if the list itself cannot be read or ctx ends.
+func (c *Client) Stories(ctx context.Context, list List, limit int) ([]Item, error) {
+	ids, err := c.StoryIDs(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	workers := c.Concurrency
+	if workers <= 0 {
+		workers = DefaultConcurrency
+	}
+
+	items := make([]*Item, len(ids))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+		wg.Add(1)
+		go func(i int, id int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			it, err := c.Item(ctx, id)
+			if err != nil {
+				log.Printf("[HN] item %d: %v\n", id, err)
+				return
+			}
+			items[i] = it
+		}(i, id)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	stories := make([]Item, 0, len(items))
+	for _, it := range items {
+		if it != nil && !it.Deleted && !it.Dead {
+			stories = append(stories, *it)
+		}
+	}
+	return stories, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	f := c.Fetcher
+	if f == nil {
+		f = &fetcher.Fetcher{}
+	}
+	body, err := f.Get(ctx, c.Source, strings.TrimSuffix(base, "/")+path)
+	if err != nil {
+		return fmt.Errorf("hn: %w", err)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("hn: invalid response for %s: %w", path, err)
+	}
+	return nil
+}
+
+// ----------------------
+// Source support
+// ----------------------
+
+// ListFromEndpoint returns the story list a Source endpoint points at, e.g.
+// ".../v0/beststories.json" is Best. Endpoints without a list default to Top.
+func ListFromEndpoint(endpoint string) List {
+	for _, l := range []List{Top, New, Best, Ask, Show, Job} {
+		if strings.Contains(endpoint, "/"+string(l)+"stories") {
+			return l
+		}
+	}
+	return Top
+}
+
+// BaseFromEndpoint returns the API root of a Source endpoint, so sources can
+// point at a mirror.
+func BaseFromEndpoint(endpoint string) string {
+	if i := strings.Index(endpoint, "/v0/"); i >= 0 {
+		return endpoint[:i+len("/v0")]
+	}
+	return DefaultBaseURL
+}
+
+// FetchSource reads the stories of the list src.Endpoint points at, making
+// its requests through f.
+func FetchSource(ctx context.Context, f *fetcher.Fetcher, src models.Source, limit int) ([]models.Article, error) {
+	c := ForSource(f, src)
+	c.BaseURL = BaseFromEndpoint(src.Endpoint)
+	items, err := c.Stories(ctx, ListFromEndpoint(src.Endpoint), limit)
+	if err != nil {
+		return nil, err
+	}
+	return ToArticles(items, src), nil
+}
+
+// ParseItems decodes a JSON array of item objects, the payload the TS "hn"
+// parser expects. A bare array of IDs (a story list) is rejected, since
+// mapping it needs the item fan-out of Stories. Null entries, items without
+// an ID and deleted or dead items are skipped.
+func ParseItems(data []byte) ([]Item, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("hn: expected a JSON array: %w", err)
+	}
+	items := make([]Item, 0, len(raw))
+	for _, r := range raw {
+		var it *Item
+		if err := json.Unmarshal(r, &it); err != nil {
+			return nil, fmt.Errorf("hn: payload is a story ID list, fetch it with hn.Client.Stories")
+		}
+		// Missing items are null, like the item endpoint returns for them
+		if it != nil && it.ID != 0 && !it.Deleted && !it.Dead {
+			items = append(items, *it)
+		}
+	}
+	return items, nil
+}
+
+// ToArticles maps items onto Articles. Stories without a URL (Ask HN, jobs)
+// link to their discussion page. Raw holds the Item, including Score and
+// Descendants.
+func ToArticles(items []Item, src models.Source) []models.Article {
+	articles := make([]models.Article, 0, len(items))
+	for _, it := range items {
+		item := it
+
+		link := it.URL
+		if link == "" {
+			link = it.DiscussionURL()
+		}
+		title := strings.TrimSpace(it.Title)
+		if title == "" {
+			title = "Untitled"
+		}
+
+		a := models.Article{
+			ID:         strconv.FormatInt(it.ID, 10),
+			Title:      title,
+			URL:        link,
+			Categories: []string{"hacker_news"},
+			Parser:     src.Parser,
+			Normalizer: src.Normalizer,
+			Confidence: src.Confidence,
+			Language:   src.Language,
+			Raw:        &item,
+			SourceMeta: &models.SourceMeta{Name: src.Name, Bias: src.Bias, Confidence: src.Confidence},
+		}
+		if a.Language == nil {
+			en := "en"
+			a.Language = &en
+		}
+		if src.Name != "" {
+			name := src.Name
+			a.Source = &name
+		}
+		if it.By != "" {
+			by := it.By
+			a.Author = &by
+		}
+		if it.Text != "" {
+			text := it.Text
+			a.Content = &text
+			a.Summary = &text
+		}
+		if it.Time > 0 {
+			iso := time.Unix(it.Time, 0).UTC().Format(time.RFC3339)
+			a.PublishedAt = &iso
+		}
+		edition := models.EditionOther
+		a.Edition = &edition
+
+		articles = append(articles, a)
+	}
+	return articles
+}
@@ -0,0 +1,69 @@
+package hn
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nous-app/internal/fetcher"
+	"nous-app/internal/models"
+)
+
+func TestFetchSource(t *testing.T) {
+	var agents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Header.Get("User-Agent"))
+		switch r.URL.Path {
+		case "/v0/beststories.json":
+			w.Write([]byte(`[3, 1, 2, 4]`))
+		case "/v0/item/1.json":
+			w.Write([]byte(`{"id": 1, "type": "story", "title": "One", "url": "https://a.example/1", "time": 1700000000}`))
+		case "/v0/item/2.json":
+			w.Write([]byte(`{"id": 2, "type": "story", "deleted": true}`))
+		case "/v0/item/3.json":
+			w.Write([]byte(`{"id": 3, "type": "story", "title": "Ask HN: Three", "text": "<p>Why?</p>", "by": "pg"}`))
+		default:
+			w.Write([]byte(`null`))
+		}
+	}))
+	defer srv.Close()
+
+	src := models.Source{
+		Name:     "HN Best",
+		Endpoint: srv.URL + "/v0/beststories.json",
+		Parser:   "hn",
+		Headers:  map[string]string{"User-Agent": "custom-agent"},
+	}
+	articles, err := FetchSource(context.Background(), fetcher.New(1), src, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 2 || articles[0].Title != "Ask HN: Three" || articles[1].URL != "https://a.example/1" {
+		t.Fatalf("got %+v, want stories 3 and 1 in list order", articles)
+	}
+	if articles[0].URL != "https://news.ycombinator.com/item?id=3" {
+		t.Errorf("Ask HN URL = %q, want the discussion page", articles[0].URL)
+	}
+	if len(agents) != 5 {
+		t.Errorf("%d requests, want the list and 4 items", len(agents))
+	}
+	for _, ua := range agents {
+		if ua != "custom-agent" {
+			t.Errorf("User-Agent %q, want the source's", ua)
+		}
+	}
+}
+
+func TestFetchSourceListError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	src := models.Source{Name: "HN", Endpoint: srv.URL + "/v0/topstories.json"}
+	if _, err := FetchSource(context.Background(), fetcher.New(1), src, 10); err == nil || !strings.Contains(err.Error(), "HTTP 403") {
+		t.Errorf("err = %v, want HTTP 403", err)
+	}
+}
@@ -0,0 +1,490 @@
+// Package models defines the data structures shared between the Wails app,
+// the P2P node HTTP API and the frontend bindings.
+package models
+
+import "encoding/json"
+
+// ----------------------
+// API Response Wrapper
+// ----------------------
+
+// APIResponse is the envelope returned by every data binding of the App.
+//
+// It ensures every response indicates success/failure and carries either a
+// machine-readable error code with a message, or the requested data payload.
+// RequestID identifies the call in the Go logs.
+//
+// Example JSON:
+//
+//	{
+//	  "success": false,
+//	  "code": "node_unreachable",
+//	  "error": "GET /articles/local: request failed: connection refused",
+//	  "requestId": "9f2c4e1ab07d3c55"
+//	}
+type APIResponse struct {
+	Success   bool        `json:"success"`         // True if the operation succeeded, false otherwise
+	Code      ErrorCode   `json:"code,omitempty"`  // Machine-readable error code when Success is false
+	Error     string      `json:"error,omitempty"` // Optional error message when Success is false
+	Data      interface{} `json:"data,omitempty"`  // Optional payload; on validation failures, the issues
+	RequestID string      `json:"requestId"`       // Identifier of the call, for log correlation
+}
+
+// ErrorCode classifies why an APIResponse failed.
+type ErrorCode string
+
+const (
+	ErrorNodeUnreachable ErrorCode = "node_unreachable"  // P2P node not running or not answering
+	ErrorNotFound        ErrorCode = "not_found"         // Requested item does not exist
+	ErrorBadRequest      ErrorCode = "bad_request"       // Invalid arguments from the caller
+	ErrorValidation      ErrorCode = "validation_failed" // Input failed validation; Data lists the issues
+	ErrorSecretsLocked   ErrorCode = "secrets_locked"    // Secret store waits for its passphrase
+	ErrorUpstreamTimeout ErrorCode = "upstream_timeout"  // Node or remote source did not answer in time
+	ErrorRateLimited     ErrorCode = "rate_limited"      // Node asked us to slow down
+	ErrorCanceled        ErrorCode = "canceled"          // Cancelled by the frontend or app shutdown
+	ErrorUpstream        ErrorCode = "upstream_error"    // Node answered with an error or malformed body
+	ErrorInternal        ErrorCode = "internal"          // Failure inside the Go app itself
+)
+
+// ----------------------
+// Debug Log Entry
+// ----------------------
+
+// DebugLogEntry represents a single log entry in the P2P debug log database.
+//
+// Each entry includes a unique ID, timestamp, message, log level, and optional metadata.
+// These entries are stored in OrbitDB (or similar) and are intended for debugging
+// and operational tracing of the P2P node.
+//
+// Example JSON:
+//
+//	{
+//	  "_id": "uuid",
+//	  "timestamp": "2025-11-26T09:36:11.340Z",
+//	  "message": "Node debug DB initialized",
+//	  "level": "info",
+//	  "meta": { "port": "9001" }
+//	}
+type DebugLogEntry struct {
+	ID        string                 `json:"_id"`            // Unique identifier for the log entry (UUID recommended)
+	Timestamp string                 `json:"timestamp"`      // ISO 8601 timestamp of when the entry was created
+	Message   string                 `json:"message"`        // Human-readable log message
+	Level     string                 `json:"level"`          // Log level: "info", "warn", or "error"
+	Meta      map[string]interface{} `json:"meta,omitempty"` // Optional metadata, e.g., port number, type, or context info
+}
+
+// ----------------------
+// Node Status
+// ----------------------
+
+// ConnectionInfo describes a peer known to the P2P node.
+type ConnectionInfo struct {
+	PeerID    string   `json:"peerId"`    // libp2p peer ID
+	Addresses []string `json:"addresses"` // Multiaddrs the peer is reachable on
+	Connected bool     `json:"connected"` // True if currently connected
+}
+
+// NodeStatus mirrors the status document served by GET /status on the P2P node.
+//
+// Example JSON:
+//
+//	{
+//	  "running": true,
+//	  "connected": true,
+//	  "orbitConnected": true,
+//	  "syncing": false,
+//	  "modelsPrefetched": true,
+//	  "lastSync": "2025-11-26T09:36:11.340Z",
+//	  "port": 9001,
+//	  "peers": [],
+//	  "logs": []
+//	}
+type NodeStatus struct {
+	Running          bool             `json:"running"`          // Node process is up
+	Connected        bool             `json:"connected"`        // libp2p has at least one connection
+	OrbitConnected   bool             `json:"orbitConnected"`   // OrbitDB databases are open
+	Syncing          bool             `json:"syncing"`          // Replication in progress
+	ModelsPrefetched bool             `json:"modelsPrefetched"` // AI models are cached locally
+	LastSync         *string          `json:"lastSync"`         // ISO timestamp of the last completed sync
+	Port             int              `json:"port,omitempty"`   // HTTP API port
+	Peers            []ConnectionInfo `json:"peers,omitempty"`  // Known peers
+	Logs             []string         `json:"logs,omitempty"`   // Recent node log lines
+}
+
+// ----------------------
+// Source Metadata
+// ----------------------
+
+// SourceMeta holds basic information about a source and its bias.
+// - Name: the human-readable source name
+// - Bias: political or ideological bias (e.g., "left", "right", "neutral")
+// - Confidence: optional confidence score in bias assessment (0-1)
+type SourceMeta struct {
+	Name       string   `json:"name"`                 // Name of the source, e.g., "CBS News"
+	Bias       string   `json:"bias"`                 // Political/ideological leaning: "left", "center", "right"
+	Confidence *float64 `json:"confidence,omitempty"` // Confidence score in bias classification (0-1)
+}
+
+// ----------------------
+// Source Definition
+// ----------------------
+
+// Source defines a data source for articles, including endpoints, auth, and metadata.
+// - Parser / Normalizer: define how the data is processed and normalized
+// - Bias / Factuality: optional bias and factuality scoring
+// - Ownership: company or organization ownership info
+type Source struct {
+	Name            string            `json:"name"`                             // Name of the source (e.g., "BBC News")
+	Endpoint        string            `json:"endpoint"`                         // API or RSS endpoint URL
+	APIKey          *string           `json:"apiKey,omitempty"`                 // Optional API key provided by the user; never written to sources.json
+	APIKeyRef       *string           `json:"apiKeyRef,omitempty"`              // Reference to the API key in the secret store
+	Instructions    *string           `json:"instructions,omitempty"`           // Optional instructions for using the source
+	APILink         *string           `json:"apiLink,omitempty"`                // Optional link to API docs
+	Enabled         *bool             `json:"enabled,omitempty"`                // Optional flag indicating if source is active
+	RequiresAPIKey  *bool             `json:"requiresApiKey,omitempty"`         // Optional flag for API key requirement
+	Category        *string           `json:"category,omitempty"`               // Optional source category (e.g., news, blog, rss)
+	Tags            []string          `json:"tags,omitempty"`                   // Optional array of tags
+	Language        *string           `json:"language,omitempty"`               // Optional ISO 639-1 language code
+	Region          *string           `json:"region,omitempty"`                 // Optional region code
+	AuthType        *string           `json:"authType,omitempty"`               // Optional auth type: none, apiKey, bearerToken, oauth, etc.
+	RateLimitPerMin *int              `json:"rateLimitPerMinute,omitempty"`     // Optional rate limit
+	RefreshInterval *int              `json:"refreshIntervalMinutes,omitempty"` // Optional minutes between background fetches
+	Headers         map[string]string `json:"headers,omitempty"`                // Optional custom headers; credentials such as Authorization are kept in the secret store
+	LastUpdated     *string           `json:"lastUpdated,omitempty"`
+	Pinned          *bool             `json:"pinned,omitempty"`
+
+	// Parser & Normalizer
+	Parser     string `json:"parser"`     // defaults to "json"
+	Normalizer string `json:"normalizer"` // defaults to "json"
+
+	// Bias / Factuality
+	Bias       string   `json:"bias,omitempty"`
+	Factuality *string  `json:"factuality,omitempty"`
+	Confidence *float64 `json:"confidence,omitempty"`
+
+	// Ownership info
+	Ownership *Ownership `json:"ownership,omitempty"`
+
+	// Scraping rules for the "html" parser
+	Scrape *ScrapeRules `json:"scrape,omitempty"`
+
+	// Last fetched timestamp
+	LastFetched *string `json:"lastFetched,omitempty"`
+}
+
+// ----------------------
+// HTML Scraping Rules
+// ----------------------
+
+// ScrapeRules tell the "html" parser how to find articles on a page. Item is a
+// CSS selector for the element wrapping each article; the other rules are
+// selectors evaluated inside it. A rule may end in "@attr" to read an
+// attribute instead of the text, e.g. "a.headline@href" or "img@data-src".
+// - Link defaults to the first a[href] in the item (or the item itself)
+// - Date reads the datetime attribute of <time> elements when no @attr is given
+// - DateLayout is an optional Go time layout for dates in a site-specific format
+type ScrapeRules struct {
+	Item       string `json:"item"`
+	Title      string `json:"title"`
+	Link       string `json:"link,omitempty"`
+	Date       string `json:"date,omitempty"`
+	DateLayout string `json:"dateLayout,omitempty"`
+	Summary    string `json:"summary,omitempty"`
+	Image      string `json:"image,omitempty"`
+	Author     string `json:"author,omitempty"`
+}
+
+// ----------------------
+// Ownership Schema
+// ----------------------
+
+// Ownership represents the owner organization of a source.
+// - CompanyName: official organization name
+// - Type: type of organization (private, government, NGO, etc.)
+// - Country: optional country code
+type Ownership struct {
+	CompanyName string  `json:"companyName"`
+	Type        string  `json:"type"` // private, government, ngo, etc.
+	Country     *string `json:"country,omitempty"`
+}
+
+// ----------------------
+// Article Editions
+// ----------------------
+
+// Edition defines the regional or contextual edition of an article.
+type Edition string
+
+const (
+	EditionInternational Edition = "international"
+	EditionUS            Edition = "us"
+	EditionUK            Edition = "uk"
+	EditionKR            Edition = "kr"
+	EditionCN            Edition = "cn"
+	EditionOther         Edition = "other"
+)
+
+// ----------------------
+// Federated Article Pointer
+// ----------------------
+
+// FederatedArticlePointer is a minimal representation of an article shared across nodes.
+// - CID: IPFS Content Identifier for fetching full content
+// - Timestamp: creation or last update of the pointer
+// - Hash: optional content hash for verification
+// - Analyzed: true if this article has been analyzed
+// - Source / Edition: optional metadata
+type FederatedArticlePointer struct {
+	CID       string  `json:"cid"`               // Content Identifier (IPFS)
+	Timestamp string  `json:"timestamp"`         // ISO timestamp of creation/update
+	Hash      *string `json:"hash,omitempty"`    // Optional content hash
+	Analyzed  bool    `json:"analyzed"`          // True if article was analyzed
+	Source    *string `json:"source,omitempty"`  // Optional source name
+	Edition   *string `json:"edition,omitempty"` // Optional edition/region
+}
+
+// ----------------------
+// Raw Article (Ingested)
+// ----------------------
+
+// Article represents a raw article before analysis, including minimal metadata.
+// - ID, Title, URL: required identifiers
+// - Content, Summary, Image: optional media and text fields
+// - Categories / Tags: optional classification
+// - SourceMeta: optional bias/factuality metadata
+type Article struct {
+	ID            string      `json:"id"`                    // Unique identifier (hash, UUID)
+	Title         string      `json:"title"`                 // Article title
+	URL           string      `json:"url"`                   // Fully qualified URL
+	Content       *string     `json:"content,omitempty"`     // Full content
+	Summary       *string     `json:"summary,omitempty"`     // Optional short summary
+	Image         *string     `json:"image,omitempty"`       // Primary image URL
+	Categories    []string    `json:"categories,omitempty"`  // Optional categories
+	Tags          []string    `json:"tags,omitempty"`        // Optional tags
+	Language      *string     `json:"language,omitempty"`    // ISO 639-1 language code
+	Author        *string     `json:"author,omitempty"`      // Optional author
+	PublishedAt   *string     `json:"publishedAt,omitempty"` // Optional ISO timestamp
+	Edition       *Edition    `json:"edition,omitempty"`     // Optional regional edition
+	Analyzed      bool        `json:"analyzed"`              // False until analyzed
+	IPFSHash      *string     `json:"ipfsHash,omitempty"`
+	Raw           interface{} `json:"raw,omitempty"`
+	SourceMeta    *SourceMeta `json:"sourceMeta,omitempty"`
+	FetchedAt     *string     `json:"fetchedAt,omitempty"`
+	Parser        string      `json:"parser"`     // frontend-compatible parser
+	Normalizer    string      `json:"normalizer"` // frontend-compatible normalizer
+	Confidence    *float64    `json:"confidence,omitempty"`
+	MobileURL     *string     `json:"mobileUrl,omitempty"`
+	Source        *string     `json:"source,omitempty"`
+	SourceDomain  *string     `json:"sourceDomain,omitempty"`
+	SourceType    *string     `json:"sourceType,omitempty"`
+	SourceCountry *string     `json:"sourceCountry,omitempty"`
+	Sources       []SourceRef `json:"sources,omitempty"`     // Every source that carried this article, set by dedup
+	DuplicateOf   *string     `json:"duplicateOf,omitempty"` // ID of the article this is a near copy of, set by dedup
+}
+
+// ArticleKey identifies an article across stores, listings and the search
+// index: its ID, or its URL if it has none yet.
+func ArticleKey(id, url string) string {
+	if id != "" {
+		return id
+	}
+	return url
+}
+
+// SourceRef records one source that carried an article merged by dedup, with
+// the URL and ID the article had there.
+type SourceRef struct {
+	Name        string  `json:"name"`
+	Bias        string  `json:"bias,omitempty"`
+	URL         string  `json:"url"`
+	ID          string  `json:"id,omitempty"`
+	PublishedAt *string `json:"publishedAt,omitempty"`
+}
+
+// ----------------------
+// Analyzed Article
+// ----------------------
+
+// CognitiveBias represents one detected cognitive bias in an article.
+type CognitiveBias struct {
+	Bias        string  `json:"bias"`
+	Snippet     string  `json:"snippet"`
+	Explanation string  `json:"explanation"`
+	Severity    string  `json:"severity"`
+	Description *string `json:"description,omitempty"`
+	Category    *string `json:"category,omitempty"`
+}
+
+// ArticleAnalyzed extends Article with AI-enriched fields.
+// - PoliticalBias: optional political/ideological classification
+// - Antithesis / Philosophical: optional interpretative summaries
+// - Sentiment: optional sentiment label and valence
+// - CognitiveBiases: optional array of detected cognitive biases
+// - ClickbaitLevel, CredibilityLevel, SubjectivityLevel: optional quality metrics
+// - EmotionalPalette: optional dominant/secondary emotions
+// - Readability: optional reading difficulty metrics
+// - Trustworthiness: optional 1-5 score
+// - AnalysisTimestamp: when analysis was performed
+type ArticleAnalyzed struct {
+	Article                          // Embed base Article
+	PoliticalBias    *string         `json:"politicalBias,omitempty"`   // Optional political/ideological bias
+	Antithesis       *string         `json:"antithesis,omitempty"`      // Concise summary of opposing viewpoints
+	Philosophical    *string         `json:"philosophical,omitempty"`   // Optional philosophical interpretation
+	Sentiment        *string         `json:"sentiment,omitempty"`       // e.g., positive/negative/neutral
+	CognitiveBiases  []CognitiveBias `json:"cognitiveBiases,omitempty"` // Array of detected biases
+	Confidence       *float64        `json:"confidence,omitempty"`      // Confidence of analysis (0-1)
+	SentimentValence *float64        `json:"sentimentValence,omitempty"`
+	ClickbaitLevel   *string         `json:"clickbaitLevel,omitempty"`
+	CredibilityLevel *string         `json:"credibilityLevel,omitempty"`
+	EmotionalPalette *struct {
+		Dominant  string  `json:"dominant"`
+		Secondary *string `json:"secondary,omitempty"`
+	} `json:"emotionalPalette,omitempty"`
+	Readability *struct {
+		FleschEase   *float64 `json:"fleschEase,omitempty"`
+		FleschGrade  *float64 `json:"fleschGrade,omitempty"`
+		ReadingLevel *string  `json:"readingLevel,omitempty"`
+	} `json:"readability,omitempty"`
+	SubjectivityLevel *string  `json:"subjectivityLevel,omitempty"`
+	Trustworthiness   *float64 `json:"trustworthiness,omitempty"`
+	AnalysisTimestamp *string  `json:"analysisTimestamp,omitempty"`
+}
+
+// ArticlesResponse represents the standard response from the P2P HTTP API
+// when fetching multiple articles from sources.
+//
+// The response wraps the list of articles in a success envelope to indicate
+// whether the operation was successful, along with the actual articles array.
+//
+// Example JSON:
+//
+//	{
+//	  "success": true,
+//	  "articles": [
+//	    { "id": "123", "title": "Example Article", "url": "https://example.com" },
+//	    ...
+//	  ]
+//	}
+type ArticlesResponse struct {
+	Success  bool      `json:"success"`  // True if the fetch operation succeeded
+	Articles []Article `json:"articles"` // Array of Article objects retrieved
+}
+
+// ArticlesBySource represents a collection of raw feed data grouped by source name.
+//
+// Each entry maps a source name to the raw response fetched from that source.
+// The raw data can be JSON, XML, RSS, HTML, or any other format provided by the source.
+// Parsing and normalization is intended to be handled by the Node/JS frontend.
+//
+// This structure allows the frontend to handle different formats per source,
+// while Go focuses solely on fetching the data.
+//
+// Example:
+//
+//	{
+//	  "BBC News": "<rss>...</rss>",
+//	  "NY Times": "[{ \"id\": \"3\", \"title\": \"Article C\", \"url\": \"https://nytimes.com/c\" }]"
+//
+//	}
+type ArticlesBySource map[string][]byte
+
+// MarshalJSON encodes each raw payload as a string rather than base64.
+func (a ArticlesBySource) MarshalJSON() ([]byte, error) {
+	out := make(map[string]string, len(a))
+	for name, raw := range a {
+		out[name] = string(raw)
+	}
+	return json.Marshal(out)
+}
+
+// FetchMeta describes how fetching one source went.
+type FetchMeta struct {
+	StatusCode  int    `json:"statusCode"`            // HTTP status, 0 if no response was received
+	Bytes       int64  `json:"bytes"`                 // Size of the payload
+	DurationMs  int64  `json:"durationMs"`            // Time spent, including rate limit waits
+	ContentType string `json:"contentType,omitempty"` // Content-Type reported by the source
+	FetchedAt   string `json:"fetchedAt"`             // RFC3339 start time
+	NotModified bool   `json:"notModified,omitempty"` // Source answered 304 or is still fresh: no new items
+	RetryAt     string `json:"retryAt,omitempty"`     // RFC3339 time the source asked us to wait until (Retry-After)
+	Error       string `json:"error,omitempty"`       // Set if the fetch failed
+}
+
+// FetchResult is the outcome of fetching a set of sources from Go: a payload
+// per source that succeeded (raw from the fetcher, the parsed articles as a
+// JSON array from FetchArticlesBySources) plus metadata for every source.
+type FetchResult struct {
+	Articles ArticlesBySource     `json:"articles"`
+	Meta     map[string]FetchMeta `json:"meta"` // Keyed by source name
+}
+
+// ArticleStatus represents the processing state of an article
+type ArticleStatus struct {
+	ID      string           `json:"id"`
+	Status  string           `json:"status"`            // "pending" | "complete"
+	Article *ArticleAnalyzed `json:"article,omitempty"` // set once complete
+}
+
+// TranslationRequest represents the request body for translating specified fields of articles
+type TranslationRequest struct {
+	Identifiers    []string `json:"identifiers"`    // Article URLs, internal IDs, or IPFS CIDs
+	TargetLanguage string   `json:"targetLanguage"` // e.g., "en", "ko"
+	Keys           []string `json:"keys,omitempty"` // Fields to translate, default ["title"]
+	Overwrite      bool     `json:"overwrite"`      // Whether to overwrite existing translations
+}
+
+// ----------------------
+// Story Clusters
+// ----------------------
+
+// Political bias values used for coverage, matching PoliticalBiasValues in
+// the frontend.
+const (
+	BiasLeft      = "left"
+	BiasLeanLeft  = "lean-left"
+	BiasCenter    = "center"
+	BiasLeanRight = "lean-right"
+	BiasRight     = "right"
+	BiasUnknown   = "unknown"
+)
+
+// SourceCluster is one source's contribution to a story cluster.
+type SourceCluster struct {
+	Name       string `json:"name"`                 // Name of the source, e.g. "BBC News"
+	Bias       string `json:"bias"`                 // One of the Bias* values
+	ArticleURL string `json:"articleUrl,omitempty"` // The source's article about the story
+}
+
+// Coverage holds the percentage (0-100) of a story's sources per bias.
+type Coverage struct {
+	Left      float64 `json:"left"`
+	LeanLeft  float64 `json:"lean-left"`
+	Center    float64 `json:"center"`
+	LeanRight float64 `json:"lean-right"`
+	Right     float64 `json:"right"`
+	Unknown   float64 `json:"unknown"`
+}
+
+// StoryCluster groups the articles of several sources covering the same
+// story, mirroring StoryClusterSchema in the frontend.
+//
+// Example JSON:
+//
+//	{
+//	  "storyId": "3f1c9a0b7e2d4c65",
+//	  "title": "Central bank raises rates by a quarter point",
+//	  "sources": [
+//	    { "name": "BBC News", "bias": "center", "articleUrl": "https://bbc.co.uk/news/..." }
+//	  ],
+//	  "coverage": { "left": 0, "lean-left": 0, "center": 100, "lean-right": 0, "right": 0, "unknown": 0 },
+//	  "articleIds": ["5d41402abc4b2a76b9719d911017c592"],
+//	  "lastUpdated": "2025-03-04T10:00:00Z"
+//	}
+type StoryCluster struct {
+	StoryID     string          `json:"storyId"`               // Stable for as long as the story's first article is in the cluster
+	Sources     []SourceCluster `json:"sources"`               // One entry per source, in order of first coverage
+	Coverage    Coverage        `json:"coverage"`              // Share of Sources per bias
+	Title       string          `json:"title,omitempty"`       // Canonical title
+	Summary     string          `json:"summary,omitempty"`     // Summary of the article the title was taken from
+	LastUpdated string          `json:"lastUpdated,omitempty"` // RFC3339 time of the most recent article
+	ArticleIDs  []string        `json:"articleIds,omitempty"`  // Articles in the cluster, oldest first
+}
@@ -0,0 +1,162 @@
+package nodeclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"nous-app/internal/models"
+)
+
+// SaveResult is returned by the node after saving an article.
+type SaveResult struct {
+	Success     bool   `json:"success"`
+	URL         string `json:"url,omitempty"`         // URL of the saved article
+	Overwritten bool   `json:"overwritten,omitempty"` // True if an existing article was replaced
+}
+
+// DeleteResult is returned by the node after deleting an article.
+type DeleteResult struct {
+	Success bool   `json:"success"`
+	URL     string `json:"url,omitempty"` // Identifier of the deleted article
+}
+
+// ----------------------
+// Local articles
+// ----------------------
+
+// LocalArticles lists every article in the local store (GET /articles/local).
+func (c *Client) LocalArticles(ctx context.Context) ([]models.Article, error) {
+	var articles []models.Article
+	err := c.do(ctx, http.MethodGet, "/articles/local", nil, nil, &articles)
+	return articles, err
+}
+
+// LocalArticle fetches one article with its full content by ID, CID or URL
+// (GET /articles/local/full).
+func (c *Client) LocalArticle(ctx context.Context, idOrCIDOrURL string) (*models.ArticleAnalyzed, error) {
+	var article models.ArticleAnalyzed
+	query := url.Values{"id": {idOrCIDOrURL}}
+	if err := c.do(ctx, http.MethodGet, "/articles/local/full", query, nil, &article); err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// LocalArticleJSON is LocalArticle returning the stored JSON object as is,
+// including the fields the Go types don't model.
+func (c *Client) LocalArticleJSON(ctx context.Context, idOrCIDOrURL string) (map[string]interface{}, error) {
+	var article map[string]interface{}
+	query := url.Values{"id": {idOrCIDOrURL}}
+	if err := c.do(ctx, http.MethodGet, "/articles/local/full", query, nil, &article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// SaveLocalArticle stores an article in the local store (POST /articles/local/save).
+// article is a models.Article, or the article's raw JSON object, sent as is
+// so fields the Go types don't model reach the node.
+func (c *Client) SaveLocalArticle(ctx context.Context, article interface{}, overwrite bool) (*SaveResult, error) {
+	var res SaveResult
+	query := url.Values{"overwrite": {strconv.FormatBool(overwrite)}}
+	if err := c.do(ctx, http.MethodPost, "/articles/local/save", query, article, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteLocalArticle removes an article from the local store (DELETE /articles/local/delete/:id).
+func (c *Client) DeleteLocalArticle(ctx context.Context, id string) (*DeleteResult, error) {
+	var res DeleteResult
+	if err := c.do(ctx, http.MethodDelete, "/articles/local/delete/"+url.PathEscape(id), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// TranslateArticles translates fields of the identified articles and returns
+// the updated articles (POST /articles/local/translate).
+func (c *Client) TranslateArticles(ctx context.Context, req models.TranslationRequest) ([]models.Article, error) {
+	var res struct {
+		Success bool             `json:"success"`
+		Data    []models.Article `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/articles/local/translate", nil, req, &res); err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
+// AddLocalArticles stores the articles the local store doesn't have yet,
+// matched by URL, and returns how many were added (POST /articles/local/refetch).
+func (c *Client) AddLocalArticles(ctx context.Context, articles []models.Article) (int, error) {
+	var res struct {
+		Success bool `json:"success"`
+		Added   int  `json:"added"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/articles/local/refetch", nil, articles, &res); err != nil {
+		return 0, err
+	}
+	return res.Added, nil
+}
+
+// ----------------------
+// Analyzed articles
+// ----------------------
+
+// AnalyzedArticles lists every analyzed article (GET /articles/analyzed).
+func (c *Client) AnalyzedArticles(ctx context.Context) ([]models.ArticleAnalyzed, error) {
+	var articles []models.ArticleAnalyzed
+	err := c.do(ctx, http.MethodGet, "/articles/analyzed", nil, nil, &articles)
+	return articles, err
+}
+
+// SaveAnalyzedArticle stores an analyzed article (POST /articles/analyzed/save).
+// Like SaveLocalArticle, article may be the raw JSON object.
+func (c *Client) SaveAnalyzedArticle(ctx context.Context, article interface{}) (*SaveResult, error) {
+	var res SaveResult
+	if err := c.do(ctx, http.MethodPost, "/articles/analyzed/save", nil, article, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteAnalyzedArticle removes an analyzed article (DELETE /articles/analyzed/delete/:id).
+func (c *Client) DeleteAnalyzedArticle(ctx context.Context, id string) (*DeleteResult, error) {
+	var res DeleteResult
+	if err := c.do(ctx, http.MethodDelete, "/articles/analyzed/delete/"+url.PathEscape(id), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ----------------------
+// Federated articles
+// ----------------------
+
+// FederatedArticles lists every federated article pointer (GET /articles/federated).
+func (c *Client) FederatedArticles(ctx context.Context) ([]models.FederatedArticlePointer, error) {
+	var pointers []models.FederatedArticlePointer
+	err := c.do(ctx, http.MethodGet, "/articles/federated", nil, nil, &pointers)
+	return pointers, err
+}
+
+// SaveFederatedArticle stores a federated article pointer (POST /articles/federated/save).
+func (c *Client) SaveFederatedArticle(ctx context.Context, pointer models.FederatedArticlePointer) (*SaveResult, error) {
+	var res SaveResult
+	if err := c.do(ctx, http.MethodPost, "/articles/federated/save", nil, pointer, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteFederatedArticle removes a federated article pointer (DELETE /articles/federated/delete/:id).
+func (c *Client) DeleteFederatedArticle(ctx context.Context, id string) (*DeleteResult, error) {
+	var res DeleteResult
+	if err := c.do(ctx, http.MethodDelete, "/articles/federated/delete/"+url.PathEscape(id), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
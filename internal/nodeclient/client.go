@@ -0,0 +1,144 @@
+// Package nodeclient is a typed client for the HTTP API served by the bundled
+// P2P node (backend/src/httpServer.ts).
+//
+// Every call takes a context, decodes the JSON response into the structs from
+// internal/models and reports failures as *Error, so callers never have to
+// tell an error message apart from a response body.
+package nodeclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+)
+
+// Client talks to a single P2P node.
+type Client struct {
+	BaseURL    string       // e.g. "http://localhost:9001"
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+}
+
+// New creates a client for the node listening at baseURL.
+func New(baseURL string) *Client {
+	return &Client{BaseURL: baseURL}
+}
+
+// Error describes a failed node call: the node was unreachable, answered with
+// a non-2xx status, or sent a body that could not be decoded.
+type Error struct {
+	Method     string // HTTP method of the failed call
+	Path       string // Request path, without base URL or query
+	StatusCode int    // HTTP status, 0 if no response was received
+	Message    string // Error message sent by the node, or a description of the failure
+	Err        error  // Underlying transport or decoding error, if any
+
+	transport bool // True if the request was sent but no response came back
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s %s: status %d: %s", e.Method, e.Path, e.StatusCode, msg)
+	}
+	return fmt.Sprintf("%s %s: %s", e.Method, e.Path, msg)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IsNotFound reports whether err is a 404 answer from the node.
+func IsNotFound(err error) bool {
+	var nodeErr *Error
+	return errors.As(err, &nodeErr) && nodeErr.StatusCode == http.StatusNotFound
+}
+
+// IsUnreachable reports whether err means the node never answered:
+// connection refused, timeout or cancellation.
+func IsUnreachable(err error) bool {
+	var nodeErr *Error
+	return errors.As(err, &nodeErr) && nodeErr.transport
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// do sends a request to the node and decodes the JSON response into out.
+// body is JSON-encoded when non-nil; out may be nil to discard the response.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	endpoint := c.BaseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return &Error{Method: method, Path: path, Message: "failed to encode request", Err: err}
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return &Error{Method: method, Path: path, Message: "failed to build request", Err: err}
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	log.Printf("%s %s\n", method, endpoint)
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		log.Printf("%s ERROR %s -> %v\n", method, endpoint, err)
+		return &Error{Method: method, Path: path, Message: "request failed", Err: err, transport: true}
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &Error{Method: method, Path: path, StatusCode: resp.StatusCode, Message: "failed to read response", Err: err}
+	}
+
+	log.Printf("%s %s -> status %d\n", method, endpoint, resp.StatusCode)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &Error{Method: method, Path: path, StatusCode: resp.StatusCode, Message: errorMessage(b, resp.Status)}
+	}
+
+	if out == nil || len(b) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return &Error{Method: method, Path: path, StatusCode: resp.StatusCode, Message: "failed to decode response", Err: err}
+	}
+	return nil
+}
+
+// errorMessage extracts the {"error": "..."} message the node sends with
+// failed requests, falling back to the HTTP status text.
+func errorMessage(body []byte, status string) string {
+	var payload struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error != "" {
+		return payload.Error
+	}
+	return status
+}
@@ -0,0 +1,28 @@
+package nodeclient
+
+import (
+	"context"
+	"net/http"
+
+	"nous-app/internal/models"
+)
+
+// DebugLogs lists the node's debug log entries (GET /debug/logs).
+func (c *Client) DebugLogs(ctx context.Context) ([]models.DebugLogEntry, error) {
+	var entries []models.DebugLogEntry
+	err := c.do(ctx, http.MethodGet, "/debug/logs", nil, nil, &entries)
+	return entries, err
+}
+
+// AddDebugLog appends an entry to the node's debug log and returns the stored
+// entry (POST /debug/log).
+func (c *Client) AddDebugLog(ctx context.Context, entry models.DebugLogEntry) (*models.DebugLogEntry, error) {
+	var res struct {
+		Success bool                 `json:"success"`
+		Entry   models.DebugLogEntry `json:"entry"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/debug/log", nil, entry, &res); err != nil {
+		return nil, err
+	}
+	return &res.Entry, nil
+}
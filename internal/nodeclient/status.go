@@ -0,0 +1,32 @@
+package nodeclient
+
+import (
+	"context"
+	"net/http"
+
+	"nous-app/internal/models"
+)
+
+// Status returns the node status (GET /status).
+func (c *Client) Status(ctx context.Context) (*models.NodeStatus, error) {
+	var status models.NodeStatus
+	if err := c.do(ctx, http.MethodGet, "/status", nil, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// UpdateStatus merges patch into the persisted node status and returns the
+// result (POST /status).
+func (c *Client) UpdateStatus(ctx context.Context, patch map[string]interface{}) (*models.NodeStatus, error) {
+	var status models.NodeStatus
+	if err := c.do(ctx, http.MethodPost, "/status", nil, patch, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// DeleteStatus removes the persisted node status (DELETE /status).
+func (c *Client) DeleteStatus(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/status", nil, nil, nil)
+}
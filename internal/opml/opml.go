@@ -0,0 +1,422 @@
+// Package opml converts between OPML subscription lists and Sources.
+//
+// Feeds map to Sources as follows: the outline's text (or title) is the Name,
+// xmlUrl the Endpoint, language the Language and the comma-separated category
+// attribute the Tags (tags that can't be written that way, such as ones with
+// a comma, also go to "nous:tags"). Category is the name of the folder outline a feed sits
+// in, with nested folders joined by "/". Every other Source field is stored
+// in a "nous:" attribute so a list exported from Nous imports back unchanged;
+// other readers ignore those attributes. API keys are never exported.
+package opml
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"nous-app/internal/models"
+)
+
+// Namespace is the XML namespace of the "nous:" attributes.
+const Namespace = "urn:nous:opml"
+
+// Defaults for feeds imported from other readers.
+const (
+	DefaultParser     = "rss"
+	DefaultNormalizer = "rss"
+)
+
+type document struct {
+	XMLName xml.Name   `xml:"opml"`
+	Version string     `xml:"version,attr"`
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Head    head       `xml:"head"`
+	Body    body       `xml:"body"`
+}
+
+type head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type body struct {
+	Outlines []outline `xml:"outline"`
+}
+
+type outline struct {
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Outlines []outline  `xml:"outline"`
+}
+
+// attr returns the value of a plain attribute, matching the name
+// case-insensitively since readers disagree on "xmlUrl" vs "xmlurl".
+func (o outline) attr(name string) string {
+	for _, a := range o.Attrs {
+		if a.Name.Space == "" && strings.EqualFold(a.Name.Local, name) {
+			return strings.TrimSpace(a.Value)
+		}
+	}
+	return ""
+}
+
+// nous returns the "nous:" attributes of the outline by local name. Files
+// that use the prefix without declaring it are accepted too.
+func (o outline) nous() map[string]string {
+	out := map[string]string{}
+	for _, a := range o.Attrs {
+		if a.Name.Space == Namespace || a.Name.Space == "nous" {
+			out[a.Name.Local] = a.Value
+		}
+	}
+	return out
+}
+
+// ----------------------
+// Import
+// ----------------------
+
+// Parse reads the feeds of an OPML document. Outlines without an xmlUrl are
+// folders; their feeds get the folder path as Category unless the feed sets
+// one itself.
+func Parse(r io.Reader) ([]models.Source, error) {
+	var doc document
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	dec.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) { return input, nil }
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid OPML: %w", err)
+	}
+
+	var sources []models.Source
+	var walk func(outlines []outline, folder []string) error
+	walk = func(outlines []outline, folder []string) error {
+		for _, o := range outlines {
+			name := firstNonEmpty(o.attr("text"), o.attr("title"))
+			if o.attr("xmlUrl") == "" {
+				if err := walk(o.Outlines, append(folder[:len(folder):len(folder)], name)); err != nil {
+					return err
+				}
+				continue
+			}
+			src, err := source(o, name, folder)
+			if err != nil {
+				return err
+			}
+			sources = append(sources, src)
+		}
+		return nil
+	}
+	if err := walk(doc.Body.Outlines, nil); err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+func source(o outline, name string, folder []string) (models.Source, error) {
+	src := models.Source{
+		Name:       name,
+		Endpoint:   o.attr("xmlUrl"),
+		Parser:     DefaultParser,
+		Normalizer: DefaultNormalizer,
+	}
+	if src.Name == "" {
+		src.Name = src.Endpoint
+	}
+	if l := o.attr("language"); l != "" {
+		src.Language = &l
+	}
+	if path := strings.Join(nonEmpty(folder), "/"); path != "" {
+		src.Category = &path
+	}
+	src.Tags = splitTags(o.attr("category"))
+	if err := decodeNous(&src, o.nous()); err != nil {
+		return src, fmt.Errorf("feed %q: %w", src.Name, err)
+	}
+	return src, nil
+}
+
+// splitTags reads the tags of a comma-separated category attribute.
+func splitTags(category string) []string {
+	var tags []string
+	for _, t := range strings.Split(category, ",") {
+		// "/a/b" entries are category paths in OPML 2.0, not tags
+		if t = strings.TrimSpace(t); t != "" && !strings.HasPrefix(t, "/") {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// decodeNous applies the "nous:" attributes to src.
+func decodeNous(src *models.Source, attrs map[string]string) error {
+	for key, v := range attrs {
+		var err error
+		switch key {
+		case "parser":
+			src.Parser = v
+		case "normalizer":
+			src.Normalizer = v
+		case "category":
+			src.Category = &v
+		case "bias":
+			src.Bias = v
+		case "factuality":
+			src.Factuality = &v
+		case "region":
+			src.Region = &v
+		case "authType":
+			src.AuthType = &v
+		case "apiLink":
+			src.APILink = &v
+		case "instructions":
+			src.Instructions = &v
+		case "enabled":
+			src.Enabled, err = parseBool(v)
+		case "pinned":
+			src.Pinned, err = parseBool(v)
+		case "requiresApiKey":
+			src.RequiresAPIKey, err = parseBool(v)
+		case "rateLimitPerMinute":
+			src.RateLimitPerMin, err = parseInt(v)
+		case "refreshIntervalMinutes":
+			src.RefreshInterval, err = parseInt(v)
+		case "confidence":
+			var f float64
+			if f, err = strconv.ParseFloat(v, 64); err == nil {
+				src.Confidence = &f
+			}
+		case "tags":
+			err = json.Unmarshal([]byte(v), &src.Tags)
+		case "headers":
+			err = json.Unmarshal([]byte(v), &src.Headers)
+		case "ownership":
+			err = json.Unmarshal([]byte(v), &src.Ownership)
+		case "scrape":
+			err = json.Unmarshal([]byte(v), &src.Scrape)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid nous:%s %q", key, v)
+		}
+	}
+	return nil
+}
+
+func parseBool(s string) (*bool, error) {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func parseInt(s string) (*int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// ----------------------
+// Export
+// ----------------------
+
+// Write encodes sources as an OPML 2.0 document titled title. Feeds are
+// grouped into folder outlines by Category, folders and feeds sorted by
+// name; sources without an Endpoint are left out.
+func Write(w io.Writer, title string, sources []models.Source) error {
+	root := &folder{children: map[string]*folder{}}
+	for _, src := range sources {
+		if src.Endpoint == "" {
+			continue
+		}
+		f := root
+		if src.Category != nil {
+			for _, part := range strings.Split(*src.Category, "/") {
+				if part = strings.TrimSpace(part); part == "" {
+					continue
+				}
+				if f.children[part] == nil {
+					f.children[part] = &folder{children: map[string]*folder{}}
+				}
+				f = f.children[part]
+			}
+		}
+		f.feeds = append(f.feeds, feed(src))
+	}
+
+	doc := document{
+		Version: "2.0",
+		Attrs:   []xml.Attr{{Name: xml.Name{Local: "xmlns:nous"}, Value: Namespace}},
+		Head:    head{Title: title, DateCreated: time.Now().UTC().Format(time.RFC1123Z)},
+		Body:    body{Outlines: root.outlines()},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// folder is a Category level while exporting.
+type folder struct {
+	children map[string]*folder
+	feeds    []outline
+}
+
+func (f *folder) outlines() []outline {
+	names := make([]string, 0, len(f.children))
+	for name := range f.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []outline
+	for _, name := range names {
+		out = append(out, outline{
+			Attrs:    []xml.Attr{plain("text", name), plain("title", name)},
+			Outlines: f.children[name].outlines(),
+		})
+	}
+	feeds := append([]outline(nil), f.feeds...)
+	sort.SliceStable(feeds, func(i, j int) bool {
+		return strings.ToLower(feeds[i].attr("text")) < strings.ToLower(feeds[j].attr("text"))
+	})
+	return append(out, feeds...)
+}
+
+// feed is the outline of one source.
+func feed(src models.Source) outline {
+	o := outline{Attrs: []xml.Attr{
+		plain("type", "rss"),
+		plain("text", src.Name),
+		plain("title", src.Name),
+		plain("xmlUrl", src.Endpoint),
+	}}
+	if u, err := url.Parse(src.Endpoint); err == nil && u.Host != "" {
+		o.Attrs = append(o.Attrs, plain("htmlUrl", u.Scheme+"://"+u.Host))
+	}
+	if src.Language != nil && *src.Language != "" {
+		o.Attrs = append(o.Attrs, plain("language", *src.Language))
+	}
+	tags := nonEmpty(src.Tags)
+	if len(tags) > 0 {
+		o.Attrs = append(o.Attrs, plain("category", strings.Join(tags, ",")))
+	}
+
+	add := func(key, value string) {
+		o.Attrs = append(o.Attrs, xml.Attr{Name: xml.Name{Local: "nous:" + key}, Value: value})
+	}
+	addString := func(key string, v *string) {
+		if v != nil {
+			add(key, *v)
+		}
+	}
+	addJSON := func(key string, v interface{}) {
+		if data, err := json.Marshal(v); err == nil {
+			add(key, string(data))
+		}
+	}
+
+	add("parser", src.Parser)
+	add("normalizer", src.Normalizer)
+	if src.Category != nil && *src.Category != strings.Join(nonEmpty(strings.Split(*src.Category, "/")), "/") {
+		// The folders lose empty parts and surrounding spaces; keep it exact
+		add("category", *src.Category)
+	}
+	if len(tags) > 0 && !slices.Equal(splitTags(strings.Join(tags, ",")), tags) {
+		// Tags holding a comma or starting with "/" don't survive category
+		addJSON("tags", tags)
+	}
+	if src.Bias != "" {
+		add("bias", src.Bias)
+	}
+	addString("factuality", src.Factuality)
+	addString("region", src.Region)
+	addString("authType", src.AuthType)
+	addString("apiLink", src.APILink)
+	addString("instructions", src.Instructions)
+	if src.Enabled != nil {
+		add("enabled", strconv.FormatBool(*src.Enabled))
+	}
+	if src.Pinned != nil {
+		add("pinned", strconv.FormatBool(*src.Pinned))
+	}
+	if src.RequiresAPIKey != nil {
+		add("requiresApiKey", strconv.FormatBool(*src.RequiresAPIKey))
+	}
+	if src.RateLimitPerMin != nil {
+		add("rateLimitPerMinute", strconv.Itoa(*src.RateLimitPerMin))
+	}
+	if src.RefreshInterval != nil {
+		add("refreshIntervalMinutes", strconv.Itoa(*src.RefreshInterval))
+	}
+	if src.Confidence != nil {
+		add("confidence", strconv.FormatFloat(*src.Confidence, 'g', -1, 64))
+	}
+	if len(src.Headers) > 0 {
+		addJSON("headers", src.Headers)
+	}
+	if src.Ownership != nil {
+		addJSON("ownership", src.Ownership)
+	}
+	if src.Scrape != nil {
+		addJSON("scrape", src.Scrape)
+	}
+	return o
+}
+
+func plain(name, value string) xml.Attr {
+	return xml.Attr{Name: xml.Name{Local: name}, Value: value}
+}
+
+// ----------------------
+// Duplicates
+// ----------------------
+
+// EndpointKey is the comparison key of a feed URL: host without "www." and
+// path without a trailing slash, ignoring the scheme, so the same feed listed
+// as http and https, or with and without www, is recognised.
+func EndpointKey(endpoint string) string {
+	u, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil || u.Host == "" {
+		return strings.ToLower(strings.TrimSpace(endpoint))
+	}
+	key := strings.TrimPrefix(strings.ToLower(u.Host), "www.") + strings.TrimRight(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func nonEmpty(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
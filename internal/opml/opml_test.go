@@ -0,0 +1,156 @@
+package opml
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"nous-app/internal/models"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestRoundTrip(t *testing.T) {
+	sources := []models.Source{
+		{
+			Name:            "Example News",
+			Endpoint:        "https://example.com/feed.xml",
+			Parser:          "rss",
+			Normalizer:      "rss",
+			Language:        ptr("en"),
+			Category:        ptr("News/World"),
+			Tags:            []string{"world", "Washington, D.C.", "/not-a-path"},
+			Bias:            models.BiasLeanLeft,
+			Factuality:      ptr("high"),
+			Region:          ptr("US"),
+			AuthType:        ptr("bearerToken"),
+			APILink:         ptr("https://example.com/api"),
+			Instructions:    ptr(`Ask for a key at "example.com" & wait`),
+			Enabled:         ptr(false),
+			Pinned:          ptr(true),
+			RequiresAPIKey:  ptr(true),
+			RateLimitPerMin: ptr(30),
+			RefreshInterval: ptr(45),
+			Confidence:      ptr(0.75),
+			Headers:         map[string]string{"Accept-Language": "en"},
+			Ownership:       &models.Ownership{CompanyName: "Example Corp", Type: "private", Country: ptr("US")},
+		},
+		{
+			Name:       "Scraped",
+			Endpoint:   "https://blog.example.org/",
+			Parser:     "html",
+			Normalizer: "rss",
+			Category:   ptr(" Tech / Blogs "), // not a clean folder path, kept as nous:category
+			Scrape:     &models.ScrapeRules{Item: "article", Title: "h2", Link: "a@href"},
+		},
+		{
+			Name:       "Plain",
+			Endpoint:   "https://plain.example.net/rss",
+			Parser:     "rss",
+			Normalizer: "rss",
+			Tags:       []string{"a", "b"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "Test", sources); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := map[string]models.Source{}
+	for _, s := range got {
+		byName[s.Name] = s
+	}
+	if len(byName) != len(sources) {
+		t.Fatalf("got %d sources back, want %d", len(got), len(sources))
+	}
+	for _, want := range sources {
+		if g := byName[want.Name]; !reflect.DeepEqual(g, want) {
+			t.Errorf("%s came back as\n%+v\nwant\n%+v", want.Name, g, want)
+		}
+	}
+}
+
+func TestWriteTags(t *testing.T) {
+	var buf bytes.Buffer
+	sources := []models.Source{
+		{Name: "Plain", Endpoint: "https://a.example/rss", Tags: []string{"a", "b"}},
+		{Name: "Comma", Endpoint: "https://b.example/rss", Tags: []string{"x, y"}},
+	}
+	if err := Write(&buf, "Test", sources); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `category="a,b"`) {
+		t.Errorf("plain tags not written as category:\n%s", out)
+	}
+	if strings.Count(out, "nous:tags=") != 1 {
+		t.Errorf("want nous:tags for the comma tag only:\n%s", out)
+	}
+}
+
+func TestParseOtherReaders(t *testing.T) {
+	const doc = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.0">
+  <head><title>Subscriptions</title></head>
+  <body>
+    <outline text="Tech">
+      <outline text="Nested">
+        <outline type="rss" text="Deep" xmlurl="https://deep.example/rss" category="go, rust,/Tech/Nested"/>
+      </outline>
+      <outline type="rss" title="Only Title" xmlUrl="https://t.example/feed" language="de"/>
+    </outline>
+    <outline type="rss" xmlUrl="https://noname.example/feed"/>
+  </body>
+</opml>`
+
+	got, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.Source{
+		{Name: "Deep", Endpoint: "https://deep.example/rss", Parser: DefaultParser, Normalizer: DefaultNormalizer,
+			Category: ptr("Tech/Nested"), Tags: []string{"go", "rust"}},
+		{Name: "Only Title", Endpoint: "https://t.example/feed", Parser: DefaultParser, Normalizer: DefaultNormalizer,
+			Category: ptr("Tech"), Language: ptr("de")},
+		{Name: "https://noname.example/feed", Endpoint: "https://noname.example/feed", Parser: DefaultParser, Normalizer: DefaultNormalizer},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"not xml", "this is not opml"},
+		{"bad nous attribute", `<opml version="2.0" xmlns:nous="urn:nous:opml"><body>
+			<outline text="A" xmlUrl="https://a.example/rss" nous:enabled="maybe"/></body></opml>`},
+		{"bad nous json", `<opml version="2.0" xmlns:nous="urn:nous:opml"><body>
+			<outline text="A" xmlUrl="https://a.example/rss" nous:tags="[oops"/></body></opml>`},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(strings.NewReader(tt.doc)); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
+
+func TestEndpointKey(t *testing.T) {
+	same := []string{"https://www.example.com/feed/", "http://example.com/feed", "HTTPS://Example.com/feed"}
+	for _, e := range same[1:] {
+		if EndpointKey(e) != EndpointKey(same[0]) {
+			t.Errorf("EndpointKey(%q) = %q, want %q", e, EndpointKey(e), EndpointKey(same[0]))
+		}
+	}
+	if EndpointKey("https://example.com/other") == EndpointKey(same[0]) {
+		t.Error("different paths share a key")
+	}
+}
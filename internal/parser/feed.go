@@ -0,0 +1,460 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+
+	"nous-app/internal/models"
+)
+
+// XML namespaces understood by the feed parser.
+const (
+	nsAtom  = "http://www.w3.org/2005/Atom"
+	nsRSS10 = "http://purl.org/rss/1.0/"
+	nsDC    = "http://purl.org/dc/elements/1.1/"
+)
+
+// ParseFeed parses an RSS 2.0, Atom 1.0 or RSS 1.0 (RDF) feed. The format is
+// detected from the root element, and non-UTF-8 feeds are decoded according
+// to their XML encoding declaration.
+func ParseFeed(data []byte, src models.Source) ([]models.Article, error) {
+	d := newXMLDecoder(data)
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("feed has no root element")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid feed XML: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "rss":
+			var doc rssDoc
+			if err := d.DecodeElement(&doc, &start); err != nil {
+				return nil, fmt.Errorf("invalid RSS feed: %w", err)
+			}
+			return doc.articles(src), nil
+		case "feed":
+			var doc atomFeed
+			if err := d.DecodeElement(&doc, &start); err != nil {
+				return nil, fmt.Errorf("invalid Atom feed: %w", err)
+			}
+			return doc.articles(src), nil
+		case "RDF":
+			var doc rdfDoc
+			if err := d.DecodeElement(&doc, &start); err != nil {
+				return nil, fmt.Errorf("invalid RDF feed: %w", err)
+			}
+			return doc.articles(src), nil
+		default:
+			return nil, fmt.Errorf("unknown feed root element <%s>", start.Name.Local)
+		}
+	}
+}
+
+// newXMLDecoder returns a lenient decoder: HTML entities are tolerated and
+// declared charsets are converted to UTF-8. AutoClose is deliberately not set,
+// as HTML's void <link> would swallow the RSS <link> element.
+func newXMLDecoder(data []byte) *xml.Decoder {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+	d.CharsetReader = charset.NewReaderLabel
+	return d
+}
+
+// ----------------------
+// Shared elements
+// ----------------------
+
+// text is an element whose namespace matters, e.g. to tell <title> from
+// <media:title>, which encoding/xml would otherwise both map to "title".
+type text struct {
+	XMLName xml.Name
+	Type    string `xml:"type,attr"`
+	Value   string `xml:",chardata"`
+	Inner   string `xml:",innerxml"`
+}
+
+// String returns the element's text; XHTML content is returned as markup.
+func (t text) String() string {
+	if t.Type == "xhtml" {
+		return strings.TrimSpace(t.Inner)
+	}
+	return strings.TrimSpace(t.Value)
+}
+
+// pick returns the first non-empty value among elems in one of the given
+// namespaces, trying the namespaces in order.
+func pick(elems []text, spaces ...string) string {
+	for _, space := range spaces {
+		for _, e := range elems {
+			if e.XMLName.Space == space {
+				if v := e.String(); v != "" {
+					return v
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// mediaContent is <media:content> or <media:thumbnail>.
+type mediaContent struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Medium string `xml:"medium,attr"`
+}
+
+func (m mediaContent) isImage() bool {
+	return m.URL != "" && (m.Medium == "image" || strings.HasPrefix(m.Type, "image/") ||
+		(m.Medium == "" && m.Type == "" && looksLikeImage(m.URL)))
+}
+
+// media holds the Media RSS elements that can appear on an item or entry.
+type media struct {
+	Contents   []mediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnails []mediaContent `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	Groups     []struct {
+		Contents   []mediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+		Thumbnails []mediaContent `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	} `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+// image returns the best image: media:content, then media:thumbnail, then
+// the same inside media:group.
+func (m media) image() string {
+	for _, c := range m.Contents {
+		if c.isImage() {
+			return c.URL
+		}
+	}
+	for _, t := range m.Thumbnails {
+		if t.URL != "" {
+			return t.URL
+		}
+	}
+	for _, g := range m.Groups {
+		for _, c := range g.Contents {
+			if c.isImage() {
+				return c.URL
+			}
+		}
+		for _, t := range g.Thumbnails {
+			if t.URL != "" {
+				return t.URL
+			}
+		}
+	}
+	return ""
+}
+
+var imageExt = regexp.MustCompile(`(?i)\.(jpe?g|png|gif|webp|avif|svg)(\?|#|$)`)
+
+func looksLikeImage(u string) bool {
+	return imageExt.MatchString(u)
+}
+
+var imgTag = regexp.MustCompile(`(?i)<img[^>]+src\s*=\s*["']([^"']+)["']`)
+
+// firstImage returns the src of the first <img> in an HTML fragment.
+func firstImage(htmlText string) string {
+	if m := imgTag.FindStringSubmatch(htmlText); m != nil {
+		return html.UnescapeString(m[1])
+	}
+	return ""
+}
+
+var tags = regexp.MustCompile(`(?s)<[^>]*>`)
+
+// stripHTML reduces an HTML fragment to plain text with collapsed whitespace.
+func stripHTML(s string) string {
+	s = tags.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+// finishArticle fills the optional fields shared by all feed formats.
+func finishArticle(a *models.Article, base, content, summary, author, date, image string, categories []string) {
+	if content != "" {
+		a.Content = &content
+	}
+	if summary = stripHTML(summary); summary != "" {
+		a.Summary = &summary
+	} else if content != "" {
+		if s := stripHTML(content); s != "" {
+			a.Summary = &s
+		}
+	}
+	a.Author = strPtr(author)
+	a.PublishedAt = ParseDate(date)
+
+	if image == "" {
+		image = firstImage(content)
+	}
+	if image == "" {
+		image = firstImage(summary)
+	}
+	if image != "" {
+		image = resolveURL(base, image)
+		a.Image = &image
+	}
+
+	for _, c := range categories {
+		if c = strings.TrimSpace(c); c != "" {
+			a.Categories = append(a.Categories, c)
+		}
+	}
+}
+
+// ----------------------
+// RSS 2.0
+// ----------------------
+
+type rssDoc struct {
+	Channel struct {
+		Links    []text    `xml:"link"`
+		Language string    `xml:"language"`
+		DCLang   string    `xml:"http://purl.org/dc/elements/1.1/ language"`
+		Items    []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Titles       []text   `xml:"title"`
+	Links        []text   `xml:"link"`
+	Descriptions []text   `xml:"description"`
+	Encoded      string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	GUID         string   `xml:"guid"`
+	PubDate      string   `xml:"pubDate"`
+	Author       string   `xml:"author"`
+	DCCreator    string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	DCDate       string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Categories   []string `xml:"category"`
+	DCSubjects   []string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+	Enclosures   []struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+	media
+}
+
+func (doc rssDoc) articles(src models.Source) []models.Article {
+	ch := doc.Channel
+	base := baseURL(src.Endpoint, pick(ch.Links, ""))
+	lang := firstNonEmpty(ch.Language, ch.DCLang)
+
+	articles := make([]models.Article, 0, len(ch.Items))
+	for _, item := range ch.Items {
+		link := resolveURL(base, pick(item.Links, "", nsAtom))
+		guid := strings.TrimSpace(item.GUID)
+		if link == "" && strings.HasPrefix(guid, "http") {
+			link = guid
+		}
+
+		a := newArticle(src, link, stripHTML(pick(item.Titles, "", nsDC)), guid)
+		if a.Language == nil {
+			a.Language = strPtr(lang)
+		}
+
+		image := item.media.image()
+		if image == "" {
+			for _, enc := range item.Enclosures {
+				if strings.HasPrefix(enc.Type, "image/") || (enc.Type == "" && looksLikeImage(enc.URL)) {
+					image = enc.URL
+					break
+				}
+			}
+		}
+
+		description := pick(item.Descriptions, "")
+		content := firstNonEmpty(item.Encoded, description)
+		finishArticle(&a, base, content, description,
+			firstNonEmpty(item.DCCreator, item.Author),
+			firstNonEmpty(item.PubDate, item.DCDate),
+			image, append(item.Categories, item.DCSubjects...))
+
+		articles = append(articles, a)
+	}
+	return articles
+}
+
+// ----------------------
+// Atom 1.0
+// ----------------------
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"http://www.w3.org/2005/Atom name"`
+}
+
+type atomFeed struct {
+	Lang    string       `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Base    string       `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Links   []atomLink   `xml:"http://www.w3.org/2005/Atom link"`
+	Authors []atomPerson `xml:"http://www.w3.org/2005/Atom author"`
+	Entries []atomEntry  `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+type atomEntry struct {
+	Lang       string       `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Base       string       `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	ID         string       `xml:"http://www.w3.org/2005/Atom id"`
+	Titles     []text       `xml:"title"`
+	Links      []atomLink   `xml:"http://www.w3.org/2005/Atom link"`
+	Summaries  []text       `xml:"summary"`
+	Contents   []text       `xml:"content"`
+	Published  string       `xml:"http://www.w3.org/2005/Atom published"`
+	Updated    string       `xml:"http://www.w3.org/2005/Atom updated"`
+	Authors    []atomPerson `xml:"http://www.w3.org/2005/Atom author"`
+	DCCreator  string       `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories []struct {
+		Term  string `xml:"term,attr"`
+		Label string `xml:"label,attr"`
+	} `xml:"http://www.w3.org/2005/Atom category"`
+	media
+}
+
+// link returns the entry's alternate link, or the first link without a rel.
+func (e atomEntry) link() string {
+	for _, l := range e.Links {
+		if l.Rel == "alternate" && l.Href != "" {
+			return l.Href
+		}
+	}
+	for _, l := range e.Links {
+		if l.Rel == "" && l.Href != "" {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// enclosure returns the first image linked with rel="enclosure".
+func (e atomEntry) enclosure() string {
+	for _, l := range e.Links {
+		if l.Rel == "enclosure" && (strings.HasPrefix(l.Type, "image/") || looksLikeImage(l.Href)) {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+func (feed atomFeed) articles(src models.Source) []models.Article {
+	feedBase := baseURL(src.Endpoint, feed.Base)
+	for _, l := range feed.Links {
+		if l.Rel == "alternate" && l.Href != "" && feed.Base == "" {
+			feedBase = baseURL(feedBase, l.Href)
+			break
+		}
+	}
+
+	articles := make([]models.Article, 0, len(feed.Entries))
+	for _, entry := range feed.Entries {
+		base := baseURL(feedBase, entry.Base)
+		link := resolveURL(base, entry.link())
+		id := strings.TrimSpace(entry.ID)
+
+		a := newArticle(src, link, stripHTML(pick(entry.Titles, nsAtom)), id)
+		if a.Language == nil {
+			a.Language = strPtr(firstNonEmpty(entry.Lang, feed.Lang))
+		}
+
+		author := entry.DCCreator
+		for _, p := range append(entry.Authors, feed.Authors...) {
+			if author == "" {
+				author = p.Name
+			}
+		}
+
+		image := entry.media.image()
+		if image == "" {
+			image = entry.enclosure()
+		}
+
+		var categories []string
+		for _, c := range entry.Categories {
+			categories = append(categories, firstNonEmpty(c.Label, c.Term))
+		}
+
+		content := pick(entry.Contents, nsAtom)
+		finishArticle(&a, base, content, pick(entry.Summaries, nsAtom), author,
+			firstNonEmpty(entry.Published, entry.Updated), image, categories)
+
+		articles = append(articles, a)
+	}
+	return articles
+}
+
+// ----------------------
+// RSS 1.0 (RDF)
+// ----------------------
+
+type rdfDoc struct {
+	Channel struct {
+		Links  []text `xml:"http://purl.org/rss/1.0/ link"`
+		DCLang string `xml:"http://purl.org/dc/elements/1.1/ language"`
+	} `xml:"http://purl.org/rss/1.0/ channel"`
+	Items []rdfItem `xml:"http://purl.org/rss/1.0/ item"`
+}
+
+type rdfItem struct {
+	About        string   `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Titles       []text   `xml:"title"`
+	Links        []text   `xml:"link"`
+	Descriptions []text   `xml:"description"`
+	Encoded      string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	DCCreator    string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	DCDate       string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	DCSubjects   []string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+	media
+}
+
+func (doc rdfDoc) articles(src models.Source) []models.Article {
+	base := baseURL(src.Endpoint, pick(doc.Channel.Links, nsRSS10))
+
+	articles := make([]models.Article, 0, len(doc.Items))
+	for _, item := range doc.Items {
+		link := resolveURL(base, firstNonEmpty(pick(item.Links, nsRSS10), item.About))
+
+		a := newArticle(src, link, stripHTML(pick(item.Titles, nsRSS10, nsDC)), item.About)
+		if a.Language == nil {
+			a.Language = strPtr(doc.Channel.DCLang)
+		}
+
+		description := pick(item.Descriptions, nsRSS10, nsDC)
+		finishArticle(&a, base, firstNonEmpty(item.Encoded, description), description,
+			item.DCCreator, item.DCDate, item.media.image(), item.DCSubjects)
+
+		articles = append(articles, a)
+	}
+	return articles
+}
+
+// firstNonEmpty returns the first argument that is not blank, trimmed.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"nous-app/internal/models"
+)
+
+func TestParseFeedRSS(t *testing.T) {
+	src := models.Source{Name: "Example", Parser: "rss", Endpoint: "https://news.example.com/rss.xml"}
+	articles, err := ParseFeed(fixture(t, "rss.xml"), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkArticles(t, articles, []want{
+		{
+			ID: "https://news.example.com/local/bridge", Title: "Council approves & funds new bridge",
+			URL: "https://news.example.com/local/bridge", Summary: "The city council voted on Monday.",
+			Image: "https://cdn.example.com/bridge.jpg", Author: "Jane Doe",
+			PublishedAt: "2003-06-10T09:00:00Z", Language: "en-us", Categories: []string{"Local"},
+		},
+		{
+			// The guid is the link, the audio enclosure is no image
+			ID: "https://news.example.com/guid-only", Title: "Only a permalink",
+			URL: "https://news.example.com/guid-only", Summary: "Has an inline image.",
+			Image: "https://news.example.com/img/inline.png", PublishedAt: "2024-03-01T07:30:00Z",
+			Language: "en-us",
+		},
+	})
+	if c := deref(articles[0].Content); !strings.Contains(c, "Work starts in spring.") {
+		t.Errorf("content:encoded not used as content: %q", c)
+	}
+}
+
+func TestParseFeedAtom(t *testing.T) {
+	src := models.Source{Name: "Blog", Parser: "atom", Endpoint: "https://blog.example.org/atom.xml"}
+	articles, err := ParseFeed(fixture(t, "atom.xml"), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkArticles(t, articles, []want{
+		{
+			ID: "https://blog.example.org/posts/first", Title: "First & foremost",
+			URL: "https://blog.example.org/posts/first", Summary: "A short summary.",
+			Image: "https://blog.example.org/img/first.png", Author: "Blog Team",
+			PublishedAt: "2024-01-02T10:00:00Z", Language: "en", Categories: []string{"News", "go"},
+		},
+		{
+			// xml:base and xml:lang of the entry win over the feed's
+			ID: "https://other.example.net/de/zweiter", Title: "Zweiter Beitrag",
+			URL: "https://other.example.net/de/zweiter", Summary: "Inhalt",
+			Image: "https://other.example.net/thumb.jpg", Author: "Max Muster",
+			PublishedAt: "2024-01-04T07:15:00Z", Language: "de",
+		},
+	})
+	if c := deref(articles[0].Content); !strings.Contains(c, "<p>Full <em>text</em>.</p>") {
+		t.Errorf("XHTML content not kept as markup: %q", c)
+	}
+}
+
+func TestParseFeedRDF(t *testing.T) {
+	articles, err := ParseFeed(fixture(t, "rdf.xml"), models.Source{Name: "RDF", Parser: "rdf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkArticles(t, articles, []want{{
+		ID: "https://rdf.example.com/items/1", Title: "RDF item", URL: "https://rdf.example.com/items/1",
+		Summary: "Item description.", Author: "Taro", PublishedAt: "2024-02-10T03:00:00Z",
+		Language: "ja", Categories: []string{"tech"},
+	}})
+}
+
+func TestParseFeedCharset(t *testing.T) {
+	doc := "\xef\xbb\xbf<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>" +
+		"<rss><channel><item><title>Caf\xe9 &eacute;t\xe9</title><link>https://a.example/1</link></item></channel></rss>"
+	articles, err := ParseFeed([]byte(doc), models.Source{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 1 || articles[0].Title != "Café été" {
+		t.Errorf("got %+v, want one article titled Café été", articles)
+	}
+}
+
+func TestParseFeedErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"empty", ""},
+		{"not xml", "{}"},
+		{"html", "<html><body>Not a feed</body></html>"},
+		{"broken", "<rss><channel><item><title>A</item>"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseFeed([]byte(tt.doc), models.Source{}); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+
+	"nous-app/internal/css"
+	"nous-app/internal/models"
+)
+
+// ----------------------
+// HTML scraping
+// ----------------------
+
+// ScrapeResult is the outcome of applying a source's ScrapeRules to a page.
+type ScrapeResult struct {
+	Matched  int              `json:"matched"`  // Elements matched by the item selector
+	Articles []models.Article `json:"articles"` // Items with a title or link, in page order
+}
+
+// ParseHTML extracts articles from an HTML page using src.Scrape.
+func ParseHTML(data []byte, src models.Source) ([]models.Article, error) {
+	res, err := Scrape(data, src)
+	if err != nil {
+		return nil, err
+	}
+	return res.Articles, nil
+}
+
+// Scrape applies src.Scrape to an HTML page. Links and images are resolved
+// against the page's <base href>, or src.Endpoint. Items with neither a title
+// nor a link are dropped.
+func Scrape(data []byte, src models.Source) (*ScrapeResult, error) {
+	if src.Scrape == nil {
+		return nil, fmt.Errorf("source %q has no scrape rules", src.Name)
+	}
+	rules, err := compileRules(*src.Scrape)
+	if err != nil {
+		return nil, err
+	}
+
+	enc, _, _ := charset.DetermineEncoding(data, "text/html")
+	doc, err := html.Parse(enc.NewDecoder().Reader(bytes.NewReader(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTML: %w", err)
+	}
+
+	base := src.Endpoint
+	if b := baseElement.MatchFirst(doc); b != nil {
+		base = baseURL(base, css.Attr(b, "href"))
+	}
+	lang := ""
+	if h := htmlLang.MatchFirst(doc); h != nil {
+		lang = strings.SplitN(css.Attr(h, "lang"), "-", 2)[0]
+	}
+
+	items := rules.item.MatchAll(doc)
+	res := &ScrapeResult{Matched: len(items), Articles: make([]models.Article, 0, len(items))}
+
+	for _, item := range items {
+		link := rules.link.extract(item)
+		if link != "" {
+			link = resolveURL(base, link)
+		}
+		title := rules.title.extract(item)
+		if title == "" {
+			if a := rules.link.node(item); a != nil {
+				title = css.Text(linkElement(a))
+			}
+		}
+		if title == "" && link == "" {
+			continue
+		}
+
+		a := newArticle(src, link, title, "")
+		finishArticle(&a, base, "", rules.summary.extract(item), rules.author.extract(item), "", rules.image.extract(item), nil)
+
+		if date := rules.date.extract(item); date != "" {
+			if src.Scrape.DateLayout != "" {
+				if t, err := time.ParseInLocation(src.Scrape.DateLayout, date, time.UTC); err == nil {
+					iso := t.UTC().Format(time.RFC3339)
+					a.PublishedAt = &iso
+				}
+			} else {
+				a.PublishedAt = ParseDate(date)
+			}
+		}
+		if a.Language == nil {
+			a.Language = strPtr(lang)
+		}
+		res.Articles = append(res.Articles, a)
+	}
+	return res, nil
+}
+
+// compiledRules are ScrapeRules with their selectors compiled.
+type compiledRules struct {
+	item                                      *css.Selector
+	title, link, date, summary, image, author rule
+}
+
+func compileRules(r models.ScrapeRules) (*compiledRules, error) {
+	if strings.TrimSpace(r.Item) == "" {
+		return nil, fmt.Errorf("scrape rules need an item selector")
+	}
+	item, err := css.Compile(r.Item)
+	if err != nil {
+		return nil, fmt.Errorf("item: %w", err)
+	}
+
+	c := &compiledRules{item: item}
+	for _, f := range []struct {
+		name, spec string
+		kind       ruleKind
+		dst        *rule
+	}{
+		{"title", r.Title, textRule, &c.title},
+		{"link", r.Link, linkRule, &c.link},
+		{"date", r.Date, dateRule, &c.date},
+		{"summary", r.Summary, textRule, &c.summary},
+		{"image", r.Image, imageRule, &c.image},
+		{"author", r.Author, textRule, &c.author},
+	} {
+		rl, err := parseRule(f.spec, f.kind)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		*f.dst = rl
+	}
+	return c, nil
+}
+
+type ruleKind int
+
+const (
+	textRule ruleKind = iota
+	linkRule
+	dateRule
+	imageRule
+)
+
+// rule is one field selector, optionally reading an attribute ("sel@attr").
+// A rule with no selector applies to the item element itself, except text
+// rules, which are then disabled (title falls back to the link text).
+type rule struct {
+	kind ruleKind
+	sel  *css.Selector
+	attr string
+	set  bool
+}
+
+func parseRule(spec string, kind ruleKind) (rule, error) {
+	r := rule{kind: kind}
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		r.set = kind != textRule
+		return r, nil
+	}
+	r.set = true
+	if i := strings.LastIndexByte(spec, '@'); i >= 0 && !strings.ContainsAny(spec[i:], "]) ") {
+		r.attr = strings.TrimSpace(spec[i+1:])
+		spec = strings.TrimSpace(spec[:i])
+	}
+	if spec != "" {
+		sel, err := css.Compile(spec)
+		if err != nil {
+			return r, err
+		}
+		r.sel = sel
+	}
+	return r, nil
+}
+
+// node returns the element the rule selects within item (or item itself if
+// the selector matches it), or nil.
+func (r rule) node(item *html.Node) *html.Node {
+	if !r.set {
+		return nil
+	}
+	if r.sel == nil {
+		return item
+	}
+	if n := r.sel.MatchFirst(item); n != nil {
+		return n
+	}
+	if r.sel.Match(item) {
+		return item
+	}
+	return nil
+}
+
+// extract returns the rule's value within item, or "".
+func (r rule) extract(item *html.Node) string {
+	n := r.node(item)
+	if n == nil {
+		return ""
+	}
+	if r.attr != "" {
+		return strings.TrimSpace(css.Attr(n, r.attr))
+	}
+
+	switch r.kind {
+	case linkRule:
+		if a := linkElement(n); a != nil {
+			return strings.TrimSpace(css.Attr(a, "href"))
+		}
+		return ""
+	case imageRule:
+		return imageSource(n)
+	case dateRule:
+		if v := css.Attr(n, "datetime"); v != "" {
+			return strings.TrimSpace(v)
+		}
+		if t := timeElement.MatchFirst(n); t != nil {
+			return strings.TrimSpace(css.Attr(t, "datetime"))
+		}
+	}
+	return css.Text(n)
+}
+
+var (
+	anchor      = css.MustCompile("a[href]")
+	image       = css.MustCompile("img, picture source")
+	timeElement = css.MustCompile("time[datetime]")
+	baseElement = css.MustCompile("base[href]")
+	htmlLang    = css.MustCompile("html[lang]")
+)
+
+// linkElement returns n if it is a link, else the first link inside it, else
+// the link wrapping it.
+func linkElement(n *html.Node) *html.Node {
+	if anchor.Match(n) {
+		return n
+	}
+	if a := anchor.MatchFirst(n); a != nil {
+		return a
+	}
+	return anchor.Closest(n)
+}
+
+// imageSource returns the source of n if it is an image, else of the first
+// image inside it. Lazy-loading attributes are preferred over src, which often
+// holds a placeholder.
+func imageSource(n *html.Node) string {
+	img := n
+	if n.Data != "img" && n.Data != "source" {
+		if img = image.MatchFirst(n); img == nil {
+			return ""
+		}
+	}
+	for _, name := range []string{"data-src", "data-lazy-src", "data-original", "src"} {
+		if v := strings.TrimSpace(css.Attr(img, name)); v != "" && !strings.HasPrefix(v, "data:") {
+			return v
+		}
+	}
+	for _, name := range []string{"srcset", "data-srcset"} {
+		if f := strings.Fields(strings.Split(css.Attr(img, name), ",")[0]); len(f) > 0 {
+			return f[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"nous-app/internal/models"
+)
+
+// Generic JSON APIs, the default parser. Mirrors the TS jsonParser: the
+// payload is an object with an "articles" array (the NewsAPI layout), or a
+// bare array of articles.
+
+type jsonArticle struct {
+	ID            string   `json:"id"`
+	GUID          string   `json:"guid"`
+	Title         string   `json:"title"`
+	URL           string   `json:"url"`
+	Link          string   `json:"link"`
+	Content       string   `json:"content"`
+	Summary       string   `json:"summary"`
+	Description   string   `json:"description"`
+	ImageURL      string   `json:"imageUrl"`
+	URLToImage    string   `json:"urlToImage"` // NewsAPI
+	Image         string   `json:"image"`
+	Categories    []string `json:"categories"`
+	Tags          []string `json:"tags"`
+	Language      string   `json:"language"`
+	Author        string   `json:"author"`
+	PublishedAt   string   `json:"publishedAt"`
+	PublishedDate string   `json:"published_date"`
+	Edition       string   `json:"edition"`
+}
+
+// ParseJSON parses a generic JSON article list.
+func ParseJSON(data []byte, src models.Source) ([]models.Article, error) {
+	var items []json.RawMessage
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, fmt.Errorf("invalid JSON article list: %w", err)
+		}
+	} else {
+		var doc struct {
+			Articles []json.RawMessage `json:"articles"`
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid JSON article list: %w", err)
+		}
+		if doc.Articles == nil {
+			return nil, fmt.Errorf("invalid JSON article list: no \"articles\" array")
+		}
+		items = doc.Articles
+	}
+
+	articles := make([]models.Article, 0, len(items))
+	for _, raw := range items {
+		var item jsonArticle
+		if err := json.Unmarshal(raw, &item); err != nil {
+			continue // skip malformed items rather than dropping the whole list
+		}
+
+		link := resolveURL(src.Endpoint, firstNonEmpty(item.URL, item.Link))
+		a := newArticle(src, link, stripHTML(item.Title), firstNonEmpty(item.ID, item.GUID))
+		a.Raw = raw
+		if item.Language != "" {
+			lang := item.Language
+			a.Language = &lang
+		}
+		if item.Edition != "" {
+			if ed := models.Edition(strings.ToLower(item.Edition)); validEdition(ed) {
+				a.Edition = &ed
+			}
+		}
+
+		finishArticle(&a, src.Endpoint, item.Content, firstNonEmpty(item.Summary, item.Description),
+			item.Author, firstNonEmpty(item.PublishedAt, item.PublishedDate),
+			firstNonEmpty(item.ImageURL, item.URLToImage, item.Image), item.Categories)
+		a.Tags = item.Tags
+
+		articles = append(articles, a)
+	}
+	return articles, nil
+}
+
+// validEdition reports whether ed is one of the known editions.
+func validEdition(ed models.Edition) bool {
+	switch ed {
+	case models.EditionUS, models.EditionUK, models.EditionKR, models.EditionCN,
+		models.EditionInternational, models.EditionOther:
+		return true
+	}
+	return false
+}
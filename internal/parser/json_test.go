@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"testing"
+
+	"nous-app/internal/models"
+)
+
+func TestParseJSON(t *testing.T) {
+	src := models.Source{Name: "Wire", Parser: "json", Endpoint: "https://wire.example.com/v2/top"}
+	articles, err := ParseJSON(fixture(t, "newsapi.json"), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The number in the list is skipped
+	checkArticles(t, articles, []want{
+		{
+			ID: "https://wire.example.com/markets", Title: "Markets rally", URL: "https://wire.example.com/markets",
+			Summary: "Stocks rose sharply on Friday.", Image: "https://wire.example.com/markets.jpg",
+			Author: "Wire Staff", PublishedAt: "2024-06-01T14:00:00Z",
+		},
+		{
+			ID: "https://wire.example.com/v2/stories/7", Title: "Relative link",
+			URL: "https://wire.example.com/v2/stories/7", Summary: "Summary wins over description.",
+			PublishedAt: "2024-06-01T16:00:00Z", Language: "en", Categories: []string{"Business"},
+		},
+	})
+	second := articles[1]
+	if second.Edition == nil || *second.Edition != models.EditionUK {
+		t.Errorf("Edition = %v, want uk", second.Edition)
+	}
+	if len(second.Tags) != 2 || second.Raw == nil {
+		t.Errorf("Tags %v, Raw %v", second.Tags, second.Raw)
+	}
+}
+
+func TestParseJSONBareArray(t *testing.T) {
+	doc := `[{"guid": "g-1", "title": "No link", "edition": "mars"}]`
+	articles, err := ParseJSON([]byte(doc), models.Source{Name: "Bare"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 1 || articles[0].ID != "g-1" || articles[0].Edition != nil {
+		t.Errorf("got %+v, want one article g-1 without an edition", articles)
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"not json", "<rss/>"},
+		{"no articles", `{"status": "error", "message": "apiKey missing"}`},
+		{"broken array", `[{"title": "A"`},
+	}
+	for _, tt := range tests {
+		if _, err := ParseJSON([]byte(tt.doc), models.Source{}); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"nous-app/internal/models"
+)
+
+// JSON Feed 1.0 and 1.1 (https://jsonfeed.org/version/1.1). Fields deprecated
+// in 1.1 (author) are still read so 1.0 feeds keep working.
+
+type jsonFeed struct {
+	Version     string            `json:"version"`
+	HomePageURL string            `json:"home_page_url"`
+	FeedURL     string            `json:"feed_url"`
+	Language    string            `json:"language"`
+	Authors     []jsonFeedAuthor  `json:"authors"`
+	Author      *jsonFeedAuthor   `json:"author"`
+	Items       []json.RawMessage `json:"items"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type jsonFeedItem struct {
+	ID            json.RawMessage      `json:"id"` // string in 1.1, sometimes a number in the wild
+	URL           string               `json:"url"`
+	ExternalURL   string               `json:"external_url"`
+	Title         string               `json:"title"`
+	ContentHTML   string               `json:"content_html"`
+	ContentText   string               `json:"content_text"`
+	Summary       string               `json:"summary"`
+	Image         string               `json:"image"`
+	BannerImage   string               `json:"banner_image"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Authors       []jsonFeedAuthor     `json:"authors"`
+	Author        *jsonFeedAuthor      `json:"author"`
+	Tags          []string             `json:"tags"`
+	Language      string               `json:"language"`
+	Attachments   []jsonFeedAttachment `json:"attachments"`
+}
+
+type jsonFeedAttachment struct {
+	URL      string `json:"url"`
+	MimeType string `json:"mime_type"`
+	Title    string `json:"title"`
+}
+
+// ParseJSONFeed parses a JSON Feed (version 1.0 or 1.1).
+func ParseJSONFeed(data []byte, src models.Source) ([]models.Article, error) {
+	var feed jsonFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("invalid JSON Feed: %w", err)
+	}
+	if !strings.HasPrefix(feed.Version, "https://jsonfeed.org/version/") {
+		return nil, fmt.Errorf("not a JSON Feed: unexpected version %q", feed.Version)
+	}
+
+	base := baseURL(baseURL(src.Endpoint, feed.FeedURL), feed.HomePageURL)
+	feedAuthor := authorName(feed.Authors, feed.Author)
+
+	articles := make([]models.Article, 0, len(feed.Items))
+	for _, raw := range feed.Items {
+		var item jsonFeedItem
+		if err := json.Unmarshal(raw, &item); err != nil {
+			continue // skip malformed items rather than dropping the whole feed
+		}
+
+		// Titles are optional in JSON Feed (microblog posts); use the start of the text instead
+		title := stripHTML(item.Title)
+		if title == "" {
+			title = truncate(stripHTML(firstNonEmpty(item.Summary, item.ContentText, item.ContentHTML)), 80)
+		}
+
+		link := resolveURL(base, firstNonEmpty(item.URL, item.ExternalURL))
+		a := newArticle(src, link, title, jsonFeedID(item.ID))
+		a.Raw = raw
+		if lang := firstNonEmpty(item.Language, feed.Language); lang != "" {
+			a.Language = &lang
+		}
+
+		content := item.ContentHTML
+		if content == "" {
+			content = item.ContentText
+		}
+
+		image := firstNonEmpty(item.Image, item.BannerImage)
+		if image == "" {
+			for _, att := range item.Attachments {
+				if strings.HasPrefix(att.MimeType, "image/") {
+					image = att.URL
+					break
+				}
+			}
+		}
+
+		author := authorName(item.Authors, item.Author)
+		if author == "" {
+			author = feedAuthor
+		}
+
+		finishArticle(&a, base, content, item.Summary, author,
+			firstNonEmpty(item.DatePublished, item.DateModified), image, item.Tags)
+
+		articles = append(articles, a)
+	}
+	return articles, nil
+}
+
+// jsonFeedID returns an item id given as a JSON string or number.
+func jsonFeedID(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strings.TrimSpace(s)
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String()
+	}
+	return ""
+}
+
+// authorName joins the names of a 1.1 authors list, falling back to the 1.0 author.
+func authorName(authors []jsonFeedAuthor, legacy *jsonFeedAuthor) string {
+	var names []string
+	for _, a := range authors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 && legacy != nil {
+		return strings.TrimSpace(legacy.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// truncate shortens s to at most n runes, cutting at a word boundary and
+// adding an ellipsis.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	cut := string(runes[:n])
+	if i := strings.LastIndex(cut, " "); i > n/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + "…"
+}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"testing"
+
+	"nous-app/internal/models"
+)
+
+func TestParseJSONFeed(t *testing.T) {
+	src := models.Source{Name: "JF", Parser: "jsonfeed", Endpoint: "https://jf.example.com/feed.json"}
+	articles, err := ParseJSONFeed(fixture(t, "jsonfeed.json"), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "A microblog post without a title that goes on for a while so it has to be cut short somewhere."
+	checkArticles(t, articles, []want{
+		{
+			ID: "https://jf.example.com/posts/1", Title: "Hello world", URL: "https://jf.example.com/posts/1",
+			Summary: "The first post.", Image: "https://jf.example.com/img/1.jpg", Author: "Ann, Bob",
+			PublishedAt: "2024-05-01T19:00:00Z", Language: "en", Categories: []string{"intro", "meta"},
+		},
+		{
+			// No title: the start of the text. The first image attachment
+			// and the feed's author stand in for the missing fields.
+			ID:    "https://elsewhere.example.org/story",
+			Title: "A microblog post without a title that goes on for a while so it has to be cut…",
+			URL:   "https://elsewhere.example.org/story", Summary: text, Image: "https://jf.example.com/b.png",
+			Author: "Feed Author", PublishedAt: "2024-05-02T08:00:00Z", Language: "fr",
+		},
+	})
+}
+
+func TestParseJSONFeedErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"not json", "<rss/>"},
+		{"no version", `{"items": []}`},
+		{"other version", `{"version": "1.0", "items": []}`},
+	}
+	for _, tt := range tests {
+		if _, err := ParseJSONFeed([]byte(tt.doc), models.Source{}); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
+
+func TestJSONFeedID(t *testing.T) {
+	src := models.Source{Name: "JF"}
+	doc := `{"version": "https://jsonfeed.org/version/1", "items": [{"id": " abc ", "title": "A"}, {"id": 7, "title": "B"}]}`
+	articles, err := ParseJSONFeed([]byte(doc), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 2 || articles[0].ID != "abc" || articles[1].ID != "7" {
+		t.Errorf("got %+v, want the ids abc and 7", articles)
+	}
+}
@@ -0,0 +1,289 @@
+// Package parser turns the raw payload fetched for a Source into Articles on
+// the Go side, mirroring the TypeScript parsers in backend/src/lib/parsers so
+// feeds can be ingested without the Node runtime.
+//
+// Parsers are looked up by Source.Parser; see Get and Parse.
+package parser
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"nous-app/internal/gdelt"
+	"nous-app/internal/hn"
+	"nous-app/internal/models"
+	"nous-app/internal/reddit"
+)
+
+// Func parses a raw payload fetched for src into articles.
+type Func func(data []byte, src models.Source) ([]models.Article, error)
+
+// ErrUnsupported is returned by Parse for a Source.Parser with no Go parser.
+var ErrUnsupported = errors.New("unsupported parser")
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Func{
+		"rss":  ParseFeed,
+		"atom": ParseFeed,
+		"rdf":  ParseFeed,
+		"xml":  ParseFeed,
+
+		"json":     ParseJSON,
+		"html":     ParseHTML,
+		"jsonfeed": ParseJSONFeed,
+		"gdelt":    parseGDELT,
+		"hn":       parseHN,
+		"reddit":   parseReddit,
+	}
+)
+
+// Register makes fn available under name, replacing any existing parser.
+func Register(name string, fn Func) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = fn
+}
+
+// Get returns the parser registered under name.
+func Get(name string) (Func, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	fn, ok := registry[name]
+	return fn, ok
+}
+
+// Parse parses data with the parser named by src.Parser ("json" if empty).
+func Parse(data []byte, src models.Source) ([]models.Article, error) {
+	name := src.Parser
+	if name == "" {
+		name = "json"
+	}
+	fn, ok := Get(name)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnsupported, name)
+	}
+	return fn(data, src)
+}
+
+// parseGDELT parses a GDELT DOC 2.0 artlist response.
+func parseGDELT(data []byte, src models.Source) ([]models.Article, error) {
+	items, err := gdelt.ParseArtList(data)
+	if err != nil {
+		return nil, err
+	}
+	return gdelt.ToArticles(items, src), nil
+}
+
+// parseHN parses a JSON array of Hacker News items. Story lists hold only IDs
+// and are fetched with hn.FetchSource instead.
+func parseHN(data []byte, src models.Source) ([]models.Article, error) {
+	items, err := hn.ParseItems(data)
+	if err != nil {
+		return nil, err
+	}
+	return hn.ToArticles(items, src), nil
+}
+
+// parseReddit parses a single page of a subreddit listing.
+func parseReddit(data []byte, src models.Source) ([]models.Article, error) {
+	posts, _, err := reddit.ParseListing(data)
+	if err != nil {
+		return nil, err
+	}
+	return reddit.ToArticles(posts, src), nil
+}
+
+// ----------------------
+// Article helpers
+// ----------------------
+
+// newArticle returns an Article for src with the fields every parser fills the
+// same way: ID, source metadata, language and edition.
+func newArticle(src models.Source, link, title, guid string) models.Article {
+	if title == "" {
+		title = "Untitled"
+	}
+	a := models.Article{
+		ID:         articleID(src, link, guid, title),
+		Title:      title,
+		URL:        link,
+		Parser:     src.Parser,
+		Normalizer: src.Normalizer,
+		Confidence: src.Confidence,
+		Language:   src.Language,
+		SourceMeta: &models.SourceMeta{
+			Name:       src.Name,
+			Bias:       src.Bias,
+			Confidence: src.Confidence,
+		},
+	}
+	if src.Name != "" {
+		a.Source = strPtr(src.Name)
+	}
+	if u, err := url.Parse(link); err == nil && u.Host != "" {
+		a.SourceDomain = strPtr(strings.TrimPrefix(u.Hostname(), "www."))
+	}
+	if src.Category != nil {
+		a.SourceType = src.Category
+	}
+	if src.Region != nil {
+		a.SourceCountry = src.Region
+		edition := editionFor(*src.Region)
+		a.Edition = &edition
+	}
+	return a
+}
+
+// articleID uses the link, then the guid, and falls back to a hash of the
+// source and title, matching the TS parsers ("link ?? guid").
+func articleID(src models.Source, link, guid, title string) string {
+	if link != "" {
+		return link
+	}
+	if guid != "" {
+		return guid
+	}
+	sum := sha1.Sum([]byte(src.Name + "\x00" + title))
+	return hex.EncodeToString(sum[:])
+}
+
+// editionFor maps a region code to an Edition.
+func editionFor(region string) models.Edition {
+	switch strings.ToLower(strings.TrimSpace(region)) {
+	case "us", "usa":
+		return models.EditionUS
+	case "uk", "gb":
+		return models.EditionUK
+	case "kr":
+		return models.EditionKR
+	case "cn":
+		return models.EditionCN
+	case "international", "global", "world":
+		return models.EditionInternational
+	default:
+		return models.EditionOther
+	}
+}
+
+// strPtr returns a pointer to s, or nil if s is empty after trimming.
+func strPtr(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// resolveURL resolves ref against base, returning ref unchanged if either
+// does not parse.
+func resolveURL(base, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || base == "" {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil || r.IsAbs() {
+		return ref
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
+
+// baseURL returns the base for resolving links: ref resolved against parent,
+// or parent itself when ref is empty.
+func baseURL(parent, ref string) string {
+	if strings.TrimSpace(ref) == "" {
+		return parent
+	}
+	return resolveURL(parent, ref)
+}
+
+// ----------------------
+// Dates
+// ----------------------
+
+// dateLayouts are the formats seen in the wild for feed dates, most common first.
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339Nano,
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 02 Jan 2006 15:04:05 -0700 (MST)",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"Mon, 2 Jan 2006 15:04 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	"Mon, 02 Jan 06 15:04:05 -0700",
+	"Monday, 02-Jan-06 15:04:05 MST",
+	time.RFC850,
+	time.ANSIC,
+	time.UnixDate,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"20060102T150405Z",
+	"2006-01-02",
+}
+
+// rfc822Zones are the zone names RFC 822 defines, with their offsets in
+// hours; ParseDate reads its "UT" as UTC. time.Parse only knows an
+// abbreviation's offset when the local zone uses it and reads the rest as
+// UTC, which would put EST dates five hours off.
+var rfc822Zones = map[string]int{
+	"GMT": 0,
+	"EST": -5, "EDT": -4,
+	"CST": -6, "CDT": -5,
+	"MST": -7, "MDT": -6,
+	"PST": -8, "PDT": -7,
+}
+
+// ParseDate parses a date in any layout seen in feeds and returns it as an
+// RFC3339 UTC string, or nil if it cannot be parsed.
+func ParseDate(s string) *string {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return nil
+	}
+	// time.Parse wants at least three letters in a zone name
+	if strings.HasSuffix(s, " UT") {
+		s += "C"
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			iso := withRFC822Zone(t, layout).UTC().Format(time.RFC3339)
+			return &iso
+		}
+	}
+	return nil
+}
+
+// withRFC822Zone gives t the offset of its RFC 822 zone name when layout took
+// the zone from a name alone, not from a numeric offset.
+func withRFC822Zone(t time.Time, layout string) time.Time {
+	if !strings.Contains(layout, "MST") || strings.Contains(layout, "-07") {
+		return t
+	}
+	name, offset := t.Zone()
+	hours, ok := rfc822Zones[strings.ToUpper(name)]
+	if !ok || offset == hours*3600 {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
+		time.FixedZone(name, hours*3600))
+}
@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"nous-app/internal/models"
+)
+
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// want lists the article fields the fixture tests compare; empty strings
+// stand for nil pointers.
+type want struct {
+	ID, Title, URL, Summary, Image, Author, PublishedAt, Language string
+	Categories                                                    []string
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func checkArticles(t *testing.T, got []models.Article, wants []want) {
+	t.Helper()
+	if len(got) != len(wants) {
+		t.Fatalf("got %d articles, want %d", len(got), len(wants))
+	}
+	for i, w := range wants {
+		a := got[i]
+		g := want{
+			ID: a.ID, Title: a.Title, URL: a.URL, Summary: deref(a.Summary), Image: deref(a.Image),
+			Author: deref(a.Author), PublishedAt: deref(a.PublishedAt), Language: deref(a.Language),
+			Categories: a.Categories,
+		}
+		if !reflect.DeepEqual(g, w) {
+			t.Errorf("article %d =\n%+v\nwant\n%+v", i, g, w)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // "" when the date cannot be parsed
+	}{
+		{"Tue, 10 Jun 2003 04:00:00 EST", "2003-06-10T09:00:00Z"},
+		{"Tue, 10 Jun 2003 04:00:00 EDT", "2003-06-10T08:00:00Z"},
+		{"Tue, 10 Jun 2003 04:00:00 CST", "2003-06-10T10:00:00Z"},
+		{"Tue, 10 Jun 2003 04:00:00 CDT", "2003-06-10T09:00:00Z"},
+		{"Tue, 10 Jun 2003 04:00:00 MST", "2003-06-10T11:00:00Z"},
+		{"Tue, 10 Jun 2003 04:00:00 MDT", "2003-06-10T10:00:00Z"},
+		{"Tue, 10 Jun 2003 04:00:00 PST", "2003-06-10T12:00:00Z"},
+		{"Tue, 10 Jun 2003 04:00:00 PDT", "2003-06-10T11:00:00Z"},
+		{"Tue, 10 Jun 2003 04:00:00 GMT", "2003-06-10T04:00:00Z"},
+		{"Tue, 10 Jun 2003 04:00:00 UT", "2003-06-10T04:00:00Z"},
+		{"Tue, 10 Jun 2003 04:00:00 -0400", "2003-06-10T08:00:00Z"},
+		{"Tue, 3 Jun 2003 04:00:00 +0200", "2003-06-03T02:00:00Z"},
+		{"Tue, 03 Jun 2003 04:00:00 -0700 (PDT)", "2003-06-03T11:00:00Z"},
+		{"Tue, 3 Jun 2003 04:00 PST", "2003-06-03T12:00:00Z"},
+		{"3 Jun 2003 04:00:00 EST", "2003-06-03T09:00:00Z"},
+		{"Tuesday, 03-Jun-03 04:00:00 EST", "2003-06-03T09:00:00Z"},
+		{"Tue Jun  3 04:00:00 EST 2003", "2003-06-03T09:00:00Z"},
+		{"Tue Jun  3 04:00:00 2003", "2003-06-03T04:00:00Z"},
+		{"  Tue, 10 Jun 2003\n 04:00:00 GMT ", "2003-06-10T04:00:00Z"},
+		{"2024-01-02T15:04:05.123+01:00", "2024-01-02T14:04:05Z"},
+		{"2024-01-02T15:04:05Z", "2024-01-02T15:04:05Z"},
+		{"2024-01-02T15:04:05+0100", "2024-01-02T14:04:05Z"},
+		{"2024-01-02T15:04:05", "2024-01-02T15:04:05Z"},
+		{"2024-01-02 15:04:05 -0500", "2024-01-02T20:04:05Z"},
+		{"2024-01-02 15:04:05", "2024-01-02T15:04:05Z"},
+		{"2024-01-02T15:04+09:00", "2024-01-02T06:04:00Z"},
+		{"20240102T150405Z", "2024-01-02T15:04:05Z"},
+		{"2024-01-02", "2024-01-02T00:00:00Z"},
+		{"", ""},
+		{"yesterday", ""},
+	}
+	for _, tt := range tests {
+		if got := deref(ParseDate(tt.in)); got != tt.want {
+			t.Errorf("ParseDate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	data := fixture(t, "newsapi.json")
+
+	// The JSON parser is the default
+	articles, err := Parse(data, models.Source{Name: "Wire"})
+	if err != nil || len(articles) != 2 {
+		t.Errorf("Parse with no parser: %d articles, %v", len(articles), err)
+	}
+	if _, err := Parse(data, models.Source{Parser: "yaml"}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Parse with an unknown parser: %v, want ErrUnsupported", err)
+	}
+}
+
+func TestNewArticle(t *testing.T) {
+	src := models.Source{Name: "Example", Parser: "rss", Region: ptr("UK"), Category: ptr("News")}
+	a := newArticle(src, "https://www.example.co.uk/a", "", "")
+	if a.ID != a.URL || a.Title != "Untitled" {
+		t.Errorf("ID %q, Title %q", a.ID, a.Title)
+	}
+	if deref(a.SourceDomain) != "example.co.uk" || deref(a.SourceType) != "News" {
+		t.Errorf("SourceDomain %v, SourceType %v", a.SourceDomain, a.SourceType)
+	}
+	if a.Edition == nil || *a.Edition != models.EditionUK {
+		t.Errorf("Edition = %v, want uk", a.Edition)
+	}
+
+	// Without a link the guid is the ID, and without either a hash of the title
+	if a := newArticle(src, "", "Title", "guid-1"); a.ID != "guid-1" {
+		t.Errorf("ID = %q, want the guid", a.ID)
+	}
+	one, other := newArticle(src, "", "Title", ""), newArticle(src, "", "Other", "")
+	if len(one.ID) != 40 || one.ID == other.ID {
+		t.Errorf("hashed IDs %q and %q", one.ID, other.ID)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
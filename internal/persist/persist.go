@@ -0,0 +1,190 @@
+// Package persist writes data files so a crash or power loss never leaves a
+// half-written file behind: data goes to a temporary file in the same
+// directory, is synced to disk and then renamed over the target, which
+// readers see either entirely old or entirely new. A File additionally keeps
+// rotating backups of its previous versions and falls back to the newest
+// good one when the file cannot be read.
+package persist
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultBackups is the number of previous versions a File keeps.
+const DefaultBackups = 3
+
+// WriteFile atomically replaces path with data, creating its directory if
+// needed.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// Until the rename succeeds the temp file is garbage
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes a directory entry change (the rename) to disk. Not every
+// platform supports it, e.g. Windows, so failures are ignored.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
+
+// File is a data file with rotating backups: path.1 holds the previous
+// version, path.2 the one before, up to Backups.
+type File struct {
+	Path    string
+	Backups int
+	Perm    os.FileMode
+}
+
+// NewFile returns a File at path keeping DefaultBackups backups.
+func NewFile(path string) *File {
+	return &File{Path: path, Backups: DefaultBackups, Perm: 0644}
+}
+
+// backup returns the path of the n-th backup, 1 being the newest.
+func (f *File) backup(n int) string {
+	return fmt.Sprintf("%s.%d", f.Path, n)
+}
+
+// Save rotates the backups, keeps the current file as the newest backup and
+// atomically writes data in its place.
+func (f *File) Save(data []byte) error {
+	if f.Backups > 0 {
+		if err := f.rotate(); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", filepath.Base(f.Path), err)
+		}
+	}
+	return WriteFile(f.Path, data, f.Perm)
+}
+
+func (f *File) rotate() error {
+	if _, err := os.Stat(f.Path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	for n := f.Backups - 1; n >= 1; n-- {
+		if err := os.Rename(f.backup(n), f.backup(n+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	// Copy rather than rename, so the file itself is never missing
+	return copyFile(f.Path, f.backup(1), f.Perm)
+}
+
+func copyFile(from, to string, perm os.FileMode) error {
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	return WriteFile(to, data, perm)
+}
+
+// Load reads the file. check validates the content; when the file is
+// unreadable or check rejects it, the backups are tried newest first and the
+// first good one is restored in place of the file, which is kept as
+// path.corrupt. recovered names the backup used, empty if the file itself was
+// good. A missing file with no backups returns an error matching
+// os.ErrNotExist.
+func (f *File) Load(check func([]byte) error) (data []byte, recovered string, err error) {
+	data, recovered, bad, err := f.read(check)
+	if err != nil || recovered == "" {
+		return data, recovered, err
+	}
+	if bad != nil {
+		// Keep the bad file around for inspection before restoring over it
+		WriteFile(f.Path+".corrupt", bad, f.Perm)
+	}
+	// Restore without rotating, so the good backups stay in place
+	if err := WriteFile(f.Path, data, f.Perm); err != nil {
+		return nil, "", fmt.Errorf("failed to restore %s: %w", filepath.Base(recovered), err)
+	}
+	return data, recovered, nil
+}
+
+// Read is Load without writing anything: a good backup is returned but not
+// restored.
+func (f *File) Read(check func([]byte) error) (data []byte, recovered string, err error) {
+	data, recovered, _, err = f.read(check)
+	return data, recovered, err
+}
+
+// read returns the file or its newest good backup, along with the rejected
+// content of the file when a backup was used.
+func (f *File) read(check func([]byte) error) (data []byte, recovered string, bad []byte, err error) {
+	data, err = os.ReadFile(f.Path)
+	if err == nil {
+		if err = check(data); err == nil {
+			return data, "", nil, nil
+		}
+	}
+	if errors.Is(err, os.ErrNotExist) && !f.hasBackups() {
+		return nil, "", nil, err
+	}
+	cause := err
+
+	for n := 1; n <= f.Backups; n++ {
+		path := f.backup(n)
+		b, err := os.ReadFile(path)
+		if err != nil || check(b) != nil {
+			continue
+		}
+		return b, path, data, nil
+	}
+	return nil, "", nil, fmt.Errorf("%s is unreadable and no good backup was found: %w", filepath.Base(f.Path), cause)
+}
+
+func (f *File) hasBackups() bool {
+	for n := 1; n <= f.Backups; n++ {
+		if _, err := os.Stat(f.backup(n)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveBackups deletes the backups and the kept corrupt copy, e.g. after
+// moving data out of the file that must not linger in older versions.
+func (f *File) RemoveBackups() error {
+	paths := []string{f.Path + ".corrupt"}
+	for n := 1; n <= f.Backups; n++ {
+		paths = append(paths, f.backup(n))
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package persist
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// checkJSON accepts content starting with '{', standing in for a decoder.
+func checkJSON(data []byte) error {
+	if len(data) == 0 || data[0] != '{' {
+		return errors.New("not JSON")
+	}
+	return nil
+}
+
+func readString(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// entries returns the names in dir.
+func entries(t *testing.T, dir string) []string {
+	t.Helper()
+	list, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range list {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "data.json")
+	for _, content := range []string{"first", "second"} {
+		if err := WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if got := readString(t, path); got != content {
+			t.Errorf("content = %q, want %q", got, content)
+		}
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+	if names := entries(t, filepath.Dir(path)); len(names) != 1 {
+		t.Errorf("left behind: %v", names)
+	}
+
+	// A failed rename leaves no temp file either
+	blocked := filepath.Join(dir, "blocked")
+	os.MkdirAll(filepath.Join(blocked, "inside"), 0755)
+	if err := WriteFile(blocked, []byte("x"), 0644); err == nil {
+		t.Error("replaced a non-empty directory")
+	}
+	if names := entries(t, dir); len(names) != 2 {
+		t.Errorf("left behind: %v", names)
+	}
+}
+
+func TestSaveRotates(t *testing.T) {
+	f := NewFile(filepath.Join(t.TempDir(), "data.json"))
+	for _, v := range []string{"v1", "v2", "v3", "v4", "v5"} {
+		if err := f.Save([]byte(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := map[string]string{f.Path: "v5", f.Path + ".1": "v4", f.Path + ".2": "v3", f.Path + ".3": "v2"}
+	for path, content := range want {
+		if got := readString(t, path); got != content {
+			t.Errorf("%s = %q, want %q", filepath.Base(path), got, content)
+		}
+	}
+	// v1 dropped off the end
+	if _, err := os.Stat(f.Path + ".4"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("a fourth backup was kept: %v", err)
+	}
+
+	// Without backups Save only writes
+	plain := &File{Path: filepath.Join(t.TempDir(), "plain.json"), Perm: 0644}
+	plain.Save([]byte("a"))
+	plain.Save([]byte("b"))
+	if names := entries(t, filepath.Dir(plain.Path)); len(names) != 1 {
+		t.Errorf("backups kept with Backups = 0: %v", names)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	f := NewFile(filepath.Join(t.TempDir(), "data.json"))
+	if _, _, err := f.Load(checkJSON); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load of a missing file: %v, want ErrNotExist", err)
+	}
+
+	f.Save([]byte(`{"v":1}`))
+	f.Save([]byte(`{"v":2}`))
+	data, recovered, err := f.Load(checkJSON)
+	if err != nil || recovered != "" || string(data) != `{"v":2}` {
+		t.Errorf("Load of a good file = %q, %q, %v", data, recovered, err)
+	}
+	if _, err := os.Stat(f.Path + ".corrupt"); !errors.Is(err, os.ErrNotExist) {
+		t.Error("good file kept as corrupt")
+	}
+
+	// The newest backup is bad too, so the one before it is restored
+	os.WriteFile(f.Path, []byte("garbage"), 0644)
+	os.WriteFile(f.Path+".1", []byte("also garbage"), 0644)
+	os.WriteFile(f.Path+".2", []byte(`{"v":0}`), 0644)
+	data, recovered, err = f.Load(checkJSON)
+	if err != nil || recovered != f.Path+".2" || string(data) != `{"v":0}` {
+		t.Fatalf("Load = %q, %q, %v; want the second backup", data, recovered, err)
+	}
+	if got := readString(t, f.Path); got != `{"v":0}` {
+		t.Errorf("restored file = %q", got)
+	}
+	if got := readString(t, f.Path+".corrupt"); got != "garbage" {
+		t.Errorf("corrupt copy = %q, want the bad file", got)
+	}
+	if got := readString(t, f.Path+".1"); got != "also garbage" {
+		t.Errorf("backups were rotated by the restore: .1 = %q", got)
+	}
+
+	// A missing file with backups is restored too
+	os.Remove(f.Path)
+	if data, recovered, err := f.Load(checkJSON); err != nil || recovered != f.Path+".2" || string(data) != `{"v":0}` {
+		t.Errorf("Load of a missing file = %q, %q, %v; want the second backup", data, recovered, err)
+	}
+
+	// Nothing good left
+	for _, path := range []string{f.Path, f.Path + ".2"} {
+		os.WriteFile(path, []byte("garbage"), 0644)
+	}
+	if _, _, err := f.Load(checkJSON); err == nil {
+		t.Error("Load without a good version succeeded")
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	f := NewFile(filepath.Join(dir, "data.json"))
+	os.WriteFile(f.Path, []byte("garbage"), 0644)
+	os.WriteFile(f.Path+".1", []byte(`{"v":1}`), 0644)
+
+	data, recovered, err := f.Read(checkJSON)
+	if err != nil || recovered != f.Path+".1" || string(data) != `{"v":1}` {
+		t.Fatalf("Read = %q, %q, %v; want the backup", data, recovered, err)
+	}
+	if got := readString(t, f.Path); got != "garbage" {
+		t.Errorf("Read restored the backup: %q", got)
+	}
+	if names := entries(t, dir); len(names) != 2 {
+		t.Errorf("Read wrote files: %v", names)
+	}
+}
+
+func TestRemoveBackups(t *testing.T) {
+	dir := t.TempDir()
+	f := NewFile(filepath.Join(dir, "data.json"))
+	for _, v := range []string{"a", "b", "c"} {
+		f.Save([]byte(v))
+	}
+	os.WriteFile(f.Path+".corrupt", []byte("bad"), 0644)
+	os.WriteFile(filepath.Join(dir, "other.json"), []byte("x"), 0644)
+
+	if err := f.RemoveBackups(); err != nil {
+		t.Fatal(err)
+	}
+	names := entries(t, dir)
+	if len(names) != 2 || names[0] != "data.json" || names[1] != "other.json" {
+		t.Errorf("after RemoveBackups: %v", names)
+	}
+	// Nothing to remove is fine
+	if err := f.RemoveBackups(); err != nil {
+		t.Errorf("second RemoveBackups: %v", err)
+	}
+}
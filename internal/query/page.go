@@ -0,0 +1,143 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Listing sort keys.
+const (
+	SortPublishedAt       = "publishedAt"
+	SortFetchedAt         = "fetchedAt"
+	SortAnalysisTimestamp = "analysisTimestamp"
+)
+
+// PageRequest asks for one page of a store listing.
+type PageRequest struct {
+	Limit     int    `json:"limit,omitempty"`     // Items per page; default DefaultLimit, at most MaxLimit
+	Cursor    string `json:"cursor,omitempty"`    // NextCursor of the previous page; empty for the first
+	Sort      string `json:"sort,omitempty"`      // publishedAt (default), fetchedAt or analysisTimestamp
+	Ascending bool   `json:"ascending,omitempty"` // Oldest first instead of newest first
+}
+
+// Listing is the result of Paginate: the positions of the page's items in
+// the listed collection, in page order.
+type Listing struct {
+	Indices    []int
+	Total      int    // Size of the collection when the page was cut
+	NextCursor string // Empty on the last page
+}
+
+// Paginate sorts a collection of n items by the time keyOf returns for each
+// (items without one sort as the oldest), breaking ties by ID, and cuts the
+// page after req.Cursor. Cursors hold the last item's time and ID rather than
+// an offset, so items added or removed meanwhile neither repeat nor skip
+// items on later pages.
+func Paginate(n int, keyOf func(i int) (time.Time, string), req PageRequest) (Listing, error) {
+	if req.Sort == "" {
+		req.Sort = SortPublishedAt
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+
+	after, err := decodeListCursor(req.Cursor, req)
+	if err != nil {
+		return Listing{}, err
+	}
+
+	type entry struct {
+		i  int
+		t  time.Time
+		id string
+	}
+	entries := make([]entry, n)
+	for i := range entries {
+		t, id := keyOf(i)
+		entries[i] = entry{i: i, t: t, id: id}
+	}
+	less := func(a, b entry) bool {
+		if !a.t.Equal(b.t) {
+			return a.t.Before(b.t) == req.Ascending
+		}
+		return a.id < b.id
+	}
+	sort.Slice(entries, func(i, j int) bool { return less(entries[i], entries[j]) })
+
+	start := 0
+	if after != nil {
+		last := entry{t: after.time(), id: after.ID}
+		start = sort.Search(len(entries), func(i int) bool { return less(last, entries[i]) })
+	}
+	end := min(start+limit, len(entries))
+
+	l := Listing{Indices: make([]int, 0, end-start), Total: n}
+	for _, e := range entries[start:end] {
+		l.Indices = append(l.Indices, e.i)
+	}
+	if end < len(entries) && end > start {
+		last := entries[end-1]
+		l.NextCursor = encodeListCursor(req, last.t, last.id)
+	}
+	return l, nil
+}
+
+// ParseTime reads an RFC3339 timestamp as stored on articles; it returns the
+// zero time for nil or unparsable values.
+func ParseTime(s *string) time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, *s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// listCursor is the sort position of the last item of a listing page.
+type listCursor struct {
+	Sort      string `json:"s"`
+	Ascending bool   `json:"a,omitempty"`
+	Time      int64  `json:"t,omitempty"` // Unix nanoseconds; 0 if the item had none
+	ID        string `json:"i"`
+}
+
+func (c listCursor) time() time.Time {
+	if c.Time == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, c.Time)
+}
+
+func encodeListCursor(req PageRequest, t time.Time, id string) string {
+	c := listCursor{Sort: req.Sort, Ascending: req.Ascending, ID: id}
+	if !t.IsZero() {
+		c.Time = t.UnixNano()
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeListCursor(s string, req PageRequest) (*listCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+	var c listCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrBadCursor
+	}
+	if c.Sort != req.Sort || c.Ascending != req.Ascending {
+		return nil, fmt.Errorf("%w: it was issued for another sort order", ErrBadCursor)
+	}
+	return &c, nil
+}
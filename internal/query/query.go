@@ -0,0 +1,492 @@
+// Package query filters, sorts, facets and pages the articles of the local,
+// analyzed and federated stores, mirroring the frontend FilterOptions so the
+// filtering happens in Go instead of after downloading every article.
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"nous-app/internal/cluster"
+	"nous-app/internal/models"
+)
+
+// Stores an Item can come from.
+const (
+	StoreLocal     = "local"
+	StoreAnalyzed  = "analyzed"
+	StoreFederated = "federated"
+)
+
+// Sort orders.
+const (
+	SortNewest     = "newest"
+	SortOldest     = "oldest"
+	SortTitle      = "title"
+	SortConfidence = "confidence"
+	SortCoverage   = "coverage"
+)
+
+// Page size limits.
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// maxTagFacets caps the number of values of the tags facet.
+const maxTagFacets = 50
+
+// ErrBadCursor is returned for a cursor that is malformed or was issued for
+// another sort order.
+var ErrBadCursor = errors.New("invalid cursor")
+
+// Filter mirrors FilterOptions in the frontend. Empty fields and "all" do not
+// filter.
+type Filter struct {
+	Stores     []string `json:"stores,omitempty"`     // StoreLocal, StoreAnalyzed, StoreFederated; empty for all
+	Bias       string   `json:"bias,omitempty"`       // left, center or right; left and right include their lean-* sides
+	Edition    string   `json:"edition,omitempty"`    // "international" (the frontend default) does not filter
+	Sentiment  string   `json:"sentiment,omitempty"`  // positive, neutral or negative
+	Coverage   string   `json:"coverage,omitempty"`   // high, medium or low, by the number of sources carrying the article
+	Confidence string   `json:"confidence,omitempty"` // high, medium or low
+	Tags       []string `json:"tags,omitempty"`       // Articles with any of these tags or categories
+	Source     string   `json:"source,omitempty"`     // Source name or domain, case-insensitive
+}
+
+// Item is an article from one of the stores. Federated items only carry what
+// their pointer holds: ID and IPFSHash (the CID), PublishedAt (the pointer
+// timestamp), Source, Edition and Analyzed.
+type Item struct {
+	Store string `json:"store"`
+	models.ArticleAnalyzed
+}
+
+// FacetCount is the number of matching articles with one facet value.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Page is one page of a query.
+type Page struct {
+	Items      []Item                  `json:"items"`
+	Total      int                     `json:"total"`                // Articles matching the filter across all pages
+	NextCursor string                  `json:"nextCursor,omitempty"` // Empty on the last page
+	Facets     map[string][]FacetCount `json:"facets"`               // Per dimension, counted with every other filter applied
+}
+
+// Items merges the three stores into one list. An article present in both
+// the local and the analyzed store is listed once, from the analyzed store.
+func Items(local []models.Article, analyzed []models.ArticleAnalyzed, federated []models.FederatedArticlePointer) []Item {
+	items := make([]Item, 0, len(local)+len(analyzed)+len(federated))
+	seen := map[string]bool{}
+	for _, a := range analyzed {
+		items = append(items, Item{Store: StoreAnalyzed, ArticleAnalyzed: a})
+		seen[models.ArticleKey(a.ID, a.URL)] = true
+	}
+	for _, a := range local {
+		if seen[models.ArticleKey(a.ID, a.URL)] {
+			continue
+		}
+		items = append(items, Item{Store: StoreLocal, ArticleAnalyzed: models.ArticleAnalyzed{Article: a}})
+	}
+	for _, p := range federated {
+		cid := p.CID
+		ts := p.Timestamp
+		a := models.Article{ID: cid, IPFSHash: &cid, PublishedAt: &ts, Analyzed: p.Analyzed, Source: p.Source}
+		if p.Edition != nil {
+			e := models.Edition(*p.Edition)
+			a.Edition = &e
+		}
+		items = append(items, Item{Store: StoreFederated, ArticleAnalyzed: models.ArticleAnalyzed{Article: a}})
+	}
+	return items
+}
+
+// facts are the filterable values of an item, derived once per query.
+type facts struct {
+	store      string
+	bias       string // left, center, right or unknown
+	edition    string
+	sentiment  string
+	coverage   string
+	sources    int
+	confidence string
+	score      float64 // Confidence, -1 if unknown
+	tags       []string
+	source     string
+	domain     string
+	title      string // Lowercased, for SortTitle
+	published  time.Time
+	uid        string
+}
+
+// Run applies the filter, sorts, computes facets and returns the page after
+// cursor. biasOf looks up a source's bias by name for articles without one;
+// it may be nil.
+func Run(items []Item, f Filter, order, cursor string, limit int, biasOf func(string) string) (Page, error) {
+	if order == "" {
+		order = SortNewest
+	}
+	less, ok := orders[order]
+	if !ok {
+		return Page{}, fmt.Errorf("unknown sort %q", order)
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+
+	after, err := decodeCursor(cursor, order)
+	if err != nil {
+		return Page{}, err
+	}
+
+	all := make([]facts, len(items))
+	for i := range items {
+		all[i] = factsOf(items[i], biasOf)
+	}
+
+	page := Page{Items: []Item{}, Facets: map[string][]FacetCount{}}
+	counts := map[string]map[string]int{}
+	var matched []int
+	for i, fa := range all {
+		failed := f.failing(fa)
+		if len(failed) == 0 {
+			matched = append(matched, i)
+		}
+		// An item counts towards a dimension's facet if it passes every
+		// other filter, so each facet shows what selecting a value would give.
+		for _, dim := range dimensions {
+			if len(failed) == 0 || len(failed) == 1 && failed[0] == dim {
+				countFacet(counts, dim, fa)
+			}
+		}
+	}
+	for _, dim := range dimensions {
+		page.Facets[dim] = sortedCounts(counts[dim], dim == "tags")
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return less(all[matched[i]], all[matched[j]]) })
+	page.Total = len(matched)
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(matched), func(i int) bool { return less(*after, all[matched[i]]) })
+	}
+	end := min(start+limit, len(matched))
+	for _, i := range matched[start:end] {
+		page.Items = append(page.Items, items[i])
+	}
+	if end < len(matched) && end > start {
+		page.NextCursor = encodeCursor(order, all[matched[end-1]])
+	}
+	return page, nil
+}
+
+// Facet dimensions.
+var dimensions = []string{"store", "bias", "edition", "sentiment", "coverage", "confidence", "tags", "source"}
+
+// failing returns the dimensions whose filter rejects fa.
+func (f Filter) failing(fa facts) []string {
+	var failed []string
+	if len(f.Stores) > 0 && !containsFold(f.Stores, fa.store) {
+		failed = append(failed, "store")
+	}
+	if active(f.Bias) && !strings.EqualFold(f.Bias, fa.bias) {
+		failed = append(failed, "bias")
+	}
+	if active(f.Edition) && !strings.EqualFold(f.Edition, "international") && !strings.EqualFold(f.Edition, fa.edition) {
+		failed = append(failed, "edition")
+	}
+	if active(f.Sentiment) && !strings.EqualFold(f.Sentiment, fa.sentiment) {
+		failed = append(failed, "sentiment")
+	}
+	if active(f.Coverage) && !strings.EqualFold(f.Coverage, fa.coverage) {
+		failed = append(failed, "coverage")
+	}
+	if active(f.Confidence) && !strings.EqualFold(f.Confidence, fa.confidence) {
+		failed = append(failed, "confidence")
+	}
+	if len(f.Tags) > 0 && !anyFold(f.Tags, fa.tags) {
+		failed = append(failed, "tags")
+	}
+	if active(f.Source) && !strings.EqualFold(f.Source, fa.source) && !strings.EqualFold(strings.TrimPrefix(f.Source, "www."), fa.domain) {
+		failed = append(failed, "source")
+	}
+	return failed
+}
+
+func countFacet(counts map[string]map[string]int, dim string, fa facts) {
+	if counts[dim] == nil {
+		counts[dim] = map[string]int{}
+	}
+	switch dim {
+	case "store":
+		counts[dim][fa.store]++
+	case "bias":
+		counts[dim][fa.bias]++
+	case "edition":
+		counts[dim][orUnknown(fa.edition)]++
+	case "sentiment":
+		counts[dim][orUnknown(fa.sentiment)]++
+	case "coverage":
+		counts[dim][fa.coverage]++
+	case "confidence":
+		counts[dim][orUnknown(fa.confidence)]++
+	case "tags":
+		for _, t := range fa.tags {
+			counts[dim][t]++
+		}
+	case "source":
+		counts[dim][orUnknown(fa.source)]++
+	}
+}
+
+func sortedCounts(m map[string]int, capped bool) []FacetCount {
+	out := make([]FacetCount, 0, len(m))
+	for v, c := range m {
+		out = append(out, FacetCount{Value: v, Count: c})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Value < out[j].Value
+	})
+	if capped && len(out) > maxTagFacets {
+		out = out[:maxTagFacets]
+	}
+	return out
+}
+
+func factsOf(it Item, biasOf func(string) string) facts {
+	a := it.ArticleAnalyzed
+	fa := facts{
+		store:     it.Store,
+		published: cluster.ArticleTime(a.Article),
+		uid:       it.Store + ":" + models.ArticleKey(a.ID, a.URL),
+		title:     strings.ToLower(cluster.CleanTitle(a.Title)),
+		score:     -1,
+	}
+
+	if a.Edition != nil {
+		fa.edition = strings.ToLower(string(*a.Edition))
+	}
+
+	switch {
+	case a.Source != nil && *a.Source != "":
+		fa.source = *a.Source
+	case a.SourceMeta != nil && a.SourceMeta.Name != "":
+		fa.source = a.SourceMeta.Name
+	case a.SourceDomain != nil:
+		fa.source = *a.SourceDomain
+	}
+	fa.domain = hostOf(a.URL)
+
+	// The analysis' own reading of the article wins over its source's bias.
+	bias := models.BiasUnknown
+	if a.PoliticalBias != nil {
+		bias = cluster.NormalizeBias(*a.PoliticalBias)
+	}
+	if bias == models.BiasUnknown && a.SourceMeta != nil {
+		bias = cluster.NormalizeBias(a.SourceMeta.Bias)
+	}
+	if bias == models.BiasUnknown && biasOf != nil && fa.source != "" {
+		bias = cluster.NormalizeBias(biasOf(fa.source))
+	}
+	switch bias {
+	case models.BiasLeanLeft:
+		bias = models.BiasLeft
+	case models.BiasLeanRight:
+		bias = models.BiasRight
+	}
+	fa.bias = bias
+
+	switch {
+	case a.Sentiment != nil && *a.Sentiment != "":
+		fa.sentiment = strings.ToLower(*a.Sentiment)
+	case a.SentimentValence != nil:
+		fa.sentiment = sentimentOf(*a.SentimentValence)
+	}
+
+	fa.sources = max(len(a.Sources), 1)
+	switch {
+	case fa.sources >= 5:
+		fa.coverage = "high"
+	case fa.sources >= 2:
+		fa.coverage = "medium"
+	default:
+		fa.coverage = "low"
+	}
+
+	// ArticleAnalyzed.Confidence shadows the article's; fall back to the
+	// article's and then to the confidence of the source's bias rating.
+	conf := a.Confidence
+	if conf == nil {
+		conf = a.Article.Confidence
+	}
+	if conf == nil && a.SourceMeta != nil {
+		conf = a.SourceMeta.Confidence
+	}
+	if conf != nil {
+		fa.score = *conf
+		switch {
+		case *conf >= 0.75:
+			fa.confidence = "high"
+		case *conf >= 0.5:
+			fa.confidence = "medium"
+		default:
+			fa.confidence = "low"
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, t := range append(append([]string{}, a.Tags...), a.Categories...) {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !seen[t] {
+			seen[t] = true
+			fa.tags = append(fa.tags, t)
+		}
+	}
+	return fa
+}
+
+func sentimentOf(valence float64) string {
+	switch {
+	case valence > 0.2:
+		return "positive"
+	case valence < -0.2:
+		return "negative"
+	}
+	return "neutral"
+}
+
+// ----------------------
+// Sorting and cursors
+// ----------------------
+
+// orders compare two items for each sort; ties are broken by store and ID
+// so the order, and therefore every cursor, is stable.
+var orders = map[string]func(a, b facts) bool{
+	SortNewest: func(a, b facts) bool {
+		if !a.published.Equal(b.published) {
+			return a.published.After(b.published)
+		}
+		return a.uid < b.uid
+	},
+	SortOldest: func(a, b facts) bool {
+		if !a.published.Equal(b.published) {
+			return a.published.Before(b.published)
+		}
+		return a.uid < b.uid
+	},
+	SortConfidence: func(a, b facts) bool {
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		return a.uid < b.uid
+	},
+	SortTitle: func(a, b facts) bool {
+		if a.title != b.title {
+			return a.title < b.title
+		}
+		return a.uid < b.uid
+	},
+	SortCoverage: func(a, b facts) bool {
+		if a.sources != b.sources {
+			return a.sources > b.sources
+		}
+		if !a.published.Equal(b.published) {
+			return a.published.After(b.published)
+		}
+		return a.uid < b.uid
+	},
+}
+
+// cursor is the sort position of the last item of a page.
+type cursor struct {
+	Sort      string  `json:"s"`
+	Published int64   `json:"p,omitempty"` // Unix nanoseconds
+	Score     float64 `json:"c,omitempty"`
+	Sources   int     `json:"n,omitempty"`
+	Title     string  `json:"t,omitempty"`
+	UID       string  `json:"u"`
+}
+
+func encodeCursor(order string, fa facts) string {
+	c := cursor{Sort: order, Score: fa.score, Sources: fa.sources, Title: fa.title, UID: fa.uid}
+	if !fa.published.IsZero() {
+		c.Published = fa.published.UnixNano()
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s, order string) (*facts, error) {
+	if s == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort != order || c.UID == "" {
+		return nil, ErrBadCursor
+	}
+	fa := &facts{score: c.Score, sources: c.Sources, title: c.Title, uid: c.UID}
+	if c.Published != 0 {
+		fa.published = time.Unix(0, c.Published)
+	}
+	return fa, nil
+}
+
+// ----------------------
+// Helpers
+// ----------------------
+
+// active reports whether a filter value restricts anything.
+func active(v string) bool {
+	return v != "" && !strings.EqualFold(v, "all")
+}
+
+func orUnknown(v string) string {
+	if v == "" {
+		return "unknown"
+	}
+	return v
+}
+
+func containsFold(values []string, v string) bool {
+	for _, s := range values {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyFold reports whether any wanted value is among values.
+func anyFold(wanted, values []string) bool {
+	for _, w := range wanted {
+		if containsFold(values, strings.TrimSpace(w)) {
+			return true
+		}
+	}
+	return false
+}
+
+func hostOf(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
@@ -0,0 +1,223 @@
+package readability
+
+import (
+	"encoding/json"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"nous-app/internal/css"
+)
+
+// Metadata is what a page says about itself in OpenGraph, Twitter card,
+// standard <meta> and JSON-LD markup. JSON-LD wins over meta tags, which win
+// over what can be guessed from the document.
+type Metadata struct {
+	Title       string `json:"title,omitempty"`
+	Author      string `json:"author,omitempty"`
+	Image       string `json:"image,omitempty"`
+	PublishedAt string `json:"publishedAt,omitempty"` // As written on the page
+	SiteName    string `json:"siteName,omitempty"`
+	Description string `json:"description,omitempty"`
+	Language    string `json:"language,omitempty"`
+}
+
+var (
+	metaTags   = css.MustCompile("meta[content]")
+	jsonLD     = css.MustCompile(`script[type="application/ld+json"]`)
+	titleTag   = css.MustCompile("head title")
+	htmlTag    = css.MustCompile("html[lang]")
+	imageLink  = css.MustCompile(`link[rel="image_src"][href]`)
+	authorLink = css.MustCompile(`a[rel~="author"], [itemprop="author"] [itemprop="name"], [itemprop="author"]`)
+	dateProp   = css.MustCompile(`[itemprop="datePublished"]`)
+)
+
+// readMetadata collects metadata from doc. It must run before the document is
+// cleaned, since JSON-LD lives in <script> elements.
+func readMetadata(doc *html.Node) Metadata {
+	meta := map[string]string{}
+	for _, n := range metaTags.MatchAll(doc) {
+		key := strings.ToLower(firstNonEmpty(css.Attr(n, "property"), css.Attr(n, "name"), css.Attr(n, "itemprop")))
+		if key == "" {
+			continue
+		}
+		if _, seen := meta[key]; !seen {
+			meta[key] = strings.TrimSpace(css.Attr(n, "content"))
+		}
+	}
+
+	ld := readJSONLD(doc)
+
+	m := Metadata{
+		Title: firstNonEmpty(ld.Title, meta["og:title"], meta["twitter:title"], meta["dc.title"]),
+		Author: firstNonEmpty(ld.Author, meta["author"], meta["article:author"], meta["dc.creator"],
+			meta["sailthru.author"], meta["parsely-author"]),
+		Image: firstNonEmpty(ld.Image, meta["og:image"], meta["og:image:url"], meta["og:image:secure_url"],
+			meta["twitter:image"], meta["twitter:image:src"]),
+		PublishedAt: firstNonEmpty(ld.PublishedAt, meta["article:published_time"], meta["og:published_time"],
+			meta["datepublished"], meta["date"], meta["pubdate"], meta["publishdate"], meta["dc.date"],
+			meta["dc.date.issued"], meta["sailthru.date"], meta["parsely-pub-date"]),
+		SiteName:    firstNonEmpty(meta["og:site_name"], ld.SiteName, meta["application-name"]),
+		Description: firstNonEmpty(ld.Description, meta["og:description"], meta["twitter:description"], meta["description"]),
+	}
+
+	// article:author is often a profile URL rather than a name
+	if strings.HasPrefix(m.Author, "http") {
+		m.Author = ""
+	}
+	if m.Author == "" {
+		if n := authorLink.MatchFirst(doc); n != nil {
+			if name := css.Text(n); len(name) <= 100 {
+				m.Author = name
+			}
+		}
+	}
+	if m.Title == "" {
+		if n := titleTag.MatchFirst(doc); n != nil {
+			m.Title = css.Text(n)
+		}
+	}
+	if m.Image == "" {
+		if n := imageLink.MatchFirst(doc); n != nil {
+			m.Image = css.Attr(n, "href")
+		}
+	}
+	if m.PublishedAt == "" {
+		if n := dateProp.MatchFirst(doc); n != nil {
+			m.PublishedAt = firstNonEmpty(css.Attr(n, "datetime"), css.Attr(n, "content"), css.Text(n))
+		}
+	}
+	if n := htmlTag.MatchFirst(doc); n != nil {
+		m.Language = css.Attr(n, "lang")
+	} else if l := meta["og:locale"]; l != "" {
+		m.Language = strings.ReplaceAll(l, "_", "-")
+	}
+	if m.Language != "" {
+		m.Language = strings.ToLower(strings.SplitN(m.Language, "-", 2)[0])
+	}
+	return m
+}
+
+// ----------------------
+// JSON-LD
+// ----------------------
+
+// readJSONLD returns the metadata of the first Article-like object in the
+// page's JSON-LD blocks, looking inside arrays and @graph.
+func readJSONLD(doc *html.Node) Metadata {
+	for _, n := range jsonLD.MatchAll(doc) {
+		var raw strings.Builder
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			raw.WriteString(c.Data)
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(raw.String())), &v); err != nil {
+			continue
+		}
+		if obj := findArticle(v); obj != nil {
+			return Metadata{
+				Title:       ldString(obj["headline"], obj["name"]),
+				Author:      ldNames(obj["author"]),
+				Image:       ldURL(obj["image"], obj["thumbnailUrl"]),
+				PublishedAt: ldString(obj["datePublished"], obj["dateCreated"]),
+				SiteName:    ldNames(obj["publisher"]),
+				Description: ldString(obj["description"]),
+			}
+		}
+	}
+	return Metadata{}
+}
+
+// findArticle returns the first object whose @type names an article.
+func findArticle(v interface{}) map[string]interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, e := range t {
+			if obj := findArticle(e); obj != nil {
+				return obj
+			}
+		}
+	case map[string]interface{}:
+		if isArticleType(t["@type"]) {
+			return t
+		}
+		if graph, ok := t["@graph"]; ok {
+			return findArticle(graph)
+		}
+	}
+	return nil
+}
+
+func isArticleType(v interface{}) bool {
+	switch t := v.(type) {
+	case string:
+		return strings.HasSuffix(t, "Article") || t == "BlogPosting" || t == "Report" || t == "LiveBlogPosting"
+	case []interface{}:
+		for _, e := range t {
+			if isArticleType(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ldString returns the first non-empty string value.
+func ldString(values ...interface{}) string {
+	for _, v := range values {
+		if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
+			return strings.TrimSpace(s)
+		}
+	}
+	return ""
+}
+
+// ldNames reads a Person/Organization, a list of them, or a plain string, and
+// joins the names with ", ".
+func ldNames(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case map[string]interface{}:
+		return ldString(t["name"])
+	case []interface{}:
+		var names []string
+		for _, e := range t {
+			if name := ldNames(e); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+// ldURL reads an image given as a URL, an ImageObject or a list of either.
+func ldURL(values ...interface{}) string {
+	for _, v := range values {
+		switch t := v.(type) {
+		case string:
+			if s := strings.TrimSpace(t); s != "" {
+				return s
+			}
+		case map[string]interface{}:
+			if s := ldString(t["url"], t["contentUrl"]); s != "" {
+				return s
+			}
+		case []interface{}:
+			if s := ldURL(t...); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
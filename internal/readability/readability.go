@@ -0,0 +1,443 @@
+// Package readability extracts the main content of an article page, in the
+// spirit of Mozilla's Readability: boilerplate (navigation, ads, share bars,
+// comments) is stripped, the densest block of paragraphs is kept, and the
+// result is returned as plain text and as sanitized HTML containing only
+// paragraphs, headings, lists, quotes, figures and captions.
+//
+// Page metadata (OpenGraph, Twitter cards, JSON-LD) is read alongside, so
+// Enrich can fill an Article's author, image and publication date.
+package readability
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+
+	"nous-app/internal/models"
+	"nous-app/internal/parser"
+)
+
+// MaxPageBytes caps how much of a page Fetch reads.
+const MaxPageBytes = 5 << 20
+
+// ErrNoContent is returned when a page has no recognisable article body.
+var ErrNoContent = errors.New("readability: no article content found")
+
+// Result is an extracted article.
+type Result struct {
+	Metadata
+	URL     string `json:"url"`
+	Text    string `json:"text"`    // Paragraphs separated by blank lines
+	HTML    string `json:"html"`    // Sanitized article HTML
+	Excerpt string `json:"excerpt"` // Description, or the start of the lead paragraph
+	Length  int    `json:"length"`  // Characters of text
+}
+
+// Fetch downloads pageURL and extracts its article. headers are sent with the
+// request (e.g. User-Agent); client defaults to http.DefaultClient.
+func Fetch(ctx context.Context, client *http.Client, pageURL string, headers map[string]string) (*Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("readability: failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.5")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("readability: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("readability: HTTP %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && !strings.Contains(contentType, "html") {
+		return nil, fmt.Errorf("readability: %s is not an HTML page", contentType)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxPageBytes))
+	if err != nil {
+		return nil, fmt.Errorf("readability: failed to read page: %w", err)
+	}
+
+	// Resolve links against the final URL after redirects
+	finalURL := pageURL
+	if resp.Request != nil && resp.Request.URL != nil {
+		finalURL = resp.Request.URL.String()
+	}
+	return Extract(body, finalURL, contentType)
+}
+
+// Extract extracts the article from an HTML page. pageURL resolves relative
+// links and images; contentType may carry the charset and can be empty.
+func Extract(data []byte, pageURL, contentType string) (*Result, error) {
+	enc, _, _ := charset.DetermineEncoding(data, contentType)
+	doc, err := html.Parse(enc.NewDecoder().Reader(bytes.NewReader(data)))
+	if err != nil {
+		return nil, fmt.Errorf("readability: invalid HTML: %w", err)
+	}
+
+	base := pageURL
+	if n := baseTag.MatchFirst(doc); n != nil {
+		base = resolve(base, attr(n, "href"))
+	}
+
+	res := &Result{Metadata: readMetadata(doc), URL: pageURL}
+	res.Image = resolve(base, res.Image)
+
+	prepare(doc)
+	top, scores := topCandidate(doc)
+	if top == nil {
+		return nil, ErrNoContent
+	}
+
+	content := gather(top, scores)
+	var lead string
+	res.HTML, res.Text, lead = render(content, base)
+	if res.Text == "" {
+		return nil, ErrNoContent
+	}
+	res.Length = len([]rune(res.Text))
+	res.Excerpt = res.Description
+	if res.Excerpt == "" {
+		res.Excerpt = excerpt(lead, res.Text, 300)
+	}
+	return res, nil
+}
+
+// Enrich fills the fields of a that are missing from what was extracted:
+// Content (the sanitized HTML), Summary, Author, Image, PublishedAt, Language
+// and an empty or placeholder Title. Existing values are kept.
+func Enrich(a *models.Article, r *Result) {
+	if r == nil {
+		return
+	}
+	if a.Content == nil || strings.TrimSpace(*a.Content) == "" {
+		a.Content = strPtr(r.HTML)
+	}
+	if a.Summary == nil || strings.TrimSpace(*a.Summary) == "" {
+		a.Summary = strPtr(r.Excerpt)
+	}
+	if a.Author == nil {
+		a.Author = strPtr(r.Author)
+	}
+	if a.Image == nil {
+		a.Image = strPtr(r.Image)
+	}
+	if a.PublishedAt == nil && r.PublishedAt != "" {
+		a.PublishedAt = parser.ParseDate(r.PublishedAt)
+	}
+	if a.Language == nil {
+		a.Language = strPtr(r.Language)
+	}
+	if t := strings.TrimSpace(a.Title); (t == "" || t == "Untitled") && r.Title != "" {
+		a.Title = r.Title
+	}
+}
+
+// ----------------------
+// Preparation
+// ----------------------
+
+// removedTags never hold article text.
+var removedTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "iframe": true,
+	"form": true, "button": true, "input": true, "select": true, "textarea": true,
+	"nav": true, "footer": true, "aside": true, "svg": true, "canvas": true,
+	"object": true, "embed": true, "link": true, "meta": true, "dialog": true,
+}
+
+var (
+	unlikely = regexp.MustCompile(`(?i)-ad-|ai2html|banner|breadcrumb|combx|comment|community|cookie|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|newsletter|pager|pagination|popup|promo|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|supplemental|ad-break|agegate|yom-remote`)
+	maybe    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positive = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negative = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// prepare removes elements that are never content: scripts, forms,
+// navigation, hidden elements, and blocks whose class or id marks them as
+// boilerplate.
+func prepare(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch {
+		case c.Type == html.CommentNode:
+			n.RemoveChild(c)
+		case c.Type == html.ElementNode && isBoilerplate(c):
+			n.RemoveChild(c)
+		default:
+			prepare(c)
+		}
+		c = next
+	}
+}
+
+func isBoilerplate(n *html.Node) bool {
+	if removedTags[n.Data] {
+		return true
+	}
+	if _, hidden := lookup(n, "hidden"); hidden || attr(n, "aria-hidden") == "true" {
+		return true
+	}
+	if style := strings.ReplaceAll(strings.ToLower(attr(n, "style")), " ", ""); strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden") {
+		return true
+	}
+	switch n.Data {
+	case "html", "body", "article", "main", "a":
+		return false
+	}
+	if role := attr(n, "role"); role == "navigation" || role == "complementary" || role == "banner" || role == "dialog" {
+		return true
+	}
+	match := attr(n, "class") + " " + attr(n, "id")
+	return unlikely.MatchString(match) && !maybe.MatchString(match)
+}
+
+// ----------------------
+// Scoring
+// ----------------------
+
+// topCandidate scores every paragraph-like element and returns the ancestor
+// that collects the most score, which is taken as the article body, along
+// with the scores of all candidates.
+func topCandidate(doc *html.Node) (*html.Node, map[*html.Node]float64) {
+	scores := map[*html.Node]float64{}
+	var order []*html.Node
+
+	addScore := func(n *html.Node, s float64) {
+		if n == nil || n.Type != html.ElementNode || n.Data == "html" {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			order = append(order, n)
+		}
+		scores[n] += s
+	}
+
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if isParagraph(c) {
+				text := innerText(c)
+				if len(text) >= 25 {
+					score := 1 + float64(strings.Count(text, ",")+strings.Count(text, "，")) + minFloat(float64(len(text))/100, 3)
+					for level, p := 0, c.Parent; p != nil && level < 3; level, p = level+1, p.Parent {
+						divider := 1.0
+						if level == 1 {
+							divider = 2
+						} else if level > 1 {
+							divider = float64(level * 3)
+						}
+						addScore(p, score/divider)
+					}
+				}
+			}
+			visit(c)
+		}
+	}
+	visit(doc)
+
+	var top *html.Node
+	best := 0.0
+	for _, n := range order {
+		s := scores[n] * (1 - linkDensity(n))
+		scores[n] = s
+		if top == nil || s > best {
+			top, best = n, s
+		}
+	}
+	if top == nil {
+		return bodyTag.MatchFirst(doc), scores
+	}
+
+	// A top candidate whose parent holds several comparable candidates is
+	// probably one section of the article; move up to the common parent.
+	if p := top.Parent; p != nil && p.Type == html.ElementNode && p.Data != "body" && p.Data != "html" {
+		close := 0
+		for c := p.FirstChild; c != nil; c = c.NextSibling {
+			if c != top && scores[c] >= best*0.75 {
+				close++
+			}
+		}
+		if close >= 2 {
+			top = p
+		}
+	}
+	return top, scores
+}
+
+func initialScore(n *html.Node) float64 {
+	var s float64
+	switch n.Data {
+	case "div", "article", "main":
+		s = 5
+	case "pre", "td", "blockquote":
+		s = 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		s = -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		s = -5
+	}
+	return s + classWeight(n)
+}
+
+func classWeight(n *html.Node) float64 {
+	var w float64
+	for _, v := range []string{attr(n, "class"), attr(n, "id")} {
+		if v == "" {
+			continue
+		}
+		if negative.MatchString(v) {
+			w -= 25
+		}
+		if positive.MatchString(v) {
+			w += 25
+		}
+	}
+	return w
+}
+
+// blockTags are elements that break a <div> out of being a paragraph.
+var blockTags = map[string]bool{
+	"blockquote": true, "dl": true, "div": true, "img": true, "ol": true, "p": true,
+	"pre": true, "table": true, "ul": true, "section": true, "article": true, "figure": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// isParagraph reports elements scored as text: paragraphs, preformatted
+// blocks, table cells and divs that contain no other blocks.
+func isParagraph(n *html.Node) bool {
+	switch n.Data {
+	case "p", "pre", "td":
+		return true
+	case "div", "section":
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && blockTags[c.Data] {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// linkDensity is the share of n's text that sits inside links.
+func linkDensity(n *html.Node) float64 {
+	total := len(innerText(n))
+	if total == 0 {
+		return 0
+	}
+	linked := 0
+	for _, a := range anchors.MatchAll(n) {
+		linked += len(innerText(a))
+	}
+	return float64(linked) / float64(total)
+}
+
+// ----------------------
+// Gathering
+// ----------------------
+
+// gather returns the top candidate plus siblings that look like part of the
+// same article: well-scored blocks, and text paragraphs with few links.
+func gather(top *html.Node, scores map[*html.Node]float64) []*html.Node {
+	parent := top.Parent
+	if parent == nil || top.Data == "body" {
+		return []*html.Node{top}
+	}
+
+	topScore := scores[top]
+	threshold := maxFloat(10, topScore*0.2)
+	topClass := attr(top, "class")
+
+	var nodes []*html.Node
+	for c := parent.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if c == top {
+			nodes = append(nodes, c)
+			continue
+		}
+		bonus := 0.0
+		if topClass != "" && attr(c, "class") == topClass {
+			bonus = topScore * 0.2
+		}
+		if s, ok := scores[c]; ok && s+bonus >= threshold {
+			nodes = append(nodes, c)
+			continue
+		}
+		if c.Data == "p" {
+			text := innerText(c)
+			density := linkDensity(c)
+			if len(text) > 80 && density < 0.25 || len(text) > 0 && len(text) <= 80 && density == 0 && sentenceEnd.MatchString(text) {
+				nodes = append(nodes, c)
+			}
+		}
+	}
+	return nodes
+}
+
+var sentenceEnd = regexp.MustCompile(`\.( |$)`)
+
+// ----------------------
+// Helpers
+// ----------------------
+
+// excerpt shortens the lead paragraph, or without one the first paragraph of
+// text, to about n characters.
+func excerpt(lead, text string, n int) string {
+	para := lead
+	if para == "" {
+		para = strings.SplitN(text, "\n\n", 2)[0]
+	}
+	r := []rune(para)
+	if len(r) <= n {
+		return para
+	}
+	cut := string(r[:n])
+	if i := strings.LastIndexByte(cut, ' '); i > n/2 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
+
+func strPtr(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,410 @@
+package readability
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"nous-app/internal/css"
+)
+
+var (
+	anchors = css.MustCompile("a")
+	images  = css.MustCompile("img")
+	bodyTag = css.MustCompile("body")
+	baseTag = css.MustCompile("base[href]")
+)
+
+// keptBlocks and keptInline are the only elements that survive sanitizing,
+// with the renamings applied (h1 becomes h2, b strong and i em). Every other
+// element is unwrapped, keeping its children.
+var (
+	keptBlocks = map[string]string{
+		"p": "p", "h1": "h2", "h2": "h2", "h3": "h3", "h4": "h4", "h5": "h5", "h6": "h6",
+		"blockquote": "blockquote", "ul": "ul", "ol": "ol", "li": "li", "pre": "pre",
+		"figure": "figure", "figcaption": "figcaption", "table": "table", "thead": "thead",
+		"tbody": "tbody", "tr": "tr", "td": "td", "th": "th", "dl": "dl", "dt": "dt", "dd": "dd",
+		"hr": "hr",
+	}
+	keptInline = map[string]string{
+		"a": "a", "em": "em", "i": "em", "strong": "strong", "b": "strong", "code": "code",
+		"sub": "sub", "sup": "sup", "br": "br", "img": "img", "mark": "mark", "q": "q",
+		"cite": "cite", "abbr": "abbr",
+	}
+	blockContainers = map[string]bool{
+		"div": true, "section": true, "article": true, "main": true, "header": true,
+		"center": true, "address": true, "details": true, "summary": true,
+	}
+	droppedTags = map[string]bool{
+		"video": true, "audio": true, "source": true, "track": true, "map": true, "area": true,
+	}
+)
+
+// render sanitizes the gathered nodes and returns them as HTML and as text,
+// along with the text of the lead paragraph (see leadParagraph).
+func render(nodes []*html.Node, base string) (string, string, string) {
+	root := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range nodes {
+		sanitize(root, n, base, false)
+	}
+	wrapLooseText(root)
+	pruneEmpty(root)
+
+	var b strings.Builder
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		html.Render(&b, c)
+	}
+
+	var t textWriter
+	t.write(root)
+	return b.String(), t.String(), leadParagraph(root)
+}
+
+// minLeadLength is the shortest paragraph leadParagraph takes as the lead;
+// shorter ones are usually bylines, datelines or captions.
+const minLeadLength = 80
+
+// leadParagraph returns the text of the first <p> of at least minLeadLength
+// characters outside figures, quotes and lists, or "". Headings are never
+// the lead.
+func leadParagraph(root *html.Node) string {
+	var lead string
+	var find func(*html.Node) bool
+	find = func(n *html.Node) bool {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.Data {
+			case "p":
+				if text := innerText(c); len([]rune(text)) >= minLeadLength {
+					lead = text
+					return true
+				}
+			case "figure", "blockquote", "ul", "ol", "table", "pre":
+			default:
+				if find(c) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	find(root)
+	return lead
+}
+
+// sanitize copies n into dst, keeping only allowed elements and attributes.
+func sanitize(dst, n *html.Node, base string, inPre bool) {
+	switch n.Type {
+	case html.TextNode:
+		text := n.Data
+		if !inPre {
+			text = collapseSpace(text)
+		}
+		if text != "" {
+			dst.AppendChild(&html.Node{Type: html.TextNode, Data: text})
+		}
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if droppedTags[n.Data] || isClutter(n) {
+		return
+	}
+
+	name, block := keptBlocks[n.Data]
+	if !block {
+		var inline bool
+		if name, inline = keptInline[n.Data]; !inline {
+			// Unwrapped: keep the children only. A block container still
+			// separates what comes before it from what comes after, which
+			// wrapLooseText turns into separate paragraphs.
+			boundary := blockContainers[n.Data] && (dst.Data == "div" || dst.Data == "blockquote")
+			if boundary {
+				dst.AppendChild(&html.Node{Type: html.ElementNode, Data: "br", DataAtom: atom.Br})
+			}
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				sanitize(dst, c, base, inPre)
+			}
+			if boundary {
+				dst.AppendChild(&html.Node{Type: html.ElementNode, Data: "br", DataAtom: atom.Br})
+			}
+			return
+		}
+	}
+
+	out := &html.Node{Type: html.ElementNode, Data: name, DataAtom: atom.Lookup([]byte(name))}
+	switch name {
+	case "a":
+		href := resolve(base, attr(n, "href"))
+		if !safeURL(href) {
+			// A link going nowhere, or somewhere the reader should not
+			// follow, is just its text
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				sanitize(dst, c, base, inPre)
+			}
+			return
+		}
+		out.Attr = []html.Attribute{{Key: "href", Val: href}}
+	case "img":
+		src := resolve(base, imageSource(n))
+		if !safeURL(src) || attr(n, "width") == "1" || attr(n, "height") == "1" {
+			return
+		}
+		out.Attr = []html.Attribute{{Key: "src", Val: src}}
+		if alt := strings.TrimSpace(attr(n, "alt")); alt != "" {
+			out.Attr = append(out.Attr, html.Attribute{Key: "alt", Val: alt})
+		}
+	case "td", "th":
+		for _, key := range []string{"colspan", "rowspan"} {
+			if v := attr(n, key); v != "" {
+				out.Attr = append(out.Attr, html.Attribute{Key: key, Val: v})
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sanitize(out, c, base, inPre || name == "pre")
+	}
+	dst.AppendChild(out)
+}
+
+// isClutter reports blocks inside the article that are still boilerplate:
+// share bars, "read more" link lists and headings marked as such by class.
+func isClutter(n *html.Node) bool {
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		return classWeight(n) < 0 || linkDensity(n) > 0.33
+	case "ul", "ol", "table", "div", "section", "header", "dl":
+		if classWeight(n) < 0 {
+			return true
+		}
+		text := innerText(n)
+		hasImage := images.MatchFirst(n) != nil
+		return len(text) < 1000 && linkDensity(n) > 0.5 && !hasImage
+	}
+	return false
+}
+
+// isInline reports text and the kept elements that belong inside a paragraph.
+func isInline(n *html.Node) bool {
+	if n.Type == html.TextNode {
+		return true
+	}
+	_, ok := keptInline[n.Data]
+	return ok
+}
+
+// wrapLooseText wraps runs of text and inline elements sitting directly in
+// root or a blockquote into paragraphs.
+func wrapLooseText(root *html.Node) {
+	var run []*html.Node
+	flush := func(before *html.Node) {
+		if len(run) == 0 {
+			return
+		}
+		p := &html.Node{Type: html.ElementNode, Data: "p", DataAtom: atom.P}
+		root.InsertBefore(p, before)
+		for _, n := range run {
+			root.RemoveChild(n)
+			p.AppendChild(n)
+		}
+		run = nil
+	}
+	for c := root.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch {
+		case c.Type == html.ElementNode && c.Data == "br":
+			flush(c)
+			root.RemoveChild(c)
+		case isInline(c):
+			run = append(run, c)
+		default:
+			flush(c)
+			if c.Data == "blockquote" {
+				wrapLooseText(c)
+			}
+		}
+		c = next
+	}
+	flush(nil)
+}
+
+// pruneEmpty removes elements with no text and no image, and trims
+// whitespace-only text at the edges of blocks.
+func pruneEmpty(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode {
+			pruneEmpty(c)
+			if c.Data != "br" && c.Data != "img" && c.Data != "hr" && c.Data != "td" && c.Data != "th" &&
+				strings.TrimSpace(innerText(c)) == "" && images.MatchFirst(c) == nil {
+				n.RemoveChild(c)
+			}
+		}
+		c = next
+	}
+	if _, block := keptBlocks[n.Data]; block && n.Data != "pre" {
+		if f := n.FirstChild; f != nil && f.Type == html.TextNode {
+			if f.Data = strings.TrimLeft(f.Data, " "); f.Data == "" {
+				n.RemoveChild(f)
+			}
+		}
+		if l := n.LastChild; l != nil && l.Type == html.TextNode {
+			if l.Data = strings.TrimRight(l.Data, " "); l.Data == "" {
+				n.RemoveChild(l)
+			}
+		}
+	}
+}
+
+// ----------------------
+// Text output
+// ----------------------
+
+// textWriter renders sanitized HTML as text: blocks are separated by blank
+// lines, list items are prefixed with "- " and <br> becomes a line break.
+type textWriter struct {
+	paras []string
+	cur   strings.Builder
+}
+
+func (t *textWriter) write(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.TextNode:
+			t.cur.WriteString(c.Data)
+			continue
+		case html.ElementNode:
+		default:
+			continue
+		}
+
+		switch c.Data {
+		case "br":
+			t.cur.WriteString("\n")
+		case "img", "hr":
+		case "li":
+			t.flush()
+			t.cur.WriteString("- ")
+			t.write(c)
+			t.flush()
+		case "td", "th":
+			t.write(c)
+			t.cur.WriteString("\t")
+		default:
+			if _, block := keptBlocks[c.Data]; block {
+				t.flush()
+				t.write(c)
+				t.flush()
+			} else {
+				t.write(c)
+			}
+		}
+	}
+}
+
+func (t *textWriter) flush() {
+	lines := strings.Split(t.cur.String(), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+	if s := strings.TrimSpace(strings.Join(lines, "\n")); s != "" {
+		t.paras = append(t.paras, s)
+	}
+	t.cur.Reset()
+}
+
+func (t *textWriter) String() string {
+	t.flush()
+	return strings.Join(t.paras, "\n\n")
+}
+
+// ----------------------
+// DOM helpers
+// ----------------------
+
+func attr(n *html.Node, name string) string {
+	return css.Attr(n, name)
+}
+
+func lookup(n *html.Node, name string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && strings.EqualFold(a.Key, name) {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// innerText is n's text with whitespace collapsed.
+func innerText(n *html.Node) string {
+	return css.Text(n)
+}
+
+func collapseSpace(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return " "
+	}
+	lead := s[0] == ' ' || s[0] == '\n' || s[0] == '\t' || s[0] == '\r'
+	last := s[len(s)-1]
+	trail := last == ' ' || last == '\n' || last == '\t' || last == '\r'
+	out := strings.Join(strings.Fields(s), " ")
+	if lead {
+		out = " " + out
+	}
+	if trail {
+		out += " "
+	}
+	return out
+}
+
+// imageSource prefers lazy-loading attributes over src, which often holds a
+// placeholder.
+func imageSource(n *html.Node) string {
+	for _, name := range []string{"data-src", "data-lazy-src", "data-original", "src"} {
+		if v := strings.TrimSpace(attr(n, name)); v != "" && !strings.HasPrefix(v, "data:") {
+			return v
+		}
+	}
+	for _, name := range []string{"srcset", "data-srcset"} {
+		if f := strings.Fields(strings.Split(attr(n, name), ",")[0]); len(f) > 0 {
+			return f[0]
+		}
+	}
+	return ""
+}
+
+// safeSchemes are the URL schemes kept on links and images. Anything else,
+// such as javascript: or data:text/html, could run in the reader view.
+var safeSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// safeURL reports whether the resolved URL u uses one of safeSchemes.
+// Relative URLs that could not be resolved are dropped as well.
+func safeURL(u string) bool {
+	p, err := url.Parse(u)
+	return err == nil && safeSchemes[strings.ToLower(p.Scheme)]
+}
+
+// resolve resolves ref against base, returning ref unchanged if either does
+// not parse.
+func resolve(base, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || base == "" {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil || r.IsAbs() {
+		return ref
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
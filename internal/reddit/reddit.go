@@ -0,0 +1,372 @@
+// Package reddit reads subreddit listings from Reddit's public JSON endpoints
+// (https://www.reddit.com/r/<subreddit>/<sort>.json), paging with the listing's
+// "after" cursor.
+package reddit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"nous-app/internal/fetcher"
+	"nous-app/internal/models"
+)
+
+// DefaultBaseURL is Reddit's public web root.
+const DefaultBaseURL = "https://www.reddit.com"
+
+// DefaultUserAgent is sent when the source sets none. Reddit throttles
+// generic user agents heavily, so sources should set their own in Headers.
+const DefaultUserAgent = "desktop:nous-app:v1.0 (P2P news reader)"
+
+// MaxLimit is the largest page Reddit returns.
+const MaxLimit = 100
+
+// Valid values for Listing.Sort and Listing.Time.
+var (
+	Sorts = []string{"hot", "new", "top", "rising", "controversial"}
+	Times = []string{"hour", "day", "week", "month", "year", "all"}
+)
+
+// Listing describes one subreddit listing request.
+type Listing struct {
+	Subreddit string `json:"subreddit"`       // e.g. "news", without "r/"
+	Sort      string `json:"sort,omitempty"`  // One of Sorts, default "hot"
+	Time      string `json:"time,omitempty"`  // One of Times, for "top" and "controversial"
+	Limit     int    `json:"limit,omitempty"` // Posts per page, 1..MaxLimit (default 25)
+	After     string `json:"after,omitempty"` // Cursor from a previous page
+}
+
+// Validate reports listings Reddit would reject or silently ignore.
+func (l Listing) Validate() error {
+	if strings.TrimSpace(l.Subreddit) == "" {
+		return fmt.Errorf("reddit: subreddit is required")
+	}
+	if l.Sort != "" && !contains(Sorts, l.Sort) {
+		return fmt.Errorf("reddit: unknown sort %q", l.Sort)
+	}
+	if l.Time != "" && !contains(Times, l.Time) {
+		return fmt.Errorf("reddit: unknown time window %q", l.Time)
+	}
+	if l.Limit < 0 || l.Limit > MaxLimit {
+		return fmt.Errorf("reddit: limit must be between 1 and %d", MaxLimit)
+	}
+	return nil
+}
+
+// URL returns the listing's JSON URL against baseURL (DefaultBaseURL if empty).
+func (l Listing) URL(baseURL string) string {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	sort := l.Sort
+	if sort == "" {
+		sort = "hot"
+	}
+	sub := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(l.Subreddit), "/"), "r/")
+
+	q := url.Values{"raw_json": {"1"}} // unescaped HTML in selftext_html and titles
+	if l.Limit > 0 {
+		q.Set("limit", strconv.Itoa(l.Limit))
+	}
+	if l.Time != "" && (sort == "top" || sort == "controversial") {
+		q.Set("t", l.Time)
+	}
+	if l.After != "" {
+		q.Set("after", l.After)
+	}
+	return fmt.Sprintf("%s/r/%s/%s.json?%s", strings.TrimSuffix(baseURL, "/"), url.PathEscape(sub), sort, q.Encode())
+}
+
+// ParseListingURL recovers a Listing from a Source endpoint such as
+// "https://www.reddit.com/r/news/top/.json?t=week".
+func ParseListingURL(endpoint string) (Listing, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return Listing{}, fmt.Errorf("reddit: invalid endpoint: %w", err)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "r" {
+		return Listing{}, fmt.Errorf("reddit: endpoint %q is not a subreddit listing", endpoint)
+	}
+
+	// The listing may end in ".json" either way: /r/news.json or /r/news/top.json
+	l := Listing{Subreddit: strings.TrimSuffix(parts[1], ".json"), Sort: "hot"}
+	if len(parts) >= 3 {
+		if sort := strings.TrimSuffix(parts[2], ".json"); contains(Sorts, sort) {
+			l.Sort = sort
+		}
+	}
+	q := u.Query()
+	l.Time = q.Get("t")
+	l.After = q.Get("after")
+	if n, err := strconv.Atoi(q.Get("limit")); err == nil {
+		l.Limit = n
+	}
+	return l, l.Validate()
+}
+
+// Post is the data of a t3 (link) thing. Score and NumComments are kept in
+// Article.Raw.
+type Post struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"` // Fullname, e.g. "t3_abc123"
+	Subreddit    string  `json:"subreddit"`
+	Title        string  `json:"title"`
+	Author       string  `json:"author"`
+	URL          string  `json:"url"` // Linked page, or the post itself for self posts
+	Permalink    string  `json:"permalink"`
+	Domain       string  `json:"domain"`
+	IsSelf       bool    `json:"is_self"`
+	Selftext     string  `json:"selftext,omitempty"`
+	SelftextHTML string  `json:"selftext_html,omitempty"`
+	Thumbnail    string  `json:"thumbnail,omitempty"`
+	CreatedUTC   float64 `json:"created_utc"`
+	Score        int     `json:"score"`
+	UpvoteRatio  float64 `json:"upvote_ratio"`
+	NumComments  int     `json:"num_comments"`
+	Over18       bool    `json:"over_18"`
+	Stickied     bool    `json:"stickied"`
+	LinkFlair    string  `json:"link_flair_text,omitempty"`
+	Preview      *struct {
+		Images []struct {
+			Source struct {
+				URL string `json:"url"`
+			} `json:"source"`
+		} `json:"images"`
+	} `json:"preview,omitempty"`
+}
+
+// ParseListing decodes a listing response into its posts and the cursor for
+// the next page ("" on the last page).
+func ParseListing(data []byte) ([]Post, string, error) {
+	var res struct {
+		Kind string `json:"kind"`
+		Data struct {
+			After    string `json:"after"`
+			Children []struct {
+				Kind string `json:"kind"`
+				Data Post   `json:"data"`
+			} `json:"children"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, "", fmt.Errorf("reddit: invalid listing: %w", err)
+	}
+	if res.Kind != "Listing" {
+		return nil, "", fmt.Errorf("reddit: expected a Listing, got %q", res.Kind)
+	}
+
+	posts := make([]Post, 0, len(res.Data.Children))
+	for _, child := range res.Data.Children {
+		if child.Kind == "t3" {
+			posts = append(posts, child.Data)
+		}
+	}
+	return posts, res.Data.After, nil
+}
+
+// Client reads listings.
+type Client struct {
+	BaseURL string           // Defaults to DefaultBaseURL
+	Fetcher *fetcher.Fetcher // Sends the requests; defaults to a Fetcher of its own
+	Source  models.Source    // Headers, auth and rate limit; User-Agent defaults to DefaultUserAgent
+}
+
+// ForSource returns a client that makes its requests through f with the
+// headers, auth and rate limit of src.
+func ForSource(f *fetcher.Fetcher, src models.Source) *Client {
+	return &Client{Fetcher: f, Source: src}
+}
+
+// Page fetches one page of a listing.
+func (c *Client) Page(ctx context.Context, l Listing) ([]Post, string, error) {
+	if err := l.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	endpoint := l.URL(c.BaseURL)
+	log.Printf("[Reddit] GET %s\n", endpoint)
+
+	f := c.Fetcher
+	if f == nil {
+		f = &fetcher.Fetcher{}
+	}
+	body, err := f.Get(ctx, withUserAgent(c.Source), endpoint)
+	if err != nil {
+		return nil, "", fmt.Errorf("reddit: %w", err)
+	}
+	return ParseListing(body)
+}
+
+// withUserAgent returns src with DefaultUserAgent added to its headers
+// unless they set a User-Agent already.
+func withUserAgent(src models.Source) models.Source {
+	for k := range src.Headers {
+		if strings.EqualFold(k, "User-Agent") {
+			return src
+		}
+	}
+	headers := make(map[string]string, len(src.Headers)+1)
+	for k, v := range src.Headers {
+		headers[k] = v
+	}
+	headers["User-Agent"] = DefaultUserAgent
+	src.Headers = headers
+	return src
+}
+
+// Posts follows the "after" cursor for up to maxPages pages.
+func (c *Client) Posts(ctx context.Context, l Listing, maxPages int) ([]Post, error) {
+	if maxPages <= 0 {
+		maxPages = 1
+	}
+	var all []Post
+	for page := 0; page < maxPages; page++ {
+		posts, after, err := c.Page(ctx, l)
+		if err != nil {
+			if len(all) > 0 && ctx.Err() == nil {
+				log.Printf("[Reddit] Stopping after %d page(s): %v\n", page, err)
+				return all, nil
+			}
+			return nil, err
+		}
+		all = append(all, posts...)
+		if after == "" {
+			break
+		}
+		l.After = after
+	}
+	return all, nil
+}
+
+// FetchSource reads up to maxPages pages of the listing src.Endpoint points
+// at, making its requests through f.
+func FetchSource(ctx context.Context, f *fetcher.Fetcher, src models.Source, maxPages int) ([]models.Article, error) {
+	l, err := ParseListingURL(src.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	c := ForSource(f, src)
+	if u, err := url.Parse(src.Endpoint); err == nil && u.Host != "" {
+		c.BaseURL = u.Scheme + "://" + u.Host
+	}
+	posts, err := c.Posts(ctx, l, maxPages)
+	if err != nil {
+		return nil, err
+	}
+	return ToArticles(posts, src), nil
+}
+
+// ----------------------
+// Article mapping
+// ----------------------
+
+// ToArticles maps posts onto Articles. Stickied posts are skipped. Link posts
+// point at the linked page, self posts at their permalink. Raw holds the
+// Post, including Score and NumComments.
+func ToArticles(posts []Post, src models.Source) []models.Article {
+	articles := make([]models.Article, 0, len(posts))
+	for _, p := range posts {
+		if p.Stickied {
+			continue
+		}
+		post := p
+
+		permalink := "https://www.reddit.com" + p.Permalink
+		link := p.URL
+		if p.IsSelf || link == "" {
+			link = permalink
+		}
+		title := strings.TrimSpace(html.UnescapeString(p.Title))
+		if title == "" {
+			title = "Untitled"
+		}
+
+		a := models.Article{
+			ID:         p.ID,
+			Title:      title,
+			URL:        link,
+			Parser:     src.Parser,
+			Normalizer: src.Normalizer,
+			Confidence: src.Confidence,
+			Language:   src.Language,
+			Raw:        &post,
+			SourceMeta: &models.SourceMeta{Name: src.Name, Bias: src.Bias, Confidence: src.Confidence},
+		}
+		if a.Language == nil {
+			en := "en"
+			a.Language = &en
+		}
+		if src.Name != "" {
+			name := src.Name
+			a.Source = &name
+		}
+		if p.Subreddit != "" {
+			a.Categories = []string{"r/" + p.Subreddit}
+		}
+		if p.LinkFlair != "" {
+			a.Tags = []string{p.LinkFlair}
+		}
+		if p.Author != "" && p.Author != "[deleted]" {
+			author := p.Author
+			a.Author = &author
+		}
+		if p.SelftextHTML != "" {
+			content := p.SelftextHTML
+			a.Content = &content
+		} else if p.Selftext != "" {
+			content := p.Selftext
+			a.Content = &content
+		}
+		if p.Selftext != "" {
+			summary := p.Selftext
+			a.Summary = &summary
+		}
+		if p.Domain != "" && !p.IsSelf {
+			domain := strings.TrimPrefix(p.Domain, "www.")
+			a.SourceDomain = &domain
+		}
+		if image := postImage(p); image != "" {
+			a.Image = &image
+		}
+		if p.CreatedUTC > 0 {
+			iso := time.Unix(int64(p.CreatedUTC), 0).UTC().Format(time.RFC3339)
+			a.PublishedAt = &iso
+		}
+		edition := models.EditionOther
+		a.Edition = &edition
+
+		articles = append(articles, a)
+	}
+	return articles
+}
+
+// postImage returns the preview image, falling back to the thumbnail. Reddit
+// uses placeholders like "self" and "default" when there is no thumbnail.
+func postImage(p Post) string {
+	if p.Preview != nil && len(p.Preview.Images) > 0 {
+		if u := html.UnescapeString(p.Preview.Images[0].Source.URL); u != "" {
+			return u
+		}
+	}
+	if strings.HasPrefix(p.Thumbnail, "http") {
+		return p.Thumbnail
+	}
+	return ""
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package reddit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nous-app/internal/fetcher"
+	"nous-app/internal/models"
+)
+
+// listing is a one-post page whose cursor is after.
+func listing(id, after string) string {
+	return fmt.Sprintf(`{"kind": "Listing", "data": {"after": %q, "children": [
+		{"kind": "t3", "data": {"id": %q, "name": "t3_%s", "title": "Post %s", "url": "https://a.example/%s",
+		 "permalink": "/r/news/comments/%s/", "created_utc": 1700000000}}]}}`, after, id, id, id, id, id)
+}
+
+func TestFetchSource(t *testing.T) {
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if r.URL.Query().Get("after") == "" {
+			fmt.Fprint(w, listing("a", "t3_a"))
+		} else {
+			fmt.Fprint(w, listing("b", ""))
+		}
+	}))
+	defer srv.Close()
+
+	src := models.Source{Name: "r/news", Endpoint: srv.URL + "/r/news/top.json?t=week", Parser: "reddit"}
+	articles, err := FetchSource(context.Background(), fetcher.New(1), src, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 2 || len(requests) != 2 {
+		t.Fatalf("got %d articles in %d requests, want 2 in 2", len(articles), len(requests))
+	}
+	first := requests[0]
+	if first.URL.Path != "/r/news/top.json" || first.URL.Query().Get("t") != "week" {
+		t.Errorf("requested %s", first.URL)
+	}
+	if ua := first.Header.Get("User-Agent"); ua != DefaultUserAgent {
+		t.Errorf("User-Agent = %q, want %q", ua, DefaultUserAgent)
+	}
+}
+
+func TestPageSourceHeaders(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		fmt.Fprint(w, listing("a", ""))
+	}))
+	defer srv.Close()
+
+	headers := map[string]string{"user-agent": "my-bot/1.0"}
+	auth, token := fetcher.AuthBearerToken, "token"
+	c := ForSource(fetcher.New(1), models.Source{Name: "r/go", Headers: headers, AuthType: &auth, APIKey: &token})
+	c.BaseURL = srv.URL
+	if _, _, err := c.Page(context.Background(), Listing{Subreddit: "golang"}); err != nil {
+		t.Fatal(err)
+	}
+	if got.Header.Get("User-Agent") != "my-bot/1.0" || got.Header.Get("Authorization") != "Bearer token" {
+		t.Errorf("headers %v, want the source's User-Agent and auth", got.Header)
+	}
+	if len(headers) != 1 {
+		t.Errorf("Page changed the source's headers: %v", headers)
+	}
+}
@@ -0,0 +1,394 @@
+// Package search is a local full-text index over stored articles. It indexes
+// Title, Summary, Content, Tags and Author into an inverted index with word
+// positions, ranks matches with BM25F (BM25 with per-field weights), supports
+// phrase, prefix and per-field queries plus source, bias, edition, language
+// and date filters, and persists itself to a single file.
+package search
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nous-app/internal/cluster"
+	"nous-app/internal/models"
+	"nous-app/internal/persist"
+)
+
+// Indexed fields.
+const (
+	FieldTitle = iota
+	FieldSummary
+	FieldContent
+	FieldTags
+	FieldAuthor
+	numFields
+)
+
+var fieldNames = [numFields]string{"title", "summary", "content", "tags", "author"}
+
+// fieldWeights scale a field's term frequencies in BM25F: a word in the title
+// says more about an article than the same word deep in its content.
+var fieldWeights = [numFields]float64{3, 1.5, 1, 2, 1.5}
+
+// formatVersion is bumped whenever the persisted layout changes; an index
+// file of another version is discarded and rebuilt.
+const formatVersion = 1
+
+// Doc is the indexed form of an article: the fields needed to filter and
+// display a hit, plus what is needed to update or remove it.
+type Doc struct {
+	ID        string
+	URL       string
+	Title     string
+	Source    string
+	Bias      string // Normalised, see cluster.NormalizeBias
+	Edition   string
+	Language  string
+	Published int64 // Unix seconds; 0 if unknown
+	Len       [numFields]uint32
+	Terms     []string // Distinct terms, to remove the doc from their postings
+	Sum       uint64   // Checksum of the indexed fields, to skip unchanged articles
+}
+
+// Posting holds the positions of a term in one doc, per field.
+type Posting struct {
+	Pos [numFields][]uint32
+}
+
+// Index is an inverted index of articles. It is safe for concurrent use.
+type Index struct {
+	path string
+
+	mu       sync.RWMutex
+	docs     map[uint32]*Doc
+	keys     map[string]uint32 // Article ID (or URL when it has none) → doc
+	urls     map[string]uint32
+	postings map[string]map[uint32]*Posting
+	totalLen [numFields]uint64
+	next     uint32
+	sorted   []string // Sorted terms for prefix queries; nil when stale
+	gen      uint64   // Bumped on every change
+	flushed  uint64   // gen at the last flush
+
+	flushMu sync.Mutex // Serialises Flush
+}
+
+// snapshot is the persisted form of an Index.
+type snapshot struct {
+	Version  int
+	Next     uint32
+	Docs     map[uint32]*Doc
+	Postings map[string]map[uint32]*Posting
+}
+
+// Open creates an index persisted at path, loading it if the file exists.
+// A missing file starts an empty index; an unreadable or outdated one starts
+// an empty index and reports why, so the caller can rebuild it.
+func Open(path string) (*Index, error) {
+	x := newIndex(path)
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return x, nil
+	}
+	if err != nil {
+		return x, fmt.Errorf("failed to read search index: %w", err)
+	}
+	defer f.Close()
+
+	var snap snapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil {
+		return x, fmt.Errorf("failed to parse search index: %w", err)
+	}
+	if snap.Version != formatVersion {
+		return x, fmt.Errorf("search index has format %d, want %d", snap.Version, formatVersion)
+	}
+
+	x.next = snap.Next
+	if snap.Docs != nil {
+		x.docs = snap.Docs
+	}
+	if snap.Postings != nil {
+		x.postings = snap.Postings
+	}
+	for n, d := range x.docs {
+		x.keys[models.ArticleKey(d.ID, d.URL)] = n
+		if d.URL != "" {
+			x.urls[d.URL] = n
+		}
+		for f, l := range d.Len {
+			x.totalLen[f] += uint64(l)
+		}
+	}
+	return x, nil
+}
+
+func newIndex(path string) *Index {
+	return &Index{
+		path:     path,
+		docs:     make(map[uint32]*Doc),
+		keys:     make(map[string]uint32),
+		urls:     make(map[string]uint32),
+		postings: make(map[string]map[uint32]*Posting),
+	}
+}
+
+// Len returns the number of indexed articles.
+func (x *Index) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.docs)
+}
+
+// Put indexes an article, replacing its previous version. biasOf looks up
+// the bias of a source by name for articles whose metadata has none; it may
+// be nil. It reports whether the index changed.
+func (x *Index) Put(a models.Article, biasOf func(string) string) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.put(a, biasOf)
+}
+
+// Delete removes the article with the given ID or URL. It reports whether
+// the article was indexed.
+func (x *Index) Delete(idOrURL string) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	n, ok := x.keys[idOrURL]
+	if !ok {
+		n, ok = x.urls[idOrURL]
+	}
+	if !ok {
+		return false
+	}
+	x.remove(n)
+	return true
+}
+
+// Sync makes the index match articles: new and changed articles are indexed
+// and indexed articles missing from the list are removed. It returns how many
+// articles were added or updated and how many were removed.
+func (x *Index) Sync(articles []models.Article, biasOf func(string) string) (updated, removed int) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	keep := make(map[uint32]bool, len(articles))
+	for _, a := range articles {
+		if x.put(a, biasOf) {
+			updated++
+		}
+		if n, ok := x.keys[models.ArticleKey(a.ID, a.URL)]; ok {
+			keep[n] = true
+		}
+	}
+	for n := range x.docs {
+		if !keep[n] {
+			x.remove(n)
+			removed++
+		}
+	}
+	return updated, removed
+}
+
+// Flush writes the index to disk if it changed since the last flush.
+func (x *Index) Flush() error {
+	x.flushMu.Lock()
+	defer x.flushMu.Unlock()
+
+	x.mu.RLock()
+	gen := x.gen
+	if gen == x.flushed {
+		x.mu.RUnlock()
+		return nil
+	}
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(snapshot{
+		Version:  formatVersion,
+		Next:     x.next,
+		Docs:     x.docs,
+		Postings: x.postings,
+	})
+	x.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode search index: %w", err)
+	}
+
+	if err := persist.WriteFile(x.path, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	x.mu.Lock()
+	x.flushed = gen
+	x.mu.Unlock()
+	return nil
+}
+
+// put indexes a; callers hold x.mu.
+func (x *Index) put(a models.Article, biasOf func(string) string) bool {
+	key := models.ArticleKey(a.ID, a.URL)
+	if key == "" {
+		return false
+	}
+
+	var fields [numFields][]string
+	fields[FieldTitle] = tokens(a.Title)
+	fields[FieldSummary] = tokens(deref(a.Summary))
+	fields[FieldContent] = tokens(deref(a.Content))
+	fields[FieldAuthor] = tokens(deref(a.Author))
+
+	doc := &Doc{
+		ID:       a.ID,
+		URL:      a.URL,
+		Title:    a.Title,
+		Source:   sourceName(a),
+		Language: strings.ToLower(deref(a.Language)),
+	}
+	if a.Edition != nil {
+		doc.Edition = strings.ToLower(string(*a.Edition))
+	}
+	if t := cluster.ArticleTime(a); !t.IsZero() {
+		doc.Published = t.Unix()
+	}
+	doc.Bias = articleBias(a, doc.Source, biasOf)
+	doc.Sum = checksum(a, doc)
+
+	if n, ok := x.keys[key]; ok {
+		if x.docs[n].Sum == doc.Sum {
+			return false
+		}
+		x.remove(n)
+	}
+
+	n := x.next
+	x.next++
+	terms := map[string]bool{}
+	addTerm := func(f int, term string, pos uint32) {
+		p := x.postings[term]
+		if p == nil {
+			p = make(map[uint32]*Posting)
+			x.postings[term] = p
+			x.sorted = nil
+		}
+		post := p[n]
+		if post == nil {
+			post = &Posting{}
+			p[n] = post
+		}
+		post.Pos[f] = append(post.Pos[f], pos)
+		terms[term] = true
+	}
+
+	for f, words := range fields {
+		for i, w := range words {
+			addTerm(f, w, uint32(i))
+		}
+		doc.Len[f] = uint32(len(words))
+	}
+	var pos uint32
+	for _, tag := range a.Tags {
+		for _, w := range tokens(tag) {
+			addTerm(FieldTags, w, pos)
+			pos++
+			doc.Len[FieldTags]++
+		}
+		pos += tagGap
+	}
+
+	for t := range terms {
+		doc.Terms = append(doc.Terms, t)
+	}
+	sort.Strings(doc.Terms)
+	for f, l := range doc.Len {
+		x.totalLen[f] += uint64(l)
+	}
+
+	x.docs[n] = doc
+	x.keys[key] = n
+	if doc.URL != "" {
+		x.urls[doc.URL] = n
+	}
+	x.gen++
+	return true
+}
+
+// remove drops doc n; callers hold x.mu.
+func (x *Index) remove(n uint32) {
+	d, ok := x.docs[n]
+	if !ok {
+		return
+	}
+	for _, t := range d.Terms {
+		if p := x.postings[t]; p != nil {
+			delete(p, n)
+			if len(p) == 0 {
+				delete(x.postings, t)
+				x.sorted = nil
+			}
+		}
+	}
+	for f, l := range d.Len {
+		x.totalLen[f] -= uint64(l)
+	}
+	delete(x.docs, n)
+	if x.keys[models.ArticleKey(d.ID, d.URL)] == n {
+		delete(x.keys, models.ArticleKey(d.ID, d.URL))
+	}
+	if x.urls[d.URL] == n {
+		delete(x.urls, d.URL)
+	}
+	x.gen++
+}
+
+func sourceName(a models.Article) string {
+	switch {
+	case a.Source != nil && *a.Source != "":
+		return *a.Source
+	case a.SourceMeta != nil && a.SourceMeta.Name != "":
+		return a.SourceMeta.Name
+	case a.SourceDomain != nil:
+		return *a.SourceDomain
+	}
+	return ""
+}
+
+func articleBias(a models.Article, source string, biasOf func(string) string) string {
+	if a.SourceMeta != nil {
+		if b := cluster.NormalizeBias(a.SourceMeta.Bias); b != models.BiasUnknown {
+			return b
+		}
+	}
+	if biasOf != nil && source != "" {
+		return cluster.NormalizeBias(biasOf(source))
+	}
+	return models.BiasUnknown
+}
+
+// checksum covers everything put derives from an article.
+func checksum(a models.Article, d *Doc) uint64 {
+	h := fnv.New64a()
+	for _, s := range []string{
+		a.ID, a.URL, a.Title, deref(a.Summary), deref(a.Content), deref(a.Author),
+		strings.Join(a.Tags, "\x1f"), d.Source, d.Bias, d.Edition, d.Language,
+		time.Unix(d.Published, 0).UTC().Format(time.RFC3339),
+	} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,131 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nous-app/internal/models"
+)
+
+func count(t *testing.T, x *Index, text string) int {
+	t.Helper()
+	res, err := x.Search(Query{Text: text})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.Total
+}
+
+func TestPutDelete(t *testing.T) {
+	x := newIndex(filepath.Join(t.TempDir(), "search.index"))
+	a := models.Article{ID: "a", URL: "https://a.example/1", Title: "Old title"}
+	if !x.Put(a, nil) || x.Len() != 1 {
+		t.Fatal("Put did not index the article")
+	}
+	if x.Put(a, nil) {
+		t.Error("Put of an unchanged article reported a change")
+	}
+	if x.Put(models.Article{Title: "No ID or URL"}, nil) {
+		t.Error("indexed an article without ID or URL")
+	}
+
+	a.Title = "New title"
+	if !x.Put(a, nil) || x.Len() != 1 {
+		t.Fatal("update added a second doc")
+	}
+	if count(t, x, "old") != 0 || count(t, x, "new") != 1 {
+		t.Error("update left the old words indexed")
+	}
+
+	// Deleted by URL as well as by ID
+	if !x.Delete("https://a.example/1") || x.Len() != 0 {
+		t.Error("Delete by URL failed")
+	}
+	if x.Delete("a") {
+		t.Error("Delete of a removed article reported it as indexed")
+	}
+	if len(x.postings) != 0 || x.totalLen != [numFields]uint64{} {
+		t.Errorf("postings %v, lengths %v left after deleting everything", x.postings, x.totalLen)
+	}
+
+	// Articles without an ID are keyed by URL
+	x.Put(models.Article{URL: "https://b.example/2", Title: "Keyed by URL"}, nil)
+	if !x.Delete("https://b.example/2") {
+		t.Error("Delete of an article keyed by URL failed")
+	}
+}
+
+func TestSync(t *testing.T) {
+	x := newIndex(filepath.Join(t.TempDir(), "search.index"))
+	x.Put(models.Article{ID: "keep", Title: "Kept"}, nil)
+	x.Put(models.Article{ID: "change", Title: "Before"}, nil)
+	x.Put(models.Article{ID: "gone", Title: "Deleted"}, nil)
+
+	updated, removed := x.Sync([]models.Article{
+		{ID: "keep", Title: "Kept"},
+		{ID: "change", Title: "After"},
+		{ID: "new", Title: "Added"},
+	}, nil)
+	if updated != 2 || removed != 1 || x.Len() != 3 {
+		t.Errorf("Sync = %d updated, %d removed, %d docs; want 2, 1, 3", updated, removed, x.Len())
+	}
+	if count(t, x, "deleted") != 0 || count(t, x, "before") != 0 || count(t, x, "after") != 1 {
+		t.Error("index does not match the synced articles")
+	}
+}
+
+func TestFlushOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.index")
+	x := newIndex(path)
+
+	// Nothing to write yet
+	if err := x.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Flush of an unchanged index wrote a file")
+	}
+
+	x.Put(models.Article{ID: "a", Title: "Interest rates", Tags: []string{"economy"}}, nil)
+	x.Put(models.Article{ID: "b", Title: "Other news"}, nil)
+	if err := x.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	y, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if y.Len() != 2 || y.totalLen != x.totalLen {
+		t.Errorf("reopened: %d docs, lengths %v; want 2, %v", y.Len(), y.totalLen, x.totalLen)
+	}
+	if count(t, y, "tag:economy") != 1 || count(t, y, `"interest rates"`) != 1 {
+		t.Error("reopened index does not find the articles")
+	}
+	if y.Put(models.Article{ID: "a", Title: "Interest rates", Tags: []string{"economy"}}, nil) {
+		t.Error("reopened index lost the checksums")
+	}
+	// New docs do not reuse the numbers of loaded ones
+	y.Put(models.Article{ID: "c", Title: "Third"}, nil)
+	if y.Len() != 3 {
+		t.Errorf("Len() = %d after adding to the reopened index, want 3", y.Len())
+	}
+	if !y.Delete("b") || count(t, y, "other") != 0 {
+		t.Error("Delete on the reopened index failed")
+	}
+}
+
+func TestOpenBadFile(t *testing.T) {
+	dir := t.TempDir()
+	if x, err := Open(filepath.Join(dir, "missing.index")); err != nil || x.Len() != 0 {
+		t.Errorf("Open of a missing file = %v, %v", x, err)
+	}
+
+	path := filepath.Join(dir, "bad.index")
+	os.WriteFile(path, []byte("not gob"), 0644)
+	x, err := Open(path)
+	if err == nil || x == nil || x.Len() != 0 {
+		t.Errorf("Open of a bad file = %v, %v; want an empty index and an error", x, err)
+	}
+}
@@ -0,0 +1,191 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"nous-app/internal/cluster"
+)
+
+// Query is a search request. Text holds the words to look for and may use
+// the query syntax below; the other fields are filters that every hit must
+// pass, in addition to any filters written in Text.
+//
+// Query syntax, all terms must match:
+//
+//	fed rates          both words, anywhere
+//	"interest rates"   the exact phrase
+//	infla*             any word starting with "infla"
+//	-opinion           excludes articles containing the word
+//	title:fed          a word (or title:"phrase", title:infla*) in one field:
+//	                   title, summary, content, tag(s) or author
+//	source:reuters     filters: source, bias, edition, lang(uage),
+//	after:2025-01-01   after/since and before/until (RFC3339 or a date)
+type Query struct {
+	Text     string `json:"text"`
+	Source   string `json:"source,omitempty"`   // Source name or domain, case-insensitive
+	Bias     string `json:"bias,omitempty"`     // left, lean-left, center, lean-right, right or unknown
+	Edition  string `json:"edition,omitempty"`  // e.g. "us"
+	Language string `json:"language,omitempty"` // ISO 639-1 code
+	Since    string `json:"since,omitempty"`    // RFC3339 time or YYYY-MM-DD, inclusive
+	Until    string `json:"until,omitempty"`    // RFC3339 time or YYYY-MM-DD, inclusive
+	Limit    int    `json:"limit,omitempty"`    // Hits per page; default DefaultLimit
+	Offset   int    `json:"offset,omitempty"`   // Hits to skip
+}
+
+// clause is one term, prefix or phrase of a parsed query.
+type clause struct {
+	field  int      // Field to match in, or -1 for any
+	words  []string // More than one for a phrase
+	prefix bool     // Last word is a prefix
+	negate bool
+}
+
+// filters are the constraints a doc must meet regardless of its score.
+type filters struct {
+	sources   []string
+	bias      []string
+	editions  []string
+	languages []string
+	since     time.Time
+	until     time.Time
+}
+
+var fieldKeys = map[string]int{
+	"title":   FieldTitle,
+	"summary": FieldSummary,
+	"content": FieldContent,
+	"body":    FieldContent,
+	"tag":     FieldTags,
+	"tags":    FieldTags,
+	"author":  FieldAuthor,
+	"by":      FieldAuthor,
+}
+
+// parse splits a query into clauses and filters.
+func parse(q Query) ([]clause, filters, error) {
+	var clauses []clause
+	var f filters
+
+	setFilter := func(key, value string) error {
+		switch key {
+		case "source", "site":
+			f.sources = append(f.sources, strings.TrimPrefix(strings.ToLower(value), "www."))
+		case "bias":
+			f.bias = append(f.bias, cluster.NormalizeBias(value))
+		case "edition":
+			f.editions = append(f.editions, strings.ToLower(value))
+		case "lang", "language":
+			f.languages = append(f.languages, strings.ToLower(value))
+		case "after", "since":
+			t, err := parseTime(value, false)
+			if err != nil {
+				return err
+			}
+			f.since = t
+		case "before", "until":
+			t, err := parseTime(value, true)
+			if err != nil {
+				return err
+			}
+			f.until = t
+		}
+		return nil
+	}
+
+	for _, kv := range [][2]string{
+		{"source", q.Source}, {"bias", q.Bias}, {"edition", q.Edition},
+		{"language", q.Language}, {"since", q.Since}, {"until", q.Until},
+	} {
+		if v := strings.TrimSpace(kv[1]); v != "" {
+			if err := setFilter(kv[0], v); err != nil {
+				return nil, f, err
+			}
+		}
+	}
+
+	for _, part := range splitQuery(q.Text) {
+		c := clause{field: -1}
+		if strings.HasPrefix(part, "-") && len(part) > 1 {
+			c.negate = true
+			part = part[1:]
+		}
+
+		if key, value, ok := strings.Cut(part, ":"); ok && value != "" && !strings.HasPrefix(key, `"`) {
+			key = strings.ToLower(key)
+			if field, isField := fieldKeys[key]; isField {
+				c.field = field
+				part = value
+			} else if isFilterKey(key) {
+				if err := setFilter(key, strings.Trim(value, `"`)); err != nil {
+					return nil, f, err
+				}
+				continue
+			}
+		}
+
+		quoted := strings.HasPrefix(part, `"`)
+		part = strings.Trim(part, `"`)
+		if !quoted && strings.HasSuffix(part, "*") {
+			c.prefix = true
+			part = strings.TrimRight(part, "*")
+		}
+		c.words = tokens(part)
+		if len(c.words) == 0 {
+			continue
+		}
+		clauses = append(clauses, c)
+	}
+	return clauses, f, nil
+}
+
+func isFilterKey(key string) bool {
+	switch key {
+	case "source", "site", "bias", "edition", "lang", "language", "after", "since", "before", "until":
+		return true
+	}
+	return false
+}
+
+// splitQuery splits on whitespace outside double quotes, keeping the quotes.
+func splitQuery(s string) []string {
+	var parts []string
+	var b strings.Builder
+	inQuote := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			b.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuote:
+			if b.Len() > 0 {
+				parts = append(parts, b.String())
+				b.Reset()
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() > 0 {
+		parts = append(parts, b.String())
+	}
+	return parts
+}
+
+// parseTime reads an RFC3339 time or a YYYY-MM-DD date. A date used as an
+// upper bound means the end of that day.
+func parseTime(s string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: want RFC3339 or YYYY-MM-DD", s)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+	return t, nil
+}
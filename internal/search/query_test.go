@@ -0,0 +1,86 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text    string
+		clauses []clause
+	}{
+		{"Fed rates", []clause{{field: -1, words: []string{"fed"}}, {field: -1, words: []string{"rates"}}}},
+		{`"interest rates" rise`, []clause{{field: -1, words: []string{"interest", "rates"}}, {field: -1, words: []string{"rise"}}}},
+		{"infla*", []clause{{field: -1, words: []string{"infla"}, prefix: true}}},
+		{`"infla*"`, []clause{{field: -1, words: []string{"infla"}}}},
+		{"-opinion", []clause{{field: -1, words: []string{"opinion"}, negate: true}}},
+		{"- alone", []clause{{field: -1, words: []string{"alone"}}}},
+		{"title:fed", []clause{{field: FieldTitle, words: []string{"fed"}}}},
+		{`title:"rate cut"`, []clause{{field: FieldTitle, words: []string{"rate", "cut"}}}},
+		{"TAG:econ* by:smith", []clause{{field: FieldTags, words: []string{"econ"}, prefix: true}, {field: FieldAuthor, words: []string{"smith"}}}},
+		{"-body:leak", []clause{{field: FieldContent, words: []string{"leak"}, negate: true}}},
+		{"unknown:key", []clause{{field: -1, words: []string{"unknown", "key"}}}},
+		{"title: ... !!", []clause{{field: -1, words: []string{"title"}}}},
+		{"... !!", nil},
+		{"source:Reuters", nil},
+	}
+	for _, tt := range tests {
+		clauses, _, err := parse(Query{Text: tt.text})
+		if err != nil {
+			t.Errorf("%q: %v", tt.text, err)
+			continue
+		}
+		if !reflect.DeepEqual(clauses, tt.clauses) {
+			t.Errorf("%q: clauses = %+v, want %+v", tt.text, clauses, tt.clauses)
+		}
+	}
+}
+
+func TestParseFilters(t *testing.T) {
+	q := Query{
+		Text:    `climate source:www.BBC.co.uk bias:"Lean Left" edition:UK lang:EN after:2024-01-01 before:2024-01-31`,
+		Source:  "Reuters",
+		Bias:    "center",
+		Edition: "us",
+	}
+	clauses, f, err := parse(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clauses) != 1 {
+		t.Errorf("clauses = %+v, want only climate", clauses)
+	}
+	want := filters{
+		sources:   []string{"reuters", "bbc.co.uk"},
+		bias:      []string{"center", "lean-left"},
+		editions:  []string{"us", "uk"},
+		languages: []string{"en"},
+		since:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		until:     time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(f, want) {
+		t.Errorf("filters = %+v, want %+v", f, want)
+	}
+
+	// An RFC3339 upper bound is taken as is
+	_, f, err = parse(Query{Until: "2024-01-31T12:00:00Z"})
+	if err != nil || !f.until.Equal(time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("until = %v, %v", f.until, err)
+	}
+
+	for _, q := range []Query{{Text: "after:yesterday"}, {Since: "01/02/2024"}} {
+		if _, _, err := parse(q); err == nil {
+			t.Errorf("%+v: invalid date accepted", q)
+		}
+	}
+}
+
+func TestTokens(t *testing.T) {
+	got := tokens(`Don't <a href="/x">Stop</a> me-now, café 2024 <3`)
+	want := []string{"dont", "stop", "me", "now", "café", "2024", "3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokens = %q, want %q", got, want)
+	}
+}
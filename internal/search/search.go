@@ -0,0 +1,328 @@
+package search
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"nous-app/internal/models"
+)
+
+// DefaultLimit is the page size when Query.Limit is not set, and MaxLimit
+// the largest page served.
+const (
+	DefaultLimit = 20
+	MaxLimit     = 200
+)
+
+// maxExpansions caps how many indexed words a prefix query matches.
+const maxExpansions = 256
+
+// BM25 parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Hit is one matching article.
+type Hit struct {
+	ID          string   `json:"id"`
+	URL         string   `json:"url"`
+	Title       string   `json:"title"`
+	Source      string   `json:"source,omitempty"`
+	Bias        string   `json:"bias,omitempty"`
+	PublishedAt string   `json:"publishedAt,omitempty"`
+	Score       float64  `json:"score"`
+	Fields      []string `json:"fields,omitempty"` // Fields the query matched in
+}
+
+// Results is a page of hits, best first.
+type Results struct {
+	Total  int   `json:"total"` // Matching articles across all pages
+	Offset int   `json:"offset"`
+	Hits   []Hit `json:"hits"`
+}
+
+// freqs holds a clause's weighted term frequency per field in one doc.
+type freqs [numFields]float64
+
+// Search runs a query. Hits are ranked by BM25F score; a query with filters
+// but no words lists the matching articles newest first.
+func (x *Index) Search(q Query) (Results, error) {
+	clauses, f, err := parse(q)
+	if err != nil {
+		return Results{}, err
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	offset := max(q.Offset, 0)
+
+	x.mu.Lock()
+	if x.sorted == nil {
+		x.sorted = make([]string, 0, len(x.postings))
+		for t := range x.postings {
+			x.sorted = append(x.sorted, t)
+		}
+		sort.Strings(x.sorted)
+	}
+	x.mu.Unlock()
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var positive []map[uint32]freqs
+	var negative []map[uint32]freqs
+	for _, c := range clauses {
+		m := x.match(c)
+		if c.negate {
+			negative = append(negative, m)
+		} else {
+			positive = append(positive, m)
+		}
+	}
+
+	// Candidates are the docs every positive clause matched, or all docs.
+	var candidates []uint32
+	if len(positive) == 0 {
+		for n := range x.docs {
+			candidates = append(candidates, n)
+		}
+	} else {
+		smallest := 0
+		for i, m := range positive {
+			if len(m) < len(positive[smallest]) {
+				smallest = i
+			}
+		}
+	next:
+		for n := range positive[smallest] {
+			for _, m := range positive {
+				if _, ok := m[n]; !ok {
+					continue next
+				}
+			}
+			candidates = append(candidates, n)
+		}
+	}
+
+	type scored struct {
+		n      uint32
+		score  float64
+		fields []string
+	}
+	var hits []scored
+	total := float64(len(x.docs))
+	var avgLen freqs
+	for fi := range avgLen {
+		if total > 0 {
+			avgLen[fi] = float64(x.totalLen[fi]) / total
+		}
+	}
+
+candidate:
+	for _, n := range candidates {
+		d := x.docs[n]
+		if !f.pass(d) {
+			continue
+		}
+		for _, m := range negative {
+			if _, ok := m[n]; ok {
+				continue candidate
+			}
+		}
+
+		s := scored{n: n}
+		var matched [numFields]bool
+		for _, m := range positive {
+			tf := m[n]
+			df := float64(len(m))
+			idf := math.Log(1 + (total-df+0.5)/(df+0.5))
+			var weighted float64
+			for fi, v := range tf {
+				if v == 0 {
+					continue
+				}
+				matched[fi] = true
+				norm := 1.0
+				if avgLen[fi] > 0 {
+					norm = 1 - bm25B + bm25B*float64(d.Len[fi])/avgLen[fi]
+				}
+				weighted += fieldWeights[fi] * v / norm
+			}
+			s.score += idf * weighted * (bm25K1 + 1) / (weighted + bm25K1)
+		}
+		for fi, ok := range matched {
+			if ok {
+				s.fields = append(s.fields, fieldNames[fi])
+			}
+		}
+		hits = append(hits, s)
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		di, dj := x.docs[hits[i].n], x.docs[hits[j].n]
+		if di.Published != dj.Published {
+			return di.Published > dj.Published
+		}
+		return models.ArticleKey(di.ID, di.URL) < models.ArticleKey(dj.ID, dj.URL)
+	})
+
+	res := Results{Total: len(hits), Offset: offset, Hits: []Hit{}}
+	for i := offset; i < len(hits) && i < offset+limit; i++ {
+		d := x.docs[hits[i].n]
+		h := Hit{
+			ID:     d.ID,
+			URL:    d.URL,
+			Title:  d.Title,
+			Source: d.Source,
+			Bias:   d.Bias,
+			Score:  math.Round(hits[i].score*1000) / 1000,
+			Fields: hits[i].fields,
+		}
+		if d.Published != 0 {
+			h.PublishedAt = time.Unix(d.Published, 0).UTC().Format(time.RFC3339)
+		}
+		res.Hits = append(res.Hits, h)
+	}
+	return res, nil
+}
+
+// match returns the docs matching a clause with their term frequencies.
+// Callers hold x.mu.
+func (x *Index) match(c clause) map[uint32]freqs {
+	out := map[uint32]freqs{}
+	add := func(n uint32, fi int, count int) {
+		if c.field >= 0 && fi != c.field || count == 0 {
+			return
+		}
+		tf := out[n]
+		tf[fi] += float64(count)
+		out[n] = tf
+	}
+
+	if len(c.words) == 1 {
+		for _, term := range x.expand(c.words[0], c.prefix) {
+			for n, p := range x.postings[term] {
+				for fi, pos := range p.Pos {
+					add(n, fi, len(pos))
+				}
+			}
+		}
+		return out
+	}
+
+	// Phrase: every word must follow the previous one in the same field.
+	last := len(c.words) - 1
+	lists := make([][]map[uint32]*Posting, len(c.words))
+	for i, w := range c.words {
+		for _, term := range x.expand(w, c.prefix && i == last) {
+			if p := x.postings[term]; p != nil {
+				lists[i] = append(lists[i], p)
+			}
+		}
+		if len(lists[i]) == 0 {
+			return out
+		}
+	}
+	for _, first := range lists[0] {
+		for n, p := range first {
+			for fi, starts := range p.Pos {
+				count := 0
+				for _, start := range starts {
+					if x.phraseAt(lists[1:], n, fi, start+1) {
+						count++
+					}
+				}
+				add(n, fi, count)
+			}
+		}
+	}
+	return out
+}
+
+// phraseAt reports whether the remaining phrase words occur in doc n, field
+// fi, from position pos on.
+func (x *Index) phraseAt(rest [][]map[uint32]*Posting, n uint32, fi int, pos uint32) bool {
+	if len(rest) == 0 {
+		return true
+	}
+	for _, p := range rest[0] {
+		post := p[n]
+		if post == nil {
+			continue
+		}
+		positions := post.Pos[fi]
+		i := sort.Search(len(positions), func(i int) bool { return positions[i] >= pos })
+		if i < len(positions) && positions[i] == pos && x.phraseAt(rest[1:], n, fi, pos+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// expand returns the indexed terms a word matches: itself, or with prefix
+// set every term starting with it. Callers hold x.mu.
+func (x *Index) expand(word string, prefix bool) []string {
+	if !prefix {
+		return []string{word}
+	}
+	var out []string
+	i := sort.SearchStrings(x.sorted, word)
+	for ; i < len(x.sorted) && strings.HasPrefix(x.sorted[i], word) && len(out) < maxExpansions; i++ {
+		out = append(out, x.sorted[i])
+	}
+	return out
+}
+
+// pass reports whether d meets the filters.
+func (f filters) pass(d *Doc) bool {
+	if len(f.sources) > 0 && !anyEqual(f.sources, strings.ToLower(d.Source), host(d.URL)) {
+		return false
+	}
+	if len(f.bias) > 0 && !anyEqual(f.bias, d.Bias) {
+		return false
+	}
+	if len(f.editions) > 0 && !anyEqual(f.editions, d.Edition) {
+		return false
+	}
+	if len(f.languages) > 0 && !anyEqual(f.languages, d.Language) {
+		return false
+	}
+	if !f.since.IsZero() && (d.Published == 0 || d.Published < f.since.Unix()) {
+		return false
+	}
+	if !f.until.IsZero() && (d.Published == 0 || d.Published > f.until.Unix()) {
+		return false
+	}
+	return true
+}
+
+// anyEqual reports whether one of the wanted values is among the values.
+func anyEqual(wanted []string, values ...string) bool {
+	for _, w := range wanted {
+		for _, v := range values {
+			if v != "" && v == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func host(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
@@ -0,0 +1,127 @@
+package search
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"nous-app/internal/models"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// testIndex returns an index holding a few articles about rates.
+func testIndex(t *testing.T) *Index {
+	t.Helper()
+	x := newIndex(filepath.Join(t.TempDir(), "search.index"))
+	us, uk := models.EditionUS, models.EditionUK
+	articles := []models.Article{
+		{
+			ID: "title", URL: "https://www.reuters.com/fed", Title: "Fed cuts interest rates",
+			Summary: ptr("The central bank moved."), Source: ptr("Reuters"), Edition: &us,
+			PublishedAt: ptr("2024-01-10T12:00:00Z"), Language: ptr("en"),
+		},
+		{
+			ID: "content", URL: "https://bbc.co.uk/markets", Title: "Markets update",
+			Content: ptr("<p>Stocks rose as traders bet on lower interest rates and the rates outlook.</p>"),
+			Source:  ptr("BBC"), Edition: &uk, PublishedAt: ptr("2024-01-12T08:00:00Z"), Language: ptr("en"),
+			SourceMeta: &models.SourceMeta{Name: "BBC", Bias: "Center"},
+		},
+		{
+			ID: "opinion", URL: "https://example.com/op", Title: "Opinion: rates are too high",
+			Tags: []string{"opinion", "economy"}, Author: ptr("Jane Smith"), Source: ptr("Example"),
+			PublishedAt: ptr("2024-01-05T00:00:00Z"), Language: ptr("de"),
+		},
+		{ID: "inflation", URL: "https://example.com/infl", Title: "Inflation slows", Source: ptr("Example")},
+	}
+	biasOf := func(source string) string {
+		if source == "Reuters" {
+			return "lean-left"
+		}
+		return ""
+	}
+	for _, a := range articles {
+		if !x.Put(a, biasOf) {
+			t.Fatalf("Put(%s) reported no change", a.ID)
+		}
+	}
+	return x
+}
+
+func hitIDs(res Results) []string {
+	ids := []string{}
+	for _, h := range res.Hits {
+		ids = append(ids, h.ID)
+	}
+	return ids
+}
+
+func TestSearch(t *testing.T) {
+	x := testIndex(t)
+	tests := []struct {
+		name string
+		q    Query
+		want []string
+	}{
+		// Matches in short titles beat repeated ones in a long content field
+		{"ranking", Query{Text: "rates"}, []string{"title", "opinion", "content"}},
+		{"all words", Query{Text: "interest rates"}, []string{"title", "content"}},
+		{"phrase", Query{Text: `"lower interest rates"`}, []string{"content"}},
+		{"phrase out of order", Query{Text: `"rates interest"`}, []string{}},
+		{"prefix", Query{Text: "infla*"}, []string{"inflation"}},
+		{"exclusion", Query{Text: "rates -opinion"}, []string{"title", "content"}},
+		{"field", Query{Text: "title:interest"}, []string{"title"}},
+		{"tag", Query{Text: "tag:economy"}, []string{"opinion"}},
+		{"author", Query{Text: "by:smith"}, []string{"opinion"}},
+		{"markup not indexed", Query{Text: "href"}, []string{}},
+		{"source name", Query{Text: "rates source:bbc"}, []string{"content"}},
+		{"source domain", Query{Text: "rates source:reuters.com"}, []string{"title"}},
+		{"bias from metadata", Query{Text: "rates", Bias: "center"}, []string{"content"}},
+		{"bias from lookup", Query{Text: "rates", Bias: "lean-left"}, []string{"title"}},
+		{"unknown bias", Query{Text: "rates bias:unknown"}, []string{"opinion"}},
+		{"edition", Query{Text: "rates edition:us"}, []string{"title"}},
+		{"language", Query{Text: "rates lang:de"}, []string{"opinion"}},
+		{"date range", Query{Text: "rates", Since: "2024-01-06", Until: "2024-01-10"}, []string{"title"}},
+		// Without words, filters list matches newest first
+		{"filters only", Query{Language: "en"}, []string{"content", "title"}},
+	}
+	for _, tt := range tests {
+		res, err := x.Search(tt.q)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := hitIDs(res); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: hits %v, want %v", tt.name, got, tt.want)
+		}
+		if res.Total != len(tt.want) {
+			t.Errorf("%s: total %d, want %d", tt.name, res.Total, len(tt.want))
+		}
+	}
+}
+
+func TestSearchHit(t *testing.T) {
+	x := testIndex(t)
+	res, err := x.Search(Query{Text: "rates", Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 3 || res.Offset != 1 || len(res.Hits) != 1 {
+		t.Fatalf("page = %+v, want the second of 3 hits", res)
+	}
+	h := res.Hits[0]
+	want := Hit{
+		ID: "opinion", URL: "https://example.com/op", Title: "Opinion: rates are too high", Source: "Example",
+		Bias: models.BiasUnknown, PublishedAt: "2024-01-05T00:00:00Z", Score: h.Score, Fields: []string{"title"},
+	}
+	if !reflect.DeepEqual(h, want) || h.Score <= 0 {
+		t.Errorf("hit = %+v, want %+v", h, want)
+	}
+
+	if res, _ := x.Search(Query{Text: "rates", Offset: 10}); res.Total != 3 || len(res.Hits) != 0 {
+		t.Errorf("page past the end = %+v", res)
+	}
+	if _, err := x.Search(Query{Text: "before:soon"}); err == nil {
+		t.Error("invalid date searched without error")
+	}
+}
@@ -0,0 +1,44 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// tagGap separates the positions of consecutive tags so a phrase never
+// matches across two of them.
+const tagGap = 16
+
+// tokens lower-cases text and splits it into words. Apostrophes inside a
+// word are dropped ("don't" → "dont") and HTML tags are skipped, so markup in
+// Content is not indexed.
+func tokens(text string) []string {
+	var out []string
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			out = append(out, b.String())
+			b.Reset()
+		}
+	}
+
+	runes := []rune(text)
+	inTag := false
+	for i, r := range runes {
+		switch {
+		case inTag:
+			inTag = r != '>'
+		case r == '<' && i+1 < len(runes) && (unicode.IsLetter(runes[i+1]) || runes[i+1] == '/' || runes[i+1] == '!'):
+			flush()
+			inTag = true
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		case r == '\'' || r == '’':
+			// Part of the word.
+		default:
+			flush()
+		}
+	}
+	flush()
+	return out
+}
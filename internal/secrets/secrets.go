@@ -0,0 +1,372 @@
+// Package secrets keeps API keys and tokens encrypted on disk, so data files
+// such as sources.json only hold references to them.
+//
+// Secrets are stored as one AES-256-GCM encrypted file. The key is either a
+// random file key kept next to it (readable by the user only, works the same
+// on every OS), or derived with scrypt from a passphrase the user enters once
+// per session; until then the store is locked.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+
+	"nous-app/internal/persist"
+)
+
+// Key sources.
+const (
+	ModeFile       = "file"
+	ModePassphrase = "passphrase"
+)
+
+// RefPrefix starts every secret reference.
+const RefPrefix = "secret:"
+
+// scrypt parameters for passphrase keys.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+	keyLen  = 32
+)
+
+// formatVersion is the layout of the store file.
+const formatVersion = 1
+
+var (
+	// ErrLocked is returned while a passphrase store has not been unlocked.
+	ErrLocked = errors.New("secret store is locked")
+	// ErrWrongPassphrase is returned by Unlock for a passphrase that does not
+	// decrypt the store.
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+)
+
+// envelope is the persisted form of the store.
+type envelope struct {
+	Version int    `json:"version"`
+	Mode    string `json:"mode"`
+	Salt    []byte `json:"salt,omitempty"` // scrypt salt in passphrase mode
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"` // Sealed JSON map of reference → secret
+}
+
+// Store holds secrets by reference. It is safe for concurrent use.
+type Store struct {
+	file    *persist.File
+	keyPath string
+
+	mu      sync.Mutex
+	mode    string
+	salt    []byte
+	key     []byte // nil while locked
+	secrets map[string]string
+	sealed  *envelope // Loaded but not yet decrypted
+}
+
+// Open loads the store at path. keyPath is the file key used in file mode,
+// created on first save. A passphrase store opens locked.
+func Open(path, keyPath string) (*Store, error) {
+	f := persist.NewFile(path)
+	f.Perm = 0600
+	s := &Store{file: f, keyPath: keyPath, mode: ModeFile, secrets: map[string]string{}}
+
+	data, _, err := f.Load(func(b []byte) error {
+		var env envelope
+		return json.Unmarshal(b, &env)
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return s, err
+	}
+	// Until decrypted the store stays locked, so it is never overwritten
+	s.mode, s.salt, s.sealed = env.Mode, env.Salt, &env
+	if env.Version != formatVersion {
+		return s, fmt.Errorf("secret store has format %d, want %d", env.Version, formatVersion)
+	}
+
+	if s.mode == ModePassphrase {
+		return s, nil
+	}
+	key, err := os.ReadFile(keyPath)
+	if err != nil {
+		return s, fmt.Errorf("failed to read secret key file: %w", err)
+	}
+	return s, s.open(key)
+}
+
+// open decrypts the sealed store with key and unlocks it.
+func (s *Store) open(key []byte) error {
+	plain, err := decrypt(key, s.sealed.Nonce, s.sealed.Data)
+	if err != nil {
+		return err
+	}
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return fmt.Errorf("invalid secret store: %w", err)
+	}
+	s.key, s.secrets, s.sealed = key, secrets, nil
+	return nil
+}
+
+// Mode returns ModeFile or ModePassphrase.
+func (s *Store) Mode() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mode
+}
+
+// Locked reports whether the store waits for its passphrase.
+func (s *Store) Locked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locked()
+}
+
+func (s *Store) locked() bool {
+	return s.sealed != nil
+}
+
+// Len returns the number of secrets, 0 while locked.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.secrets)
+}
+
+// Unlock decrypts a passphrase store. Unlocking an open store only checks
+// the passphrase.
+func (s *Store) Unlock(passphrase string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.mode != ModePassphrase {
+		return nil
+	}
+	key, err := deriveKey(passphrase, s.salt)
+	if err != nil {
+		return err
+	}
+	if !s.locked() {
+		if subtle.ConstantTimeCompare(key, s.key) != 1 {
+			return ErrWrongPassphrase
+		}
+		return nil
+	}
+	if err := s.open(key); err != nil {
+		return ErrWrongPassphrase
+	}
+	return nil
+}
+
+// SetPassphrase re-encrypts the store with a key derived from passphrase, or
+// with the file key when passphrase is empty.
+func (s *Store) SetPassphrase(passphrase string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.locked() {
+		return ErrLocked
+	}
+	if passphrase == "" {
+		key, err := s.fileKey()
+		if err != nil {
+			return err
+		}
+		return s.rekey(ModeFile, nil, key)
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	if err := s.rekey(ModePassphrase, salt, key); err != nil {
+		return err
+	}
+	// Backups sealed with the file key would still open without the
+	// passphrase, so drop them together with the key
+	if err := s.file.RemoveBackups(); err != nil {
+		return err
+	}
+	if err := os.Remove(s.keyPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// rekey saves the store sealed with key and only then switches to it, so a
+// failed save leaves the key that still opens the file on disk.
+func (s *Store) rekey(mode string, salt, key []byte) error {
+	if err := s.write(mode, salt, key); err != nil {
+		return err
+	}
+	s.mode, s.salt, s.key = mode, salt, key
+	return nil
+}
+
+// Get returns the secret a reference points to.
+func (s *Store) Get(ref string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locked() {
+		return "", false, ErrLocked
+	}
+	v, ok := s.secrets[ref]
+	return v, ok, nil
+}
+
+// Put stores value under a new reference and returns the reference.
+// Changing a secret means putting the new value and retaining only the
+// references still in use.
+func (s *Store) Put(value string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locked() {
+		return "", ErrLocked
+	}
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	ref := RefPrefix + hex.EncodeToString(b)
+	s.secrets[ref] = value
+	if err := s.save(); err != nil {
+		delete(s.secrets, ref)
+		return "", err
+	}
+	return ref, nil
+}
+
+// Retain deletes every secret whose reference keep does not hold.
+func (s *Store) Retain(keep map[string]bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locked() {
+		return ErrLocked
+	}
+	changed := false
+	for ref := range s.secrets {
+		if !keep[ref] {
+			delete(s.secrets, ref)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return s.save()
+}
+
+// save encrypts and writes the store; callers hold s.mu.
+func (s *Store) save() error {
+	if s.key == nil {
+		key, err := s.fileKey()
+		if err != nil {
+			return err
+		}
+		s.key = key
+	}
+	return s.write(s.mode, s.salt, s.key)
+}
+
+// write encrypts the secrets with key and writes them; callers hold s.mu.
+func (s *Store) write(mode string, salt, key []byte) error {
+	plain, err := json.Marshal(s.secrets)
+	if err != nil {
+		return err
+	}
+	nonce, sealed, err := encrypt(key, plain)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(envelope{Version: formatVersion, Mode: mode, Salt: salt, Nonce: nonce, Data: sealed})
+	if err != nil {
+		return err
+	}
+	return s.file.Save(data)
+}
+
+// fileKey reads the file key, creating it if missing.
+func (s *Store) fileKey() ([]byte, error) {
+	key, err := os.ReadFile(s.keyPath)
+	if err == nil && len(key) == keyLen {
+		return key, nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read secret key file: %w", err)
+	}
+	if err == nil {
+		return nil, fmt.Errorf("secret key file %s is damaged", s.keyPath)
+	}
+	key = make([]byte, keyLen)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := persist.WriteFile(s.keyPath, key, 0600); err != nil {
+		return nil, fmt.Errorf("failed to create secret key file: %w", err)
+	}
+	return key, nil
+}
+
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	if len(salt) == 0 {
+		return nil, errors.New("secret store has no salt")
+	}
+	return scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keyLen)
+}
+
+func encrypt(key, plain []byte) (nonce, sealed []byte, err error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return nonce, gcm.Seal(nil, nonce, plain, nil), nil
+}
+
+func decrypt(key, nonce, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid secret store nonce")
+	}
+	plain, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt secret store")
+	}
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
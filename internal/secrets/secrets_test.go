@@ -0,0 +1,211 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func openTemp(t *testing.T) (*Store, string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	path, keyPath := filepath.Join(dir, "secrets.json"), filepath.Join(dir, "secrets.key")
+	s, err := Open(path, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, path, keyPath
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestFileMode(t *testing.T) {
+	s, path, keyPath := openTemp(t)
+	if s.Mode() != ModeFile || s.Locked() || s.Len() != 0 {
+		t.Fatalf("new store: mode %s, locked %v, %d secrets", s.Mode(), s.Locked(), s.Len())
+	}
+
+	values := []string{"key-one", "key-two", ""}
+	refs := make([]string, len(values))
+	for i, v := range values {
+		ref, err := s.Put(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(ref, RefPrefix) {
+			t.Errorf("ref %q lacks %q", ref, RefPrefix)
+		}
+		refs[i] = ref
+	}
+	if refs[0] == refs[1] {
+		t.Error("two puts returned the same reference")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "key-one") {
+		t.Error("store file holds a secret in plaintext")
+	}
+	if info, err := os.Stat(keyPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("key file: %v, %v", info, err)
+	}
+
+	reopened, err := Open(path, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		ref  string
+		want string
+		ok   bool
+	}{
+		{refs[0], "key-one", true},
+		{refs[1], "key-two", true},
+		{refs[2], "", true},
+		{RefPrefix + "0000000000000000", "", false},
+	}
+	for _, tt := range tests {
+		got, ok, err := reopened.Get(tt.ref)
+		if err != nil || ok != tt.ok || got != tt.want {
+			t.Errorf("Get(%s) = %q, %v, %v; want %q, %v", tt.ref, got, ok, err, tt.want, tt.ok)
+		}
+	}
+
+	if err := reopened.Retain(map[string]bool{refs[1]: true}); err != nil {
+		t.Fatal(err)
+	}
+	if reopened.Len() != 1 {
+		t.Errorf("Len() = %d after Retain, want 1", reopened.Len())
+	}
+	if _, ok, _ := reopened.Get(refs[0]); ok {
+		t.Error("Retain kept a reference it was not given")
+	}
+}
+
+func TestOpenMissingKeyFile(t *testing.T) {
+	s, path, keyPath := openTemp(t)
+	if _, err := s.Put("key"); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(keyPath)
+
+	s, err := Open(path, keyPath)
+	if err == nil {
+		t.Fatal("opened a file store without its key file")
+	}
+	if !s.Locked() {
+		t.Error("store without key file is not locked")
+	}
+	if _, err := s.Put("other"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Put on an unreadable store: %v, want ErrLocked", err)
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	s, path, keyPath := openTemp(t)
+	ref, err := s.Put("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Put("another") // leaves a backup sealed with the file key
+	if !exists(path + ".1") {
+		t.Fatal("no backup to check")
+	}
+
+	if err := s.SetPassphrase("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	if s.Mode() != ModePassphrase || s.Locked() {
+		t.Errorf("after SetPassphrase: mode %s, locked %v", s.Mode(), s.Locked())
+	}
+	if exists(keyPath) || exists(path+".1") {
+		t.Error("key file or backups sealed with it survived switching to a passphrase")
+	}
+
+	locked, err := Open(path, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !locked.Locked() {
+		t.Fatal("passphrase store opened unlocked")
+	}
+	if _, _, err := locked.Get(ref); !errors.Is(err, ErrLocked) {
+		t.Errorf("Get while locked: %v, want ErrLocked", err)
+	}
+	if _, err := locked.Put("new"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Put while locked: %v, want ErrLocked", err)
+	}
+	if err := locked.Retain(nil); !errors.Is(err, ErrLocked) {
+		t.Errorf("Retain while locked: %v, want ErrLocked", err)
+	}
+
+	if err := locked.Unlock("wrong horse"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Unlock with a wrong passphrase: %v", err)
+	}
+	if !locked.Locked() {
+		t.Error("wrong passphrase unlocked the store")
+	}
+	if err := locked.Unlock("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := locked.Get(ref); err != nil || !ok || v != "key" {
+		t.Errorf("Get after Unlock = %q, %v, %v", v, ok, err)
+	}
+	// Unlocking an open store only checks the passphrase
+	if err := locked.Unlock("wrong horse"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Unlock of an open store with a wrong passphrase: %v", err)
+	}
+
+	// Back to a file key
+	if err := locked.SetPassphrase(""); err != nil {
+		t.Fatal(err)
+	}
+	if !exists(keyPath) {
+		t.Error("no key file after removing the passphrase")
+	}
+	reopened, err := Open(path, keyPath)
+	if err != nil || reopened.Locked() {
+		t.Fatalf("file store after removing the passphrase: %v, locked %v", err, reopened.Locked())
+	}
+	if v, _, _ := reopened.Get(ref); v != "key" {
+		t.Errorf("Get = %q after switching back, want key", v)
+	}
+}
+
+func TestSetPassphraseFailedSave(t *testing.T) {
+	s, path, keyPath := openTemp(t)
+	ref, err := s.Put("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Non-empty directories in the way of the backup rotation make Save fail
+	for _, backup := range []string{path + ".2", path + ".3"} {
+		if err := os.Mkdir(backup, 0755); err != nil {
+			t.Fatal(err)
+		}
+		os.WriteFile(filepath.Join(backup, "x"), nil, 0644)
+	}
+	if err := s.SetPassphrase("correct horse"); err == nil || !strings.Contains(err.Error(), "failed to back up") {
+		t.Fatalf("SetPassphrase with a failing save: %v", err)
+	}
+	if s.Mode() != ModeFile || !exists(keyPath) {
+		t.Errorf("failed SetPassphrase switched to %s, key file present %v", s.Mode(), exists(keyPath))
+	}
+
+	// The store still matches the file on disk
+	reopened, err := Open(path, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _, _ := reopened.Get(ref); v != "key" {
+		t.Errorf("Get = %q after the failed switch, want key", v)
+	}
+}
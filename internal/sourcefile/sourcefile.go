@@ -0,0 +1,165 @@
+// Package sourcefile reads, migrates, validates and writes sources.json.
+//
+// The file is a versioned document:
+//
+//	{"version": 2, "sources": [ ... ]}
+//
+// Version 1 is the original layout, a bare array of sources. Older layouts
+// are migrated forward step by step when read; files written by a newer
+// version of the app are refused rather than rewritten without the fields
+// this version does not know.
+package sourcefile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"nous-app/internal/cluster"
+	"nous-app/internal/models"
+)
+
+// Version is the layout written by Encode.
+const Version = 2
+
+// ErrNewerVersion is returned by Decode for a file written by a newer app.
+var ErrNewerVersion = errors.New("sources file was written by a newer version of the app")
+
+// document is the persisted layout of the current version.
+type document struct {
+	Version int             `json:"version"`
+	Sources []models.Source `json:"sources"`
+}
+
+// migrations[v] upgrades a raw document of version v to version v+1.
+var migrations = map[int]func(doc map[string]interface{}) error{
+	1: migrateV1,
+}
+
+// Decode reads a sources file of any supported version. It returns the
+// sources and the version the file had, so callers know to rewrite it.
+func Decode(data []byte) ([]models.Source, int, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, Version, nil
+	}
+
+	var doc map[string]interface{}
+	if data[0] == '[' {
+		// Version 1: a bare array
+		var list []interface{}
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, 0, fmt.Errorf("invalid sources file: %w", err)
+		}
+		doc = map[string]interface{}{"version": float64(1), "sources": list}
+	} else if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, fmt.Errorf("invalid sources file: %w", err)
+	}
+
+	v, ok := doc["version"].(float64)
+	if !ok || v < 1 || v != float64(int(v)) {
+		return nil, 0, fmt.Errorf("invalid sources file: missing or invalid version")
+	}
+	from := int(v)
+	if from > Version {
+		return nil, from, fmt.Errorf("%w (version %d, this app reads up to %d)", ErrNewerVersion, from, Version)
+	}
+
+	for ver := from; ver < Version; ver++ {
+		if err := migrations[ver](doc); err != nil {
+			return nil, from, fmt.Errorf("failed to migrate sources file from version %d: %w", ver, err)
+		}
+		doc["version"] = float64(ver + 1)
+	}
+
+	// Round-trip through JSON to decode the migrated document into Sources
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, from, err
+	}
+	var out document
+	if err := json.Unmarshal(migrated, &out); err != nil {
+		return nil, from, fmt.Errorf("invalid sources file: %w", err)
+	}
+	return out.Sources, from, nil
+}
+
+// Encode writes sources in the current layout.
+func Encode(sources []models.Source) ([]byte, error) {
+	if sources == nil {
+		sources = []models.Source{}
+	}
+	return json.Marshal(document{Version: Version, Sources: sources})
+}
+
+// migrateV1 cleans up what older frontends stored in the bare array, which
+// Decode has already wrapped: "url" and "requiresKey" instead of "endpoint"
+// and "requiresApiKey", and free-form bias labels such as "Lean Left".
+func migrateV1(doc map[string]interface{}) error {
+	list, ok := doc["sources"].([]interface{})
+	if !ok {
+		return fmt.Errorf("sources is not a list")
+	}
+	for i, item := range list {
+		src, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("source %d is not an object", i)
+		}
+		rename(src, "url", "endpoint")
+		rename(src, "requiresKey", "requiresApiKey")
+		if bias, ok := src["bias"].(string); ok && bias != "" {
+			src["bias"] = cluster.NormalizeBias(bias)
+		}
+	}
+	return nil
+}
+
+// rename moves src[from] to src[to] unless to is already set.
+func rename(src map[string]interface{}, from, to string) {
+	v, ok := src[from]
+	if !ok {
+		return
+	}
+	delete(src, from)
+	if cur, exists := src[to]; !exists || cur == "" || cur == nil {
+		src[to] = v
+	}
+}
+
+// Changed reports whether a and b differ in anything but LastUpdated and
+// LastFetched, the bookkeeping fields the app maintains itself.
+func Changed(a, b models.Source) bool {
+	a.LastUpdated, b.LastUpdated = nil, nil
+	a.LastFetched, b.LastFetched = nil, nil
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return !bytes.Equal(ja, jb)
+}
+
+// key is how sources are matched between saves: by name, case-insensitively.
+func key(s models.Source) string {
+	return strings.ToLower(strings.TrimSpace(s.Name))
+}
+
+// Stamp sets LastUpdated to now on the sources that are new or changed
+// compared with previous, and keeps the previous LastUpdated on the others.
+// A source sent without LastFetched keeps its previous one.
+func Stamp(sources, previous []models.Source, now string) {
+	prev := make(map[string]models.Source, len(previous))
+	for _, p := range previous {
+		prev[key(p)] = p
+	}
+	for i := range sources {
+		p, ok := prev[key(sources[i])]
+		if ok && sources[i].LastFetched == nil {
+			sources[i].LastFetched = p.LastFetched
+		}
+		if !ok || Changed(sources[i], p) {
+			sources[i].LastUpdated = &now
+		} else {
+			sources[i].LastUpdated = p.LastUpdated
+		}
+	}
+}
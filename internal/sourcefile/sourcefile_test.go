@@ -0,0 +1,123 @@
+package sourcefile
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"nous-app/internal/models"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestDecodeV1(t *testing.T) {
+	data := []byte(`[
+		{"name": "Old", "url": "https://old.example/rss", "requiresKey": true, "bias": "Lean Left", "parser": "rss"},
+		{"name": "Both", "url": "https://ignored.example", "endpoint": "https://kept.example/feed", "bias": ""}
+	]`)
+	sources, version, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 1 {
+		t.Errorf("version = %d, want 1", version)
+	}
+	want := []models.Source{
+		{Name: "Old", Endpoint: "https://old.example/rss", RequiresAPIKey: ptr(true), Bias: models.BiasLeanLeft, Parser: "rss"},
+		{Name: "Both", Endpoint: "https://kept.example/feed"},
+	}
+	if !reflect.DeepEqual(sources, want) {
+		t.Errorf("Decode =\n%+v\nwant\n%+v", sources, want)
+	}
+}
+
+func TestDecodeCurrent(t *testing.T) {
+	sources := []models.Source{{Name: "A", Endpoint: "https://a.example/rss", Enabled: ptr(false)}}
+	data, err := Encode(sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, version, err := Decode(data)
+	if err != nil || version != Version || !reflect.DeepEqual(got, sources) {
+		t.Errorf("Decode(Encode()) = %+v, %d, %v", got, version, err)
+	}
+
+	// An empty file is an empty list in the current layout
+	if got, version, err := Decode([]byte(" \n")); err != nil || got != nil || version != Version {
+		t.Errorf("Decode of an empty file = %v, %d, %v", got, version, err)
+	}
+	if data, _ := Encode(nil); string(data) != `{"version":2,"sources":[]}` {
+		t.Errorf("Encode(nil) = %s", data)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	_, version, err := Decode([]byte(`{"version": 99, "sources": []}`))
+	if !errors.Is(err, ErrNewerVersion) || version != 99 {
+		t.Errorf("Decode of a newer file = %d, %v; want 99, ErrNewerVersion", version, err)
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not json", "sources"},
+		{"broken array", `[{"name": "A"`},
+		{"no version", `{"sources": []}`},
+		{"zero version", `{"version": 0, "sources": []}`},
+		{"fractional version", `{"version": 1.5, "sources": []}`},
+		{"v1 item not an object", `["https://a.example/rss"]`},
+		{"wrong field type", `{"version": 2, "sources": [{"name": 1}]}`},
+	}
+	for _, tt := range tests {
+		if _, _, err := Decode([]byte(tt.data)); err == nil || errors.Is(err, ErrNewerVersion) {
+			t.Errorf("%s: err = %v", tt.name, err)
+		}
+	}
+}
+
+func TestStamp(t *testing.T) {
+	earlier, fetched := "2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z"
+	now := "2024-02-01T00:00:00Z"
+	previous := []models.Source{
+		{Name: "Same", Endpoint: "https://same.example", LastUpdated: &earlier, LastFetched: &fetched},
+		{Name: "Edited", Endpoint: "https://edited.example", LastUpdated: &earlier},
+	}
+	sources := []models.Source{
+		{Name: "Same", Endpoint: "https://same.example"},
+		{Name: "Edited", Endpoint: "https://edited.example/v2"},
+		{Name: "New", Endpoint: "https://new.example"},
+	}
+	Stamp(sources, previous, now)
+
+	tests := []struct {
+		name    string
+		updated string
+		fetched *string
+	}{
+		{"Same", earlier, &fetched},
+		{"Edited", now, nil},
+		{"New", now, nil},
+	}
+	for i, tt := range tests {
+		s := sources[i]
+		if s.LastUpdated == nil || *s.LastUpdated != tt.updated {
+			t.Errorf("%s: LastUpdated = %v, want %s", tt.name, s.LastUpdated, tt.updated)
+		}
+		if !reflect.DeepEqual(s.LastFetched, tt.fetched) {
+			t.Errorf("%s: LastFetched = %v, want %v", tt.name, s.LastFetched, tt.fetched)
+		}
+	}
+}
+
+func TestChanged(t *testing.T) {
+	a := models.Source{Name: "A", Endpoint: "https://a.example", LastUpdated: ptr("x"), LastFetched: ptr("y")}
+	b := models.Source{Name: "A", Endpoint: "https://a.example"}
+	if Changed(a, b) {
+		t.Error("bookkeeping fields count as a change")
+	}
+	b.Pinned = ptr(true)
+	if !Changed(a, b) {
+		t.Error("Pinned does not count as a change")
+	}
+}
@@ -0,0 +1,115 @@
+package sourcefile
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"nous-app/internal/fetcher"
+	"nous-app/internal/models"
+	"nous-app/internal/parser"
+)
+
+// Parsers and Normalizers are the names a Source may use, as known to the
+// frontend. Parsers registered in Go (see parser.Register) are accepted too.
+var (
+	Parsers     = []string{"json", "jsonfeed", "rss", "gdelt", "html", "hn", "reddit"}
+	Normalizers = []string{"json", "jsonfeed", "rss", "gdelt", "hn", "reddit"}
+)
+
+// AuthTypes are the values Source.AuthType may take; empty means none.
+var AuthTypes = []string{
+	fetcher.AuthNone, fetcher.AuthAPIKey, fetcher.AuthBearerToken,
+	fetcher.AuthOAuth, fetcher.AuthBasic,
+}
+
+// Biases are the values Source.Bias may take; empty means not rated.
+var Biases = []string{
+	models.BiasLeft, models.BiasLeanLeft, models.BiasCenter,
+	models.BiasLeanRight, models.BiasRight, models.BiasUnknown,
+}
+
+// Issue is one validation error of a source.
+type Issue struct {
+	Index   int    `json:"index"`  // Position of the source in the list
+	Source  string `json:"source"` // Its name, for display
+	Field   string `json:"field"`  // JSON name of the offending field
+	Message string `json:"message"`
+}
+
+func (i Issue) Error() string {
+	return fmt.Sprintf("source %d (%s): %s: %s", i.Index, i.Source, i.Field, i.Message)
+}
+
+// Validate checks every source and returns all issues found, or nil.
+func Validate(sources []models.Source) []Issue {
+	var issues []Issue
+	names := map[string]int{}
+	for i, s := range sources {
+		add := func(field, format string, args ...interface{}) {
+			issues = append(issues, Issue{Index: i, Source: s.Name, Field: field, Message: fmt.Sprintf(format, args...)})
+		}
+
+		if strings.TrimSpace(s.Name) == "" {
+			add("name", "is required")
+		} else if first, dup := names[key(s)]; dup {
+			add("name", "is already used by source %d", first)
+		} else {
+			names[key(s)] = i
+		}
+
+		if err := checkURL(s.Endpoint); err != "" {
+			add("endpoint", "%s", err)
+		}
+		if s.APILink != nil && *s.APILink != "" {
+			if err := checkURL(*s.APILink); err != "" {
+				add("apiLink", "%s", err)
+			}
+		}
+
+		if s.Parser != "" && !slices.Contains(Parsers, s.Parser) {
+			if _, ok := parser.Get(s.Parser); !ok {
+				add("parser", "unknown parser %q", s.Parser)
+			}
+		}
+		if s.Parser == "html" && (s.Scrape == nil || s.Scrape.Item == "") {
+			add("scrape", "the html parser needs an item selector")
+		}
+		if s.Normalizer != "" && !slices.Contains(Normalizers, s.Normalizer) {
+			add("normalizer", "unknown normalizer %q", s.Normalizer)
+		}
+
+		if s.AuthType != nil && *s.AuthType != "" && !slices.Contains(AuthTypes, *s.AuthType) {
+			add("authType", "unknown auth type %q", *s.AuthType)
+		}
+		if s.Bias != "" && !slices.Contains(Biases, s.Bias) {
+			add("bias", "must be one of %s", strings.Join(Biases, ", "))
+		}
+		if s.Confidence != nil && (*s.Confidence < 0 || *s.Confidence > 1) {
+			add("confidence", "must be between 0 and 1")
+		}
+		if s.RateLimitPerMin != nil && *s.RateLimitPerMin < 0 {
+			add("rateLimitPerMinute", "must not be negative")
+		}
+		if s.RefreshInterval != nil && *s.RefreshInterval < 0 {
+			add("refreshIntervalMinutes", "must not be negative")
+		}
+	}
+	return issues
+}
+
+// checkURL returns why raw is not an absolute http(s) URL, or "".
+func checkURL(raw string) string {
+	if strings.TrimSpace(raw) == "" {
+		return "is required"
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Sprintf("%q is not an absolute URL", raw)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Sprintf("%q must use http or https", raw)
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package sourcefile
+
+import (
+	"testing"
+
+	"nous-app/internal/models"
+)
+
+func TestValidate(t *testing.T) {
+	valid := models.Source{Name: "Valid", Endpoint: "https://valid.example/rss", Parser: "rss", Normalizer: "rss"}
+	tests := []struct {
+		name  string
+		src   models.Source
+		field string // "" for a valid source
+		msg   string
+	}{
+		{"valid", valid, "", ""},
+		{"html with rules", models.Source{Name: "H", Endpoint: "http://h.example/", Parser: "html",
+			Scrape: &models.ScrapeRules{Item: "article"}}, "", ""},
+		{"no name", models.Source{Name: " ", Endpoint: valid.Endpoint}, "name", "is required"},
+		{"duplicate name", models.Source{Name: "VALID", Endpoint: valid.Endpoint}, "name", "is already used by source 0"},
+		{"no endpoint", models.Source{Name: "E"}, "endpoint", "is required"},
+		{"relative endpoint", models.Source{Name: "E", Endpoint: "/feed"}, "endpoint", `"/feed" is not an absolute URL`},
+		{"feed scheme", models.Source{Name: "E", Endpoint: "feed://e.example/rss"}, "endpoint", `"feed://e.example/rss" must use http or https`},
+		{"bad api link", models.Source{Name: "L", Endpoint: valid.Endpoint, APILink: ptr("example.com/keys")}, "apiLink", `"example.com/keys" is not an absolute URL`},
+		{"unknown parser", models.Source{Name: "P", Endpoint: valid.Endpoint, Parser: "yaml"}, "parser", `unknown parser "yaml"`},
+		{"html without rules", models.Source{Name: "H", Endpoint: valid.Endpoint, Parser: "html"}, "scrape", "the html parser needs an item selector"},
+		{"unknown normalizer", models.Source{Name: "N", Endpoint: valid.Endpoint, Normalizer: "html"}, "normalizer", `unknown normalizer "html"`},
+		{"digest auth", models.Source{Name: "D", Endpoint: valid.Endpoint, AuthType: ptr("digestAuth")}, "authType", `unknown auth type "digestAuth"`},
+		{"bias label", models.Source{Name: "B", Endpoint: valid.Endpoint, Bias: "Lean Left"}, "bias", "must be one of left, lean-left, center, lean-right, right, unknown"},
+		{"confidence", models.Source{Name: "C", Endpoint: valid.Endpoint, Confidence: ptr(1.5)}, "confidence", "must be between 0 and 1"},
+		{"rate limit", models.Source{Name: "R", Endpoint: valid.Endpoint, RateLimitPerMin: ptr(-1)}, "rateLimitPerMinute", "must not be negative"},
+		{"refresh interval", models.Source{Name: "I", Endpoint: valid.Endpoint, RefreshInterval: ptr(-5)}, "refreshIntervalMinutes", "must not be negative"},
+	}
+	for _, tt := range tests {
+		// The valid source goes first so the duplicate has something to clash with
+		sources := []models.Source{valid, tt.src}
+		if tt.name == "valid" {
+			sources = sources[:1]
+		}
+		issues := Validate(sources)
+		if tt.field == "" {
+			if issues != nil {
+				t.Errorf("%s: unexpected issues %v", tt.name, issues)
+			}
+			continue
+		}
+		if len(issues) != 1 {
+			t.Errorf("%s: got %d issues %v, want 1", tt.name, len(issues), issues)
+			continue
+		}
+		got := issues[0]
+		if got.Index != 1 || got.Source != tt.src.Name || got.Field != tt.field || got.Message != tt.msg {
+			t.Errorf("%s: got %+v, want %s: %s at index 1", tt.name, got, tt.field, tt.msg)
+		}
+	}
+}
+
+func TestValidateCollectsAll(t *testing.T) {
+	issues := Validate([]models.Source{{Parser: "html", Confidence: ptr(-1.0)}})
+	fields := map[string]bool{}
+	for _, issue := range issues {
+		fields[issue.Field] = true
+	}
+	for _, f := range []string{"name", "endpoint", "scrape", "confidence"} {
+		if !fields[f] {
+			t.Errorf("no %s issue in %v", f, issues)
+		}
+	}
+	if want := `source 0 (): name: is required`; issues[0].Error() != want {
+		t.Errorf("Error() = %q, want %q", issues[0].Error(), want)
+	}
+}